@@ -2,258 +2,572 @@
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
 
+// Package archive implements the MCP tools for listing and extracting files
+// from archives. It is the only implementation of these tools: every path
+// passed by a client is confined to the working directory roots by
+// secureArchivePath before an archive is opened. ListReader and
+// ExtractReader are the sole exception, as they read an archive already
+// opened by the embedding program rather than a client-supplied path.
 package archive
 
 import (
-	"archive/tar"
-	"archive/zip"
-	"compress/bzip2"
-	"compress/gzip"
+	"cmp"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
-	"log/slog"
-	"os"
-	"path/filepath"
-	"regexp"
+	"io/fs"
+	"path"
+	"slices"
 	"strings"
+	"time"
 
-	"github.com/cavaliergopher/cpio"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
-	"github.com/ulikunitz/xz"
+	"golang.org/x/text/encoding"
 )
 
 // Archive holds the configuration for the archive tools.
 type Archive struct {
 	maxSize int64
+	// Workdir is the first of the working directory roots.
 	Workdir string
+	roots   []string
+	deny    []string
+	timeout time.Duration
+	// entryTimeout limits reading the content of a single entry.
+	entryTimeout time.Duration
+	// writable enables tools that modify the filesystem.
+	writable      bool
+	decoderLimits decoderLimits
+	// maxGlobArchives is the maximum number of archives a pattern passed
+	// to ListGlob may match.
+	maxGlobArchives int
+	// maxBundleSize is the maximum size of a zip bundle of extracted
+	// files.
+	maxBundleSize int64
+	// maxResponseBytes caps the estimated size of an extraction result.
+	maxResponseBytes int64
+	// maxNestedSize caps the bytes of nested archives copied to scratch
+	// files.
+	maxNestedSize int64
+	// tempDir is the directory for scratch files, the system one if empty.
+	tempDir string
+	// mmap enables memory mapping large zip archives.
+	mmap bool
+	// tolerant skips junk bytes preceding the magic of an archive.
+	tolerant bool
+	// strictExtension refuses archives whose magic does not match their
+	// extension.
+	strictExtension bool
+	// readBufferSize is the size of the buffers around the archive file
+	// and the decompressor output of compressed streams. Zero leaves the
+	// reads unbuffered.
+	readBufferSize int
+	// parallelThreshold is the number of entries below which per-entry
+	// work runs sequentially.
+	parallelThreshold int
+	// maxEntries and maxNameLength cap the entries scanned per archive.
+	maxEntries    int
+	maxNameLength int
+	// maxPathDepth caps the number of path segments of entry names.
+	maxPathDepth int
+	// ignores holds the ignore file of each root.
+	ignores map[string]*ignoreFile
+	// slots limits the concurrent archive operations to maxConcurrent if
+	// it is positive.
+	maxConcurrent int
+	slots         chan struct{}
+	// autoUnwrap enables removing up to maxAutoUnwrap extra compression
+	// layers from compressed stream formats.
+	autoUnwrap bool
+	// permissionFormat is the format of FileInfo.Permissions.
+	permissionFormat string
+	// symlinkPolicy controls how symlink entries are handled.
+	symlinkPolicy string
+	// filenameCharset names the encoding of entry names that are not
+	// UTF-8, resolved to filenameEncoding by New.
+	filenameCharset  string
+	filenameEncoding encoding.Encoding
+	// formatNames are the formats enabled by WithFormats, resolved to
+	// enabledFormats by New. A nil enabledFormats enables all formats.
+	formatNames    []string
+	enabledFormats map[string]bool
+	// metrics is nil unless enabled by WithMetrics.
+	metrics *Metrics
 }
 
-// New creates a new Archive instance.
-func New(workdir string) (*Archive, error) {
-	absWorkdir, err := filepath.Abs(workdir)
+// ErrReadOnly is returned by write-capable tools if the Archive was not
+// created with WithWritable.
+var ErrReadOnly = errors.New("server is read-only")
+
+// checkWritable must be called first by every write-capable tool.
+func (a *Archive) checkWritable() error {
+	if !a.writable {
+		return ErrReadOnly
+	}
+	return nil
+}
+
+// New creates a new Archive instance. The workdir may hold several
+// directories separated by the OS path list separator, each of which may be
+// a glob pattern that is expanded once at startup.
+func New(workdir string, opts ...Option) (*Archive, error) {
+	a := &Archive{
+		maxSize:           100 * 1024,
+		decoderLimits:     defaultDecoderLimits,
+		maxGlobArchives:   defaultMaxGlobArchives,
+		maxBundleSize:     defaultMaxBundleSize,
+		maxResponseBytes:  defaultMaxResponseBytes,
+		maxNestedSize:     defaultMaxNestedSize,
+		maxEntries:        defaultMaxEntries,
+		maxNameLength:     defaultMaxNameLength,
+		maxPathDepth:      defaultMaxPathDepth,
+		permissionFormat:  PermissionsSymbolic,
+		symlinkPolicy:     SymlinksReport,
+		parallelThreshold: defaultParallelThreshold,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	if !validPermissionFormat(a.permissionFormat) {
+		return nil, fmt.Errorf("invalid permission format %q, must be %q or %q", a.permissionFormat, PermissionsSymbolic, PermissionsOctal)
+	}
+	if !validSymlinkPolicy(a.symlinkPolicy) {
+		return nil, fmt.Errorf("invalid symlink policy %q, must be %q, %q or %q", a.symlinkPolicy, SymlinksReport, SymlinksSkip, SymlinksReject)
+	}
+	if a.filenameCharset != "" {
+		enc, err := lookupCharset(a.filenameCharset)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filename encoding: %w", err)
+		}
+		a.filenameEncoding = enc
+	}
+	if a.tempDir != "" {
+		if err := checkTempDir(a.tempDir); err != nil {
+			return nil, err
+		}
+	}
+	if len(a.formatNames) > 0 {
+		a.enabledFormats = make(map[string]bool)
+		for _, name := range a.formatNames {
+			if _, ok := formatByName(name); !ok {
+				return nil, fmt.Errorf("unknown archive format %q", name)
+			}
+			a.enabledFormats[name] = true
+		}
+	}
+
+	roots, err := expandRoots(workdir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get absolute path for workdir: %w", err)
+		return nil, err
 	}
-	return &Archive{
-		maxSize: 100 * 1024,
-		Workdir: absWorkdir,
-	}, nil
+	a.roots = roots
+	a.Workdir = roots[0]
+
+	if a.maxConcurrent > 0 {
+		a.slots = make(chan struct{}, a.maxConcurrent)
+	}
+
+	a.ignores = make(map[string]*ignoreFile)
+	for _, root := range roots {
+		if a.ignores[root], err = newIgnoreFile(root); err != nil {
+			return nil, err
+		}
+	}
+
+	if a.deny, err = expandDeny(a.deny); err != nil {
+		return nil, err
+	}
+	return a, nil
 }
 
 // FileInfo represents a file in an archive.
 type FileInfo struct {
-	Name        string `json:"name"`
+	Name string `json:"name"`
+	// Size is the uncompressed size of the entry. Sizes of zip64 entries
+	// beyond 4 GiB are reported in full. Extracting an entry larger than
+	// the maximum file size is refused, but a byte range of it can be
+	// extracted.
 	Size        int64  `json:"size"`
 	Permissions string `json:"permissions"`
+	// Type is one of "file", "dir", "symlink", "device" for block
+	// devices, "char" for character devices, "fifo" or "other".
+	Type string `json:"type"`
+	// Archive is the path of the archive holding the entry when listing
+	// several archives at once.
+	Archive string `json:"archive,omitempty"`
+	// DataOffset and CompressedSize locate the entry's data inside the
+	// archive file. They are only set for zip archives.
+	DataOffset     int64 `json:"data_offset,omitempty"`
+	CompressedSize int64 `json:"compressed_size,omitempty"`
+	// CRC32 is the checksum of the content stored in zip archives.
+	CRC32 uint32 `json:"crc32,omitempty"`
+	// Xattrs are the extended attributes and ACLs stored in tar PAX
+	// records. They are only set if IncludeXattrs was requested.
+	Xattrs map[string]string `json:"xattrs,omitempty"`
+	// Device holds the numbers of device nodes of tar archives. The cpio
+	// reader does not provide them.
+	Device *Device `json:"device,omitempty"`
+	// UID and GID are the numeric owner and group of tar and cpio
+	// entries.
+	UID *int `json:"uid,omitempty"`
+	GID *int `json:"gid,omitempty"`
+	// Uname and Gname are the owner and group names stored in tar
+	// headers, which are more meaningful across systems than the numeric
+	// ids. They are empty if the archive does not store them.
+	Uname string `json:"uname,omitempty"`
+	Gname string `json:"gname,omitempty"`
+	// LinkTarget is the target of a symlink.
+	LinkTarget string `json:"link_target,omitempty"`
 }
 
-// ListArchiveFilesArgs are the arguments for the list_archive_files tool.
-type ListArchiveFilesArgs struct {
+// Device holds the major and minor number of a device node.
+type Device struct {
+	Major int64 `json:"major"`
+	Minor int64 `json:"minor"`
+}
+
+// ListOptions are the options for listing the files in an archive.
+type ListOptions struct {
 	Path           string `json:"path" jsonschema:"the path to the archive"`
 	Depth          int    `json:"depth" jsonschema:"the depth of the directory tree to list. 0 means the complete directory tree"`
 	Limit          int    `json:"limit,omitempty" jsonschema:"the maximum number of files to display. If not set, it will default to 100"`
-	IncludePattern string `json:"include,omitempty" jsonschema:"an optional regular expression to include files"`
-	ExcludePattern string `json:"exclude,omitempty" jsonschema:"an optional regular expression to exclude files"`
+	IncludePattern string `json:"include,omitempty" jsonschema:"an optional regular expression, or glob with pattern_syntax glob, to include files"`
+	ExcludePattern string `json:"exclude,omitempty" jsonschema:"an optional regular expression, or glob with pattern_syntax glob, to exclude files"`
+	// PatternSyntax selects how IncludePattern and ExcludePattern are
+	// interpreted: "regexp", the default, or "glob" for path.Match
+	// patterns like tar --wildcards.
+	PatternSyntax string `json:"pattern_syntax,omitempty" jsonschema:"the syntax of include and exclude: regexp or glob. With glob, a pattern without a slash such as *.log matches the base name. If not set, it will default to regexp"`
+	// TypeFilter restricts the listing to files or directories. For "dir",
+	// directories that are only implied by the paths of other entries are
+	// listed as well.
+	TypeFilter string `json:"type,omitempty" jsonschema:"list only entries of this type: file or dir. If not set, all entries are listed"`
+	// Deduplicate keeps only the last occurrence of entries that appear
+	// several times in the archive, matching tar extraction semantics.
+	// Without it, the full list including duplicates is returned.
+	Deduplicate bool `json:"deduplicate,omitempty" jsonschema:"keep only the last occurrence of entries that appear more than once"`
+	// IncludeArchiveHash adds the SHA256 of the archive file itself to the
+	// result.
+	IncludeArchiveHash bool `json:"include_archive_hash,omitempty" jsonschema:"include the SHA256 of the archive file in the result"`
+	// IncludeXattrs adds the extended attributes and ACLs of tar entries,
+	// such as SELinux labels, to the listed files.
+	IncludeXattrs bool `json:"include_xattrs,omitempty" jsonschema:"include extended attributes and ACLs stored in tar PAX records"`
+	// TopN selects the N largest of the filtered entries, listed by
+	// descending size, before the limit is applied.
+	TopN int `json:"top_n,omitempty" jsonschema:"list only the N largest of the filtered entries, largest first"`
+	// NamesOnly returns the names of the displayed entries in
+	// ListArchiveFilesResult.Names instead of their metadata in Files,
+	// for a much smaller response.
+	NamesOnly bool `json:"names_only,omitempty" jsonschema:"return only the names of the entries instead of their size, permissions and other metadata"`
+	// SizeMap returns the sizes of the displayed entries in
+	// ListArchiveFilesResult.Sizes, keyed by name, instead of their
+	// metadata in Files, for quick size lookups.
+	SizeMap bool `json:"size_map,omitempty" jsonschema:"return only an object mapping the names of the entries to their sizes instead of their metadata"`
+	// SyntheticDirs makes directory entries independent of how the archive
+	// was created. If true, the parent directories that have no entry of
+	// their own are listed as well. If false, the explicit zero-size
+	// directory entries are dropped. If not set, the entries are listed as
+	// stored in the archive.
+	SyntheticDirs *bool `json:"synthetic_dirs,omitempty" jsonschema:"true to also list directories only implied by entry paths, false to drop explicit directory entries. If not set, entries are listed as stored"`
 }
 
-// ExtractArchiveFilesArgs are the arguments for the extract_archive_files tool.
-type ExtractArchiveFilesArgs struct {
+// ListArchiveFilesArgs are the arguments for the list_archive_files tool.
+type ListArchiveFilesArgs ListOptions
+
+// ExtractOptions are the options for extracting files from an archive.
+type ExtractOptions struct {
 	Path  string   `json:"path" jsonschema:"the path to the archive"`
-	Files []string `json:"files" jsonschema:"the files to extract"`
+	Files []string `json:"files,omitempty" jsonschema:"the files to extract"`
+	// All extracts every entry of the archive instead of Files. Entries
+	// hidden by an ignore file are left out.
+	All bool `json:"all,omitempty" jsonschema:"extract all entries of the archive instead of the listed files"`
+	// Index selects a single entry by its zero-based position in the
+	// archive instead of by name. It is mutually exclusive with Files.
+	Index *int `json:"index,omitempty" jsonschema:"the zero-based position of a single entry to extract, instead of files"`
+	// Occurrence selects a single occurrence of a name that appears more
+	// than once in the archive, such as in concatenated tars. It is
+	// zero-based in iteration order and requires exactly one of Files.
+	Occurrence *int `json:"occurrence,omitempty" jsonschema:"the zero-based occurrence, in archive order, of the single file to extract if its name appears more than once"`
+	// Charset is the IANA name of the character set of the files, which
+	// are transcoded to UTF-8 if set.
+	Charset string `json:"charset,omitempty" jsonschema:"an optional character set such as ISO-8859-1 or Shift_JIS to transcode the files from to UTF-8"`
+	// RangeStart and RangeEnd select the bytes [RangeStart, RangeEnd) of a
+	// single file. A RangeEnd of 0 selects the bytes up to the end of the
+	// file. Only the size of the range is limited, not that of the file.
+	RangeStart int64 `json:"range_start,omitempty" jsonschema:"the offset of the first byte to extract from a single file"`
+	RangeEnd   int64 `json:"range_end,omitempty" jsonschema:"the offset after the last byte to extract from a single file. If not set, the file is extracted to its end"`
+	// Pretty re-indents files detected as JSON or XML. Files that fail to
+	// parse are returned unchanged.
+	Pretty bool `json:"pretty,omitempty" jsonschema:"re-indent JSON and XML files for readability"`
+	// StripComponents removes that many leading path segments from the
+	// names of the returned files, like tar --strip-components. Files are
+	// still requested by their full names. Files with no more segments
+	// than that, such as their parent directories, are left out.
+	StripComponents int `json:"strip_components,omitempty" jsonschema:"remove this many leading path segments from the returned names. Entries without any remaining segment are skipped"`
+	// BundleAsZip packs the extracted files into a single zip archive,
+	// returned base64 encoded in the bundle of the result.
+	BundleAsZip bool `json:"bundle_as_zip,omitempty" jsonschema:"return the extracted files as a single base64 encoded zip archive instead of their individual content"`
+	// SkipDirectories leaves directory entries out of the result. If
+	// false, directories are returned with type "dir" and no content. If
+	// not set, directories are left out of extractions of All entries,
+	// where they are mere noise, but returned if requested by name.
+	SkipDirectories *bool `json:"skip_directories,omitempty" jsonschema:"true to omit directory entries from the result, false to return them with type dir and no content. If not set, they are omitted when extracting all entries and returned when requested by name"`
+	// VerifyCRC checks the content of zip entries against the CRC32
+	// stored in the archive. Other formats store no checksum.
+	VerifyCRC bool `json:"verify_crc,omitempty" jsonschema:"check the content of zip entries against their stored CRC32 and fail on a mismatch, which indicates corruption"`
+	// Dedup selects which occurrences of a name that appears more than
+	// once in the archive are returned: "first", "last" or "all". The
+	// default is "last", matching tar extraction semantics.
+	Dedup string `json:"dedup,omitempty" jsonschema:"which occurrences of a file appearing more than once in the archive to return: first, last or all. If not set, only the last one is returned, like tar extracts it"`
+	// DataURI returns the content of regular files as a base64 encoded
+	// data URI of their detected media type, which clients can embed
+	// directly.
+	DataURI bool `json:"data_uri,omitempty" jsonschema:"return the content of files as a base64 encoded data: URI of their detected media type, e.g. for embedding images"`
+	// CompressResponse returns the content of regular files gzip
+	// compressed and base64 encoded, with Encoding set to
+	// encodingGzipBase64, to save bandwidth on compressible content.
+	CompressResponse bool `json:"compress_response,omitempty" jsonschema:"return the content of files gzip compressed and base64 encoded, with encoding set to gzip+base64, to reduce the response size of compressible files"`
+	// FitMode controls what happens if the extracted files would exceed
+	// the maximum response size: "error", the default, fails with a
+	// ResponseTooLargeError, while "smallest-first" returns the smallest
+	// files that fit and lists the others as skipped.
+	FitMode string `json:"fit_mode,omitempty" jsonschema:"what to do if the files exceed the maximum response size: error fails, smallest-first returns the smallest files that fit and lists the rest as skipped. If not set, it will default to error"`
+	// BestEffort reports files that are too large or cannot be read in
+	// the result instead of failing the whole extraction. Hidden files
+	// are still refused.
+	BestEffort bool `json:"best_effort,omitempty" jsonschema:"return the files that could be extracted and list those that were too large or unreadable as skipped, instead of failing"`
+	// TolerateTruncation returns the partial content of a file cut short
+	// by the end of a truncated archive, marked as truncated, instead of
+	// failing.
+	TolerateTruncation bool `json:"tolerate_truncation,omitempty" jsonschema:"return what could be read of a file cut short by a truncated archive, marked as truncated with a note, instead of failing"`
+	// Raw returns the content in File.RawContent instead of File.Content.
+	Raw bool `json:"-"`
 }
 
+// ExtractArchiveFilesArgs are the arguments for the extract_archive_files tool.
+type ExtractArchiveFilesArgs ExtractOptions
+
 // File represents an extracted file's content and metadata.
 type File struct {
 	Name        string `json:"name"`
 	Size        int64  `json:"size"`
 	Permissions string `json:"permissions"`
-	Content     string `json:"content"`
+	// Type is the type of the entry like in FileInfo. Directories and
+	// symlinks have no content.
+	Type    string `json:"type"`
+	Content string `json:"content"`
+	// LinkTarget is the target of a symlink.
+	LinkTarget string `json:"link_target,omitempty"`
+	// Encoding is set to "gzip+base64" if Content was compressed because
+	// ExtractOptions.CompressResponse was set.
+	Encoding string `json:"encoding,omitempty"`
+	// Truncated is set if the archive ended before the content of the
+	// entry, which was then returned in part as requested by
+	// ExtractOptions.TolerateTruncation. Note explains what is missing.
+	Truncated bool   `json:"truncated,omitempty"`
+	Note      string `json:"note,omitempty"`
+	// Offset is the position of Content within the entry if only a byte
+	// range was extracted. Size is the size of the whole entry then.
+	Offset int64 `json:"offset,omitempty"`
+	// RawContent holds the content for in-process callers that set
+	// ExtractOptions.Raw, sparing the copy into Content.
+	RawContent []byte `json:"-"`
+	// mode is the file mode of the entry, preserved in zip bundles.
+	mode fs.FileMode
 }
 
-func (a *Archive) securePath(path string) (string, error) {
-	if !filepath.IsAbs(path) {
-		return "", fmt.Errorf("path is not an absolute path: %s", path)
-	}
-	absPath := filepath.Clean(path)
-	evalPath, err := filepath.EvalSymlinks(absPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to evaluate symlinks: %w", err)
-	}
-
-	if !strings.HasPrefix(evalPath, a.Workdir) {
-		return "", fmt.Errorf("path %s is outside of the working directory", path)
-	}
-	return evalPath, nil
+// list returns all entries of the archive at path that are not hidden by an
+// ignore file.
+func (a *Archive) list(ctx context.Context, path string) ([]FileInfo, error) {
+	return listWalk(a.pathWalker(ctx, path), a.ignoredEntries(path))
 }
 
-func (a *Archive) cpioList(path string, depth int) ([]FileInfo, error) {
-	securePath, err := a.securePath(path)
-	if err != nil {
-		return nil, err
-	}
-	file, err := os.Open(securePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open archive: %w", err)
-	}
-	defer file.Close()
-
-	reader := cpio.NewReader(file)
+// listWalk returns all entries visited by walk that are not ignored.
+func listWalk(walk walker, ignored func(FileInfo) bool) ([]FileInfo, error) {
 	var files []FileInfo
-	for {
-		header, err := reader.Next()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
-		if depth > 0 && len(strings.Split(strings.Trim(header.Name, "/"), "/")) > depth {
-			continue
+	err := walk(func(e *entry) error {
+		if !ignored(e.info) {
+			files = append(files, e.info)
 		}
-		files = append(files, FileInfo{
-			Name:        header.Name,
-			Size:        header.Size,
-			Permissions: header.Mode.String(),
-		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return files, nil
 }
 
-func (a *Archive) tarGzList(path string, depth int) ([]FileInfo, error) {
-	securePath, err := a.securePath(path)
-	if err != nil {
-		return nil, err
+// filterDepth drops the files that are nested deeper than depth. A depth of
+// 0 keeps all files.
+func filterDepth(files []FileInfo, depth int) []FileInfo {
+	if depth <= 0 {
+		return files
 	}
-	file, err := os.Open(securePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open archive: %w", err)
+	var filtered []FileInfo
+	for _, file := range files {
+		if withinDepth(file.Name, depth) {
+			filtered = append(filtered, file)
+		}
 	}
-	defer file.Close()
+	return filtered
+}
 
-	gzr, err := gzip.NewReader(file)
-	if err != nil {
-		return nil, err
-	}
-	defer gzr.Close()
+// withinDepth reports whether name is nested at most depth levels deep. A
+// depth of 0 allows any nesting.
+func withinDepth(name string, depth int) bool {
+	return depth <= 0 || len(strings.Split(strings.Trim(name, "/"), "/")) <= depth
+}
 
-	tr := tar.NewReader(gzr)
-	var files []FileInfo
-	for {
-		header, err := tr.Next()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, err
+// impliedDirs returns directory entries for the parent directories of files
+// that have no entry of their own, as is common for zip archives.
+func impliedDirs(files []FileInfo, permissionFormat string) []FileInfo {
+	seen := make(map[string]bool)
+	for _, file := range files {
+		if file.Type == typeDir {
+			seen[strings.Trim(file.Name, "/")] = true
 		}
-		if depth > 0 && len(strings.Split(strings.Trim(header.Name, "/"), "/")) > depth {
-			continue
+	}
+	var dirs []FileInfo
+	for _, file := range files {
+		for dir := path.Dir(strings.Trim(file.Name, "/")); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			if seen[dir] {
+				break
+			}
+			seen[dir] = true
+			dirs = append(dirs, FileInfo{
+				Name:        dir + "/",
+				Permissions: formatPermissions(fs.ModeDir|0o755, permissionFormat),
+				Type:        typeDir,
+			})
 		}
-		files = append(files, FileInfo{
-			Name:        header.Name,
-			Size:        header.Size,
-			Permissions: os.FileMode(header.Mode).String(),
-		})
 	}
-	return files, nil
+	return dirs
 }
 
-func (a *Archive) tarBz2List(path string, depth int) ([]FileInfo, error) {
-	securePath, err := a.securePath(path)
-	if err != nil {
-		return nil, err
-	}
-	file, err := os.Open(securePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open archive: %w", err)
-	}
-	defer file.Close()
+// stripDirs drops the explicit zero-size directory entries from files.
+func stripDirs(files []FileInfo) []FileInfo {
+	return slices.DeleteFunc(slices.Clone(files), func(file FileInfo) bool {
+		return file.Type == typeDir && file.Size == 0
+	})
+}
 
-	bz2r := bzip2.NewReader(file)
-	tr := tar.NewReader(bz2r)
-	var files []FileInfo
-	for {
-		header, err := tr.Next()
-		if err == io.EOF {
-			break
+// duplicates returns the names that occur more than once in files, in the
+// order of their first occurrence.
+func duplicates(files []FileInfo) []string {
+	count := make(map[string]int)
+	var names []string
+	for _, file := range files {
+		count[file.Name]++
+		if count[file.Name] == 2 {
+			names = append(names, file.Name)
 		}
-		if err != nil {
-			return nil, err
+	}
+	return names
+}
+
+// caseCollisions groups the distinct names in files that differ only by
+// case and would therefore collide on a case-insensitive filesystem. Groups
+// and names are in the order of their first occurrence.
+func caseCollisions(files []FileInfo) [][]string {
+	groups := make(map[string]int)
+	seen := make(map[string]bool)
+	var collisions [][]string
+	for _, file := range files {
+		if seen[file.Name] {
+			continue
 		}
-		if depth > 0 && len(strings.Split(strings.Trim(header.Name, "/"), "/")) > depth {
+		seen[file.Name] = true
+		folded := strings.ToLower(file.Name)
+		i, ok := groups[folded]
+		if !ok {
+			groups[folded] = len(collisions)
+			collisions = append(collisions, []string{file.Name})
 			continue
 		}
-		files = append(files, FileInfo{
-			Name:        header.Name,
-			Size:        header.Size,
-			Permissions: os.FileMode(header.Mode).String(),
-		})
+		collisions[i] = append(collisions[i], file.Name)
 	}
-	return files, nil
+	var result [][]string
+	for _, names := range collisions {
+		if len(names) > 1 {
+			result = append(result, names)
+		}
+	}
+	return result
 }
 
-func (a *Archive) tarXzList(path string, depth int) ([]FileInfo, error) {
-	securePath, err := a.securePath(path)
-	if err != nil {
-		return nil, err
+// keepLast drops all but the last occurrence of each name in files.
+func keepLast(files []FileInfo) []FileInfo {
+	last := make(map[string]int)
+	for i, file := range files {
+		last[file.Name] = i
 	}
-	file, err := os.Open(securePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open archive: %w", err)
+	var kept []FileInfo
+	for i, file := range files {
+		if last[file.Name] == i {
+			kept = append(kept, file)
+		}
 	}
-	defer file.Close()
+	return kept
+}
 
-	xzr, err := xz.NewReader(file)
-	if err != nil {
-		return nil, err
-	}
+// fileFilter holds the compiled type filter and include and exclude
+// patterns of ListOptions.
+type fileFilter struct {
+	typ              string
+	include, exclude func(string) bool
+}
 
-	tr := tar.NewReader(xzr)
-	var files []FileInfo
-	for {
-		header, err := tr.Next()
-		if err == io.EOF {
-			break
-		}
+func newFileFilter(opts ListOptions) (*fileFilter, error) {
+	f := &fileFilter{typ: opts.TypeFilter}
+	var err error
+	if opts.IncludePattern != "" {
+		f.include, err = compileNamePattern(opts.IncludePattern, opts.PatternSyntax)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("invalid include pattern: %w", err)
 		}
-		if depth > 0 && len(strings.Split(strings.Trim(header.Name, "/"), "/")) > depth {
-			continue
+	}
+	if opts.ExcludePattern != "" {
+		f.exclude, err = compileNamePattern(opts.ExcludePattern, opts.PatternSyntax)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern: %w", err)
 		}
-		files = append(files, FileInfo{
-			Name:        header.Name,
-			Size:        header.Size,
-			Permissions: os.FileMode(header.Mode).String(),
-		})
 	}
-	return files, nil
+	return f, nil
 }
 
-func (a *Archive) zipList(path string, depth int) ([]FileInfo, error) {
-	securePath, err := a.securePath(path)
-	if err != nil {
-		return nil, err
-	}
-	r, err := zip.OpenReader(securePath)
+// match reports whether file passes the filter.
+func (f *fileFilter) match(file FileInfo) bool {
+	return (f.typ == "" || file.Type == f.typ) &&
+		(f.include == nil || f.include(file.Name)) &&
+		(f.exclude == nil || !f.exclude(file.Name))
+}
+
+// filterFiles applies the type filter and the include and exclude patterns
+// of opts to files, preserving their order.
+func filterFiles(files []FileInfo, opts ListOptions, threshold int) ([]FileInfo, error) {
+	filter, err := newFileFilter(opts)
 	if err != nil {
 		return nil, err
 	}
-	defer r.Close()
 
-	var files []FileInfo
-	for _, f := range r.File {
-		if depth > 0 && len(strings.Split(strings.Trim(f.Name, "/"), "/")) > depth {
-			continue
+	keep := make([]bool, len(files))
+	forEachChunk(len(files), threshold, func(start, end int) error {
+		for i := start; i < end; i++ {
+			keep[i] = filter.match(files[i])
+		}
+		return nil
+	})
+
+	filteredFiles := []FileInfo{}
+	for i, file := range files {
+		if keep[i] {
+			filteredFiles = append(filteredFiles, file)
 		}
-		files = append(files, FileInfo{
-			Name:        f.Name,
-			Size:        int64(f.UncompressedSize64),
-			Permissions: f.Mode().String(),
-		})
 	}
-	return files, nil
+	return filteredFiles, nil
 }
 
 // ListArchiveFilesResult holds the result of the list_archive_files tool.
@@ -262,351 +576,760 @@ type ListArchiveFilesResult struct {
 	FilteredFiles  int        `json:"filtered_files"`
 	DisplayedFiles int        `json:"displayed_files"`
 	Files          []FileInfo `json:"files"`
+	// Names are the names of the displayed files if NamesOnly was set, in
+	// which case Files is empty.
+	Names []string `json:"names,omitempty"`
+	// Sizes maps the names of the displayed files to their sizes if
+	// SizeMap was set, in which case Files is empty. Of names appearing
+	// more than once, the last occurrence counts.
+	Sizes map[string]int64 `json:"sizes,omitempty"`
+	// Duplicates are the names that appear more than once in the archive.
+	Duplicates []string `json:"duplicates,omitempty"`
+	// CaseCollisions are groups of names that differ only by case and
+	// collide when extracted to a case-insensitive filesystem.
+	CaseCollisions [][]string `json:"case_collisions,omitempty"`
+	// ArchiveSHA256 is the hex encoded SHA256 of the archive file. It is
+	// only set if IncludeArchiveHash was requested.
+	ArchiveSHA256 string `json:"archive_sha256,omitempty"`
+	// HasManifest tells whether a Java archive holds a
+	// META-INF/MANIFEST.MF. It is only set for .jar, .war and .ear files.
+	HasManifest *bool `json:"has_manifest,omitempty"`
 }
 
-// ListArchiveFiles lists the files in an archive.
-func (a *Archive) ListArchiveFiles(ctx context.Context, req *mcp.CallToolRequest, args ListArchiveFilesArgs) (*mcp.CallToolResult, any, error) {
-	slog.Debug("mcp tool call: ListArchiveFiles", "session", req.Session.ID(), "params", args)
-	var files []FileInfo
-	var err error
-
-	switch {
-	case strings.HasSuffix(args.Path, ".cpio"):
-		files, err = a.cpioList(args.Path, args.Depth)
-	case strings.HasSuffix(args.Path, ".tar.gz"):
-		files, err = a.tarGzList(args.Path, args.Depth)
-	case strings.HasSuffix(args.Path, ".tar.bz2"):
-		files, err = a.tarBz2List(args.Path, args.Depth)
-	case strings.HasSuffix(args.Path, ".tar.xz"):
-		files, err = a.tarXzList(args.Path, args.Depth)
-	case strings.HasSuffix(args.Path, ".zip"):
-		files, err = a.zipList(args.Path, args.Depth)
+// validate checks the options that do not depend on the archive.
+func (opts ListOptions) validate() error {
+	if opts.TopN < 0 {
+		return fmt.Errorf("invalid top_n %d: must not be negative", opts.TopN)
+	}
+	if opts.NamesOnly && opts.SizeMap {
+		return errors.New("names_only and size_map are mutually exclusive")
+	}
+	switch opts.PatternSyntax {
+	case "", syntaxRegexp, syntaxGlob:
 	default:
-		return nil, nil, fmt.Errorf("unsupported archive format for %s", args.Path)
+		return fmt.Errorf("invalid pattern syntax %q: must be %q or %q", opts.PatternSyntax, syntaxRegexp, syntaxGlob)
 	}
-
-	if err != nil {
-		return nil, nil, err
+	switch opts.TypeFilter {
+	case "", typeFile, typeDir:
+		return nil
 	}
+	return fmt.Errorf("invalid type filter %q: must be %q or %q", opts.TypeFilter, typeFile, typeDir)
+}
 
-	totalFiles := len(files)
-	var filteredFiles []FileInfo
+// largest returns the n largest of files by descending size. Entries of
+// equal size keep their order. A zero n returns all files unchanged.
+func largest(files []FileInfo, n int) []FileInfo {
+	if n == 0 {
+		return files
+	}
+	sorted := slices.Clone(files)
+	slices.SortStableFunc(sorted, func(a, b FileInfo) int {
+		return cmp.Compare(b.Size, a.Size)
+	})
+	return sorted[:min(n, len(sorted))]
+}
 
-	for _, file := range files {
-		includeMatch := true
-		if args.IncludePattern != "" {
-			includeMatch, err = regexp.MatchString(args.IncludePattern, file.Name)
-			if err != nil {
-				return nil, nil, fmt.Errorf("invalid include pattern: %w", err)
-			}
-		}
+// listing holds the entries of an archive after applying the list options.
+type listing struct {
+	// total is the number of entries within the requested depth.
+	total      int
+	filtered   []FileInfo
+	dups       []string
+	collisions [][]string
+}
 
-		excludeMatch := false
-		if args.ExcludePattern != "" {
-			excludeMatch, err = regexp.MatchString(args.ExcludePattern, file.Name)
-			if err != nil {
-				return nil, nil, fmt.Errorf("invalid exclude pattern: %w", err)
-			}
-		}
+// filterListing applies all list options except for the limit to the
+// entries of an archive.
+func (a *Archive) filterListing(files []FileInfo, opts ListOptions) (listing, error) {
+	dups := duplicates(files)
+	if opts.Deduplicate && len(dups) > 0 {
+		files = keepLast(files)
+	}
+	switch {
+	case opts.SyntheticDirs != nil && !*opts.SyntheticDirs:
+		files = stripDirs(files)
+	case opts.SyntheticDirs != nil || opts.TypeFilter == typeDir:
+		files = append(files, impliedDirs(files, a.permissionFormat)...)
+	}
+	files = filterDepth(files, opts.Depth)
 
-		if includeMatch && !excludeMatch {
-			filteredFiles = append(filteredFiles, file)
+	filteredFiles, err := filterFiles(files, opts, a.parallelThreshold)
+	if err != nil {
+		return listing{}, err
+	}
+	if !opts.IncludeXattrs {
+		for i := range filteredFiles {
+			filteredFiles[i].Xattrs = nil
 		}
 	}
+	return listing{total: len(files), filtered: filteredFiles, dups: dups, collisions: caseCollisions(files)}, nil
+}
 
-	limit := args.Limit
+// displayLimit returns the number of files to display out of n.
+func displayLimit(limit, n int) int {
 	if limit == 0 {
 		limit = 100
 	}
+	return min(n, limit)
+}
 
-	displayedFilesCount := len(filteredFiles)
-	if displayedFilesCount > limit {
-		displayedFilesCount = limit
+// List lists the files in an archive, applying the depth, include and
+// exclude filters as well as the limit of displayed files.
+func (a *Archive) List(ctx context.Context, opts ListOptions) (ListArchiveFilesResult, error) {
+	if err := opts.validate(); err != nil {
+		return ListArchiveFilesResult{}, err
 	}
 
-	result := ListArchiveFilesResult{
-		TotalFiles:     totalFiles,
-		FilteredFiles:  len(filteredFiles),
-		DisplayedFiles: displayedFilesCount,
-		Files:          filteredFiles[:displayedFilesCount],
+	outer, nested, err := splitNested(opts.Path)
+	if err != nil {
+		return ListArchiveFilesResult{}, err
 	}
-
-	return nil, result, nil
-}
-
-func (a *Archive) cpioExtract(path string, filesToExtract []string) ([]File, error) {
-	securePath, err := a.securePath(path)
+	c, err := a.startWalk(ctx, outer)
 	if err != nil {
-		return nil, err
+		return ListArchiveFilesResult{}, err
 	}
-	file, err := os.Open(securePath)
+	defer c.done()
+	walk, cleanup, err := a.nestedWalker(c.ctx, c.path, nested, c.ignored)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open archive: %w", err)
+		return ListArchiveFilesResult{}, a.callError(ctx, opts.Path, err)
 	}
-	defer file.Close()
-
-	reader := cpio.NewReader(file)
-	var extractedFiles []File
+	defer cleanup()
+	files, err := listWalk(withDataOffsets(walk), c.ignored)
+	if err != nil {
+		return ListArchiveFilesResult{}, a.timeoutError(ctx, err)
+	}
+	l, err := a.filterListing(files, opts)
+	if err != nil {
+		return ListArchiveFilesResult{}, err
+	}
+	name := outer
+	if len(nested) > 0 {
+		name = nested[len(nested)-1]
+	}
+	hasManifest, isJar := jarManifest(name, files)
 
-	for {
-		header, err := reader.Next()
-		if err == io.EOF {
-			break
+	files = largest(l.filtered, opts.TopN)
+	displayed := displayLimit(opts.Limit, len(files))
+	result := ListArchiveFilesResult{
+		TotalFiles:     l.total,
+		FilteredFiles:  len(l.filtered),
+		DisplayedFiles: displayed,
+		Files:          files[:displayed],
+		Duplicates:     l.dups,
+		CaseCollisions: l.collisions,
+	}
+	if isJar {
+		result.HasManifest = &hasManifest
+	}
+	if opts.NamesOnly {
+		result.Names = make([]string, len(result.Files))
+		for i, file := range result.Files {
+			result.Names[i] = file.Name
 		}
-		if err != nil {
-			return nil, err
+		result.Files = []FileInfo{}
+	}
+	if opts.SizeMap {
+		result.Sizes = make(map[string]int64, len(result.Files))
+		for _, file := range result.Files {
+			result.Sizes[file.Name] = file.Size
 		}
-
-		for _, f := range filesToExtract {
-			if header.Name == f {
-				if header.Size > a.maxSize {
-					return nil, fmt.Errorf("file %s is too large to extract: %d bytes", header.Name, header.Size)
-				}
-
-				buf := make([]byte, header.Size)
-				if _, err := io.ReadFull(reader, buf); err != nil {
-					return nil, fmt.Errorf("could not read file %s from archive: %w", header.Name, err)
-				}
-
-				extractedFile := File{
-					Name:        header.Name,
-					Size:        header.Size,
-					Permissions: header.Mode.String(),
-					Content:     string(buf),
-				}
-				extractedFiles = append(extractedFiles, extractedFile)
-			}
+		result.Files = []FileInfo{}
+	}
+	if opts.IncludeArchiveHash {
+		result.ArchiveSHA256, err = a.hashFile(c.ctx, c.path)
+		if err != nil {
+			return ListArchiveFilesResult{}, a.timeoutError(ctx, err)
 		}
 	}
-	return extractedFiles, nil
+	return result, nil
 }
 
-func (a *Archive) tarGzExtract(path string, filesToExtract []string) ([]File, error) {
-	securePath, err := a.securePath(path)
-	if err != nil {
-		return nil, err
-	}
-	file, err := os.Open(securePath)
+// hashFile returns the hex encoded SHA256 of the archive at path, streaming
+// its content. The volumes of split archives are hashed as one.
+func (a *Archive) hashFile(ctx context.Context, path string) (string, error) {
+	r, err := a.openArchive(ctx, path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open archive: %w", err)
+		return "", err
 	}
-	defer file.Close()
+	defer r.Close()
 
-	gzr, err := gzip.NewReader(file)
-	if err != nil {
-		return nil, err
+	h := sha256.New()
+	if _, err := io.Copy(h, &ctxReader{ctx: ctx, r: io.NewSectionReader(r, 0, r.size)}); err != nil {
+		return "", fmt.Errorf("failed to hash archive: %w", err)
 	}
-	defer gzr.Close()
-
-	tr := tar.NewReader(gzr)
-	var extractedFiles []File
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
 
-	for {
-		header, err := tr.Next()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
+// ListArchiveFiles lists the files in an archive.
+func (a *Archive) ListArchiveFiles(ctx context.Context, req *mcp.CallToolRequest, args ListArchiveFilesArgs) (*mcp.CallToolResult, ListArchiveFilesResult, error) {
+	return callTool(ctx, a, req, "list_archive_files", args.Path, ListOptions(args), a.List)
+}
 
-		for _, f := range filesToExtract {
-			if header.Name == f {
-				if header.Size > a.maxSize {
-					return nil, fmt.Errorf("file %s is too large to extract: %d bytes", header.Name, header.Size)
-				}
-
-				buf := make([]byte, header.Size)
-				if _, err := io.ReadFull(tr, buf); err != nil {
-					return nil, fmt.Errorf("could not read file %s from archive: %w", header.Name, err)
-				}
-
-				extractedFile := File{
-					Name:        header.Name,
-					Size:        header.Size,
-					Permissions: os.FileMode(header.Mode).String(),
-					Content:     string(buf),
-				}
-				extractedFiles = append(extractedFiles, extractedFile)
-			}
+// maxEntrySize is the largest entry size considered plausible. Larger or
+// negative sizes stem from malformed or crafted headers.
+const maxEntrySize = 1 << 40
+
+// checkEntrySize refuses entries with an implausible size.
+func checkEntrySize(e *entry) error {
+	if e.info.Size < 0 || e.info.Size > maxEntrySize {
+		return &rejectedError{
+			reason: reasonInvalidSize,
+			entry:  e.info.Name,
+			err:    fmt.Errorf("invalid entry size %d for %s", e.info.Size, e.info.Name),
 		}
 	}
-	return extractedFiles, nil
+	return nil
 }
 
-func (a *Archive) tarBz2Extract(path string, filesToExtract []string) ([]File, error) {
-	securePath, err := a.securePath(path)
-	if err != nil {
-		return nil, err
+// readEntry reads the complete content of an entry, refusing entries larger
+// than the configured maximum size.
+func (a *Archive) readEntry(e *entry) (File, error) {
+	if err := checkEntrySize(e); err != nil {
+		return File{}, err
 	}
-	file, err := os.Open(securePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open archive: %w", err)
+	if err := checkContent(e.info); err != nil {
+		return File{}, err
+	}
+	if e.info.Type == typeDir || (e.info.Type == typeSymlink && e.linkTarget != "") {
+		return File{
+			Name:        e.info.Name,
+			Permissions: e.info.Permissions,
+			Type:        e.info.Type,
+			LinkTarget:  e.linkTarget,
+			mode:        e.mode,
+		}, nil
+	}
+	if e.info.Size > a.maxSize {
+		return File{}, &rejectedError{
+			reason: reasonTooLarge,
+			entry:  e.info.Name,
+			err:    fmt.Errorf("file %s is too large to extract: %d bytes", e.info.Name, e.info.Size),
+		}
 	}
-	defer file.Close()
 
-	bz2r := bzip2.NewReader(file)
-	tr := tar.NewReader(bz2r)
-	var extractedFiles []File
+	rc, err := e.open()
+	if err != nil {
+		return File{}, err
+	}
+	defer rc.Close()
 
-	for {
-		header, err := tr.Next()
-		if err == io.EOF {
-			break
+	var buf []byte
+	if e.sizeUnknown {
+		buf, err = io.ReadAll(io.LimitReader(rc, a.maxSize+1))
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return File{}, truncatedFile(e, buf, -1)
 		}
 		if err != nil {
-			return nil, err
+			return File{}, fmt.Errorf("could not read file %s from archive: %w", e.info.Name, err)
 		}
-
-		for _, f := range filesToExtract {
-			if header.Name == f {
-				if header.Size > a.maxSize {
-					return nil, fmt.Errorf("file %s is too large to extract: %d bytes", header.Name, header.Size)
-				}
-
-				buf := make([]byte, header.Size)
-				if _, err := io.ReadFull(tr, buf); err != nil {
-					return nil, fmt.Errorf("could not read file %s from archive: %w", header.Name, err)
-				}
-
-				extractedFile := File{
-					Name:        header.Name,
-					Size:        header.Size,
-					Permissions: os.FileMode(header.Mode).String(),
-					Content:     string(buf),
-				}
-				extractedFiles = append(extractedFiles, extractedFile)
+		if int64(len(buf)) > a.maxSize {
+			return File{}, &rejectedError{
+				reason: reasonTooLarge,
+				entry:  e.info.Name,
+				err:    fmt.Errorf("file %s is too large to extract: more than %d bytes", e.info.Name, a.maxSize),
 			}
 		}
+	} else {
+		buf = make([]byte, e.info.Size)
+		n, err := io.ReadFull(rc, buf)
+		if err == io.ErrUnexpectedEOF || (err == io.EOF && e.info.Size > 0) {
+			return File{}, truncatedFile(e, buf[:n], e.info.Size)
+		}
+		if err != nil {
+			return File{}, fmt.Errorf("could not read file %s from archive: %w", e.info.Name, err)
+		}
 	}
-	return extractedFiles, nil
+
+	if e.info.Type == typeSymlink {
+		// Zip archives store the target of a symlink as its content.
+		return File{
+			Name:        e.info.Name,
+			Permissions: e.info.Permissions,
+			Type:        typeSymlink,
+			LinkTarget:  string(buf),
+			mode:        e.mode,
+		}, nil
+	}
+	return File{
+		Name:        e.info.Name,
+		Size:        int64(len(buf)),
+		Permissions: e.info.Permissions,
+		Type:        e.info.Type,
+		RawContent:  buf,
+		mode:        e.mode,
+	}, nil
 }
 
-func (a *Archive) tarXzExtract(path string, filesToExtract []string) ([]File, error) {
-	securePath, err := a.securePath(path)
-	if err != nil {
-		return nil, err
+// normalizeName strips leading "./" and collapses duplicate slashes, so that
+// names differing only in these details match.
+func normalizeName(name string) string {
+	for strings.Contains(name, "//") {
+		name = strings.ReplaceAll(name, "//", "/")
 	}
-	file, err := os.Open(securePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open archive: %w", err)
+	for strings.HasPrefix(name, "./") {
+		name = name[2:]
 	}
-	defer file.Close()
+	return name
+}
 
-	xzr, err := xz.NewReader(file)
-	if err != nil {
-		return nil, err
+// stripComponents removes the first n segments of name. It reports false if
+// no segment remains.
+func stripComponents(name string, n int) (string, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(normalizeName(name), "/"), "/", n+1)
+	if len(parts) <= n || parts[n] == "" {
+		return "", false
 	}
+	return parts[n], true
+}
 
-	tr := tar.NewReader(xzr)
-	var extractedFiles []File
+// extract returns the content of the requested files of the archive at path.
+// Entry and requested names are compared after normalization.
+func (a *Archive) extract(ctx context.Context, path string, filesToExtract []string) ([]File, error) {
+	return extractNames(a.pathWalker(ctx, path), a.ignoredEntries(path), filesToExtract, dedupLast, a.readEntry)
+}
+
+// Values of ExtractOptions.Dedup.
+const (
+	dedupAll   = "all"
+	dedupFirst = "first"
+	dedupLast  = "last"
+)
 
-	for {
-		header, err := tr.Next()
-		if err == io.EOF {
-			break
+// extractNames returns the content of the entries visited by walk whose
+// normalized names match one of filesToExtract, read by read. The files are
+// returned in the order in which they were requested, names requested more
+// than once only at their first position. Of entries occurring more than
+// once in the archive, dedup selects the first, the last or all of them,
+// the latter in archive order. Names not found are left out. Matching
+// entries that are ignored are refused.
+func extractNames(walk walker, ignored func(FileInfo) bool, filesToExtract []string, dedup string, read func(*entry) (File, error)) ([]File, error) {
+	position := make(map[string]int, len(filesToExtract))
+	for i, f := range filesToExtract {
+		name := normalizeName(f)
+		if _, ok := position[name]; !ok {
+			position[name] = i
 		}
-		if err != nil {
-			return nil, err
+	}
+	lookup := func(info FileInfo) (int, bool) {
+		pos, ok := position[normalizeName(info.Name)]
+		return pos, ok
+	}
+	return extractMatching(walk, ignored, lookup, dedup, read)
+}
+
+// extractAll returns the content of all entries visited by walk that are
+// not ignored, read by read, in archive order. Of entries occurring more
+// than once, dedup selects the first, the last or all of them.
+func extractAll(walk walker, ignored func(FileInfo) bool, dedup string, read func(*entry) (File, error)) ([]File, error) {
+	n := 0
+	lookup := func(info FileInfo) (int, bool) {
+		if ignored(info) {
+			return 0, false
 		}
+		n++
+		return n, true
+	}
+	return extractMatching(walk, ignored, lookup, dedup, read)
+}
 
-		for _, f := range filesToExtract {
-			if header.Name == f {
-				if header.Size > a.maxSize {
-					return nil, fmt.Errorf("file %s is too large to extract: %d bytes", header.Name, header.Size)
-				}
-
-				buf := make([]byte, header.Size)
-				if _, err := io.ReadFull(tr, buf); err != nil {
-					return nil, fmt.Errorf("could not read file %s from archive: %w", header.Name, err)
-				}
-
-				extractedFile := File{
-					Name:        header.Name,
-					Size:        header.Size,
-					Permissions: os.FileMode(header.Mode).String(),
-					Content:     string(buf),
-				}
-				extractedFiles = append(extractedFiles, extractedFile)
+// extractMatching returns the content of the entries visited by walk for
+// which lookup reports a position, read by read and sorted by that
+// position. Of entries occurring more than once, dedup selects the first,
+// the last or all of them. Matching entries that are ignored are refused.
+func extractMatching(walk walker, ignored func(FileInfo) bool, lookup func(FileInfo) (int, bool), dedup string, read func(*entry) (File, error)) ([]File, error) {
+	type found struct {
+		pos  int
+		file File
+	}
+	var extracted []found
+	// seen maps the names extracted so far to their index in extracted.
+	seen := make(map[string]int)
+	err := walk(func(e *entry) error {
+		name := normalizeName(e.info.Name)
+		pos, ok := lookup(e.info)
+		if !ok {
+			return nil
+		}
+		prev, dup := seen[name]
+		if dup && dedup == dedupFirst {
+			return nil
+		}
+		if ignored(e.info) {
+			return &rejectedError{
+				reason: reasonIgnored,
+				entry:  e.info.Name,
+				err:    fmt.Errorf("file %s is hidden by server policy", e.info.Name),
 			}
 		}
+		extractedFile, err := read(e)
+		if errors.Is(err, errSkipEntry) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if dup && dedup == dedupLast {
+			extracted[prev].file = extractedFile
+			return nil
+		}
+		seen[name] = len(extracted)
+		extracted = append(extracted, found{pos, extractedFile})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(extracted, func(a, b found) int {
+		return cmp.Compare(a.pos, b.pos)
+	})
+	extractedFiles := make([]File, len(extracted))
+	for i, f := range extracted {
+		extractedFiles[i] = f.file
 	}
 	return extractedFiles, nil
 }
 
-func (a *Archive) zipExtract(path string, filesToExtract []string) ([]File, error) {
-	securePath, err := a.securePath(path)
+// extractIndex returns the entry at the zero-based position index among the
+// entries visited by walk, read by read. Ignored entries are not counted.
+func extractIndex(walk walker, ignored func(FileInfo) bool, index int, read func(*entry) (File, error)) ([]File, error) {
+	var extractedFiles []File
+	n := 0
+	reached := false
+	err := walk(func(e *entry) error {
+		if ignored(e.info) {
+			return nil
+		}
+		if n < index {
+			n++
+			return nil
+		}
+		reached = true
+		extractedFile, err := read(e)
+		if errors.Is(err, errSkipEntry) {
+			return errStopWalk
+		}
+		if err != nil {
+			return err
+		}
+		extractedFiles = append(extractedFiles, extractedFile)
+		return errStopWalk
+	})
 	if err != nil {
 		return nil, err
 	}
-	r, err := zip.OpenReader(securePath)
-	if err != nil {
-		return nil, err
+	if !reached {
+		return nil, fmt.Errorf("index %d out of range: archive has %d entries", index, n)
 	}
-	defer r.Close()
+	return extractedFiles, nil
+}
 
+// extractOccurrence extracts the occurrence-th entry named name, counting
+// from zero in iteration order. It fails if the archive holds fewer
+// entries of that name.
+func extractOccurrence(walk walker, ignored func(FileInfo) bool, name string, occurrence int, read func(*entry) (File, error)) ([]File, error) {
+	name = normalizeName(name)
 	var extractedFiles []File
-	for _, f := range r.File {
-		for _, fileToExtract := range filesToExtract {
-			if f.Name == fileToExtract {
-				if f.UncompressedSize64 > uint64(a.maxSize) {
-					return nil, fmt.Errorf("file %s is too large to extract: %d bytes", f.Name, f.UncompressedSize64)
-				}
-
-				rc, err := f.Open()
-				if err != nil {
-					return nil, err
-				}
-
-				buf := make([]byte, f.UncompressedSize64)
-				if _, err := io.ReadFull(rc, buf); err != nil {
-					rc.Close()
-					return nil, fmt.Errorf("could not read file %s from archive: %w", f.Name, err)
-				}
-				rc.Close()
-
-				extractedFile := File{
-					Name:        f.Name,
-					Size:        int64(f.UncompressedSize64),
-					Permissions: f.Mode().String(),
-					Content:     string(buf),
-				}
-				extractedFiles = append(extractedFiles, extractedFile)
+	n := 0
+	reached := false
+	err := walk(func(e *entry) error {
+		if normalizeName(e.info.Name) != name {
+			return nil
+		}
+		if ignored(e.info) {
+			return &rejectedError{
+				reason: reasonIgnored,
+				entry:  e.info.Name,
+				err:    fmt.Errorf("file %s is hidden by server policy", e.info.Name),
 			}
 		}
+		if n < occurrence {
+			n++
+			return nil
+		}
+		reached = true
+		extractedFile, err := read(e)
+		if errors.Is(err, errSkipEntry) {
+			return errStopWalk
+		}
+		if err != nil {
+			return err
+		}
+		extractedFiles = append(extractedFiles, extractedFile)
+		return errStopWalk
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !reached {
+		return nil, fmt.Errorf("occurrence %d of %s out of range: archive has %d entries of that name", occurrence, name, n)
 	}
 	return extractedFiles, nil
 }
 
 // ExtractArchiveFilesResult holds the result of the extract_archive_files tool.
 type ExtractArchiveFilesResult struct {
+	// Files are the extracted files. If they were bundled, only their
+	// metadata is listed.
 	Files []File `json:"files"`
+	// Bundle is the base64 encoded zip archive of the files if
+	// ExtractOptions.BundleAsZip was set.
+	Bundle string `json:"bundle,omitempty"`
+	// Skipped are the requested files that could not be extracted if
+	// ExtractOptions.BestEffort was set, or that did not fit into the
+	// response with the smallest-first ExtractOptions.FitMode.
+	Skipped []SkippedFile `json:"skipped,omitempty"`
 }
 
-// ExtractArchiveFiles extracts files from an archive and returns their content.
-func (a *Archive) ExtractArchiveFiles(ctx context.Context, req *mcp.CallToolRequest, args ExtractArchiveFilesArgs) (*mcp.CallToolResult, any, error) {
-	slog.Debug("mcp tool call: ExtractArchiveFiles", "session", req.Session.ID(), "params", args)
-	var files []File
-	var err error
+// extraction holds what is derived from ExtractOptions before an archive
+// is opened.
+type extraction struct {
+	opts ExtractOptions
+	read func(*entry) (File, error)
+	enc  encoding.Encoding
+	// maxBundleSize caps the size of a zip bundle.
+	maxBundleSize int64
+	// maxResponseBytes caps the estimated size of the result.
+	maxResponseBytes int64
+	// skipped collects the files that failed to extract with
+	// ExtractOptions.BestEffort.
+	skipped []SkippedFile
+	// truncated is set once the partial content of an entry cut short by
+	// the end of the archive was returned with
+	// ExtractOptions.TolerateTruncation.
+	truncated bool
+}
 
-	switch {
-	case strings.HasSuffix(args.Path, ".cpio"):
-		files, err = a.cpioExtract(args.Path, args.Files)
-	case strings.HasSuffix(args.Path, ".tar.gz"):
-		files, err = a.tarGzExtract(args.Path, args.Files)
-	case strings.HasSuffix(args.Path, ".tar.bz2"):
-		files, err = a.tarBz2Extract(args.Path, args.Files)
-	case strings.HasSuffix(args.Path, ".tar.xz"):
-		files, err = a.tarXzExtract(args.Path, args.Files)
-	case strings.HasSuffix(args.Path, ".zip"):
-		files, err = a.zipExtract(args.Path, args.Files)
+// newExtraction validates the options that do not depend on the archive.
+func (a *Archive) newExtraction(opts ExtractOptions) (*extraction, error) {
+	x := &extraction{opts: opts, read: a.readEntry, maxBundleSize: a.maxBundleSize, maxResponseBytes: a.maxResponseBytes}
+	if opts.StripComponents < 0 {
+		return nil, fmt.Errorf("invalid strip_components %d: must not be negative", opts.StripComponents)
+	}
+	switch opts.Dedup {
+	case "", dedupAll, dedupFirst, dedupLast:
+	default:
+		return nil, fmt.Errorf("invalid dedup %q: must be %q, %q or %q", opts.Dedup, dedupAll, dedupFirst, dedupLast)
+	}
+	if opts.All {
+		if len(opts.Files) > 0 || opts.Index != nil || opts.Occurrence != nil {
+			return nil, errors.New("all is mutually exclusive with files, index and occurrence")
+		}
+	} else if len(opts.Files) == 0 && opts.Index == nil {
+		return nil, errors.New("no files requested: list the files to extract, select an index or set all")
+	}
+	if opts.Index != nil {
+		if len(opts.Files) > 0 {
+			return nil, errors.New("index and files are mutually exclusive")
+		}
+		if *opts.Index < 0 {
+			return nil, fmt.Errorf("index %d out of range", *opts.Index)
+		}
+	}
+	switch opts.FitMode {
+	case "", fitError, fitSmallestFirst:
 	default:
-		return nil, nil, fmt.Errorf("unsupported archive format for %s", args.Path)
+		return nil, fmt.Errorf("invalid fit_mode %q: must be %q or %q", opts.FitMode, fitError, fitSmallestFirst)
 	}
+	if opts.DataURI && opts.BundleAsZip {
+		return nil, errors.New("data_uri and bundle_as_zip are mutually exclusive")
+	}
+	if opts.CompressResponse && (opts.DataURI || opts.BundleAsZip) {
+		return nil, errors.New("compress_response is mutually exclusive with data_uri and bundle_as_zip")
+	}
+	if opts.Occurrence != nil {
+		if len(opts.Files) != 1 {
+			return nil, errors.New("occurrence requires exactly one file")
+		}
+		if opts.Dedup != "" {
+			return nil, errors.New("occurrence and dedup are mutually exclusive")
+		}
+		if *opts.Occurrence < 0 {
+			return nil, fmt.Errorf("occurrence %d out of range", *opts.Occurrence)
+		}
+	}
+	if opts.RangeStart != 0 || opts.RangeEnd != 0 {
+		if opts.BundleAsZip {
+			return nil, errors.New("a byte range cannot be bundled as zip")
+		}
+		if opts.VerifyCRC {
+			return nil, errors.New("the CRC32 of a byte range cannot be verified")
+		}
+		if err := a.checkRange(opts); err != nil {
+			return nil, err
+		}
+		x.read = func(e *entry) (File, error) {
+			return a.readRange(e, opts.RangeStart, opts.RangeEnd)
+		}
+	}
+	if opts.VerifyCRC {
+		x.read = verifyingCRC(x.read)
+	}
+	x.read = a.timedRead(x.read)
+	if opts.TolerateTruncation {
+		x.read = x.toleratingTruncation(x.read)
+	}
+	if opts.BestEffort {
+		x.read = x.skipping(x.read)
+	}
+	if opts.Charset != "" {
+		var err error
+		if x.enc, err = lookupCharset(opts.Charset); err != nil {
+			return nil, err
+		}
+	}
+	return x, nil
+}
 
+// run extracts the requested entries of those visited by walk.
+func (x *extraction) run(walk walker, ignored func(FileInfo) bool) ([]File, error) {
+	if x.opts.TolerateTruncation {
+		walk = x.truncatedWalk(walk)
+	}
+	if x.opts.Index != nil {
+		return extractIndex(walk, ignored, *x.opts.Index, x.read)
+	}
+	if x.opts.Occurrence != nil {
+		return extractOccurrence(walk, ignored, x.opts.Files[0], *x.opts.Occurrence, x.read)
+	}
+	if x.opts.All {
+		return extractAll(walk, ignored, cmp.Or(x.opts.Dedup, dedupLast), x.read)
+	}
+	return extractNames(walk, ignored, x.opts.Files, cmp.Or(x.opts.Dedup, dedupLast), x.read)
+}
+
+// skipDirectories reports whether directory entries are left out of the
+// result, see ExtractOptions.SkipDirectories.
+func (opts ExtractOptions) skipDirectories() bool {
+	if opts.SkipDirectories != nil {
+		return *opts.SkipDirectories
+	}
+	return opts.All
+}
+
+// finish drops directories and strips, transcodes, pretty-prints and
+// encodes as data URIs the extracted files as requested and bundles them
+// or moves their content to File.Content unless raw content was requested.
+// A result too large for a response is refused with a
+// ResponseTooLargeError unless only the smallest files that fit are
+// requested.
+func (x *extraction) finish(files []File) (ExtractArchiveFilesResult, error) {
+	if x.opts.skipDirectories() {
+		files = slices.DeleteFunc(files, func(file File) bool { return file.Type == typeDir })
+	}
+	if x.opts.StripComponents > 0 {
+		stripped := files[:0]
+		for _, file := range files {
+			if name, ok := stripComponents(file.Name, x.opts.StripComponents); ok {
+				file.Name = name
+				stripped = append(stripped, file)
+			}
+		}
+		files = stripped
+	}
+	if x.enc != nil {
+		for i := range files {
+			var err error
+			if files[i].RawContent, err = toUTF8(x.enc, files[i].RawContent); err != nil {
+				return ExtractArchiveFilesResult{}, fmt.Errorf("%s: %w", files[i].Name, err)
+			}
+		}
+	}
+	if x.opts.Pretty {
+		for i := range files {
+			files[i].RawContent = prettyPrint(files[i].Name, files[i].RawContent)
+		}
+	}
+	if x.opts.DataURI {
+		for i := range files {
+			if files[i].Type != typeDir && files[i].Type != typeSymlink {
+				files[i].RawContent = dataURI(files[i].Name, files[i].RawContent)
+			}
+		}
+	}
+	if x.opts.CompressResponse {
+		for i := range files {
+			if files[i].Type == typeDir || files[i].Type == typeSymlink {
+				continue
+			}
+			var err error
+			if files[i].RawContent, err = gzipBase64(files[i].RawContent); err != nil {
+				return ExtractArchiveFilesResult{}, fmt.Errorf("%s: %w", files[i].Name, err)
+			}
+			files[i].Encoding = encodingGzipBase64
+		}
+	}
+	if x.opts.BundleAsZip {
+		bundle, err := bundleZip(files, x.maxBundleSize)
+		if err != nil {
+			return ExtractArchiveFilesResult{}, err
+		}
+		err = checkResponseSize(files, true, int64(len(bundle)), x.maxResponseBytes)
+		if x.fitsSmallest(err) {
+			files = x.fitSmallest(files, true)
+			if bundle, err = bundleZip(files, x.maxBundleSize); err == nil {
+				err = checkResponseSize(files, true, int64(len(bundle)), x.maxResponseBytes)
+			}
+		}
+		if err != nil {
+			return ExtractArchiveFilesResult{}, err
+		}
+		for i := range files {
+			files[i].RawContent = nil
+		}
+		return ExtractArchiveFilesResult{Files: files, Bundle: base64.StdEncoding.EncodeToString(bundle), Skipped: x.skipped}, nil
+	}
+	if !x.opts.Raw {
+		err := checkResponseSize(files, false, 0, x.maxResponseBytes)
+		if x.fitsSmallest(err) {
+			files = x.fitSmallest(files, false)
+			err = checkResponseSize(files, false, 0, x.maxResponseBytes)
+		}
+		if err != nil {
+			return ExtractArchiveFilesResult{}, err
+		}
+		for i := range files {
+			files[i].Content = string(files[i].RawContent)
+			files[i].RawContent = nil
+		}
+	}
+	return ExtractArchiveFilesResult{Files: files, Skipped: x.skipped}, nil
+}
+
+// Extract extracts files from an archive and returns their content.
+func (a *Archive) Extract(ctx context.Context, opts ExtractOptions) (ExtractArchiveFilesResult, error) {
+	outer, nested, err := splitNested(opts.Path)
+	if err != nil {
+		return ExtractArchiveFilesResult{}, err
+	}
+	nested = nestedFile(&opts, nested)
+	x, err := a.newExtraction(opts)
 	if err != nil {
-		return nil, nil, err
+		return ExtractArchiveFilesResult{}, err
 	}
 
-	return nil, ExtractArchiveFilesResult{Files: files}, nil
+	c, err := a.startWalk(ctx, outer)
+	if err != nil {
+		return ExtractArchiveFilesResult{}, err
+	}
+	defer c.done()
+	walk, cleanup, err := a.nestedWalker(c.ctx, c.path, nested, c.ignored)
+	if err != nil {
+		return ExtractArchiveFilesResult{}, a.callError(ctx, opts.Path, err)
+	}
+	defer cleanup()
+	files, err := x.run(walk, c.ignored)
+	if err != nil {
+		return ExtractArchiveFilesResult{}, a.callError(ctx, opts.Path, err)
+	}
+	for _, s := range x.skipped {
+		a.audit(ctx, opts.Path, s.err)
+	}
+	a.metrics.extracted(files)
+	return x.finish(files)
+}
+
+// ExtractArchiveFiles extracts files from an archive and returns their content.
+func (a *Archive) ExtractArchiveFiles(ctx context.Context, req *mcp.CallToolRequest, args ExtractArchiveFilesArgs) (*mcp.CallToolResult, ExtractArchiveFilesResult, error) {
+	return callTool(ctx, a, req, "extract_archive_files", args.Path, ExtractOptions(args), a.Extract)
 }
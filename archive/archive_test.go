@@ -5,9 +5,26 @@
 package archive
 
 import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"maps"
+	"math"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 
@@ -38,9 +55,9 @@ func containsFile(files []FileInfo, expected expectedFile) bool {
 
 func TestCpioList(t *testing.T) {
 	a := newTestArchive(t)
-	files, err := a.cpioList(filepath.Join(a.Workdir, "test.cpio"), 0)
+	files, err := a.list(context.Background(), filepath.Join(a.Workdir, "test.cpio"))
 	if err != nil {
-		t.Fatalf("cpioList failed: %v", err)
+		t.Fatalf("list failed: %v", err)
 	}
 
 	expected := []expectedFile{
@@ -60,11 +77,39 @@ func TestCpioList(t *testing.T) {
 	}
 }
 
+func TestCpioGzList(t *testing.T) {
+	a := newTestArchive(t)
+	for _, archiveType := range []string{"test.cpio.gz", "test.cpio.xz"} {
+		t.Run(archiveType, func(t *testing.T) {
+			files, err := a.list(context.Background(), filepath.Join(a.Workdir, archiveType))
+			if err != nil {
+				t.Fatalf("list failed: %v", err)
+			}
+
+			expected := []expectedFile{
+				{name: "foo", size: 0},
+				{name: "foo/baar.txt", size: 27},
+				{name: "foo/bazz", size: 5},
+			}
+
+			if len(files) != len(expected) {
+				t.Fatalf("expected %d files, got %d", len(expected), len(files))
+			}
+
+			for _, exp := range expected {
+				if !containsFile(files, exp) {
+					t.Errorf("expected file '%v' not found in archive", exp)
+				}
+			}
+		})
+	}
+}
+
 func TestTarGzList(t *testing.T) {
 	a := newTestArchive(t)
-	files, err := a.tarGzList(filepath.Join(a.Workdir, "test.tar.gz"), 0)
+	files, err := a.list(context.Background(), filepath.Join(a.Workdir, "test.tar.gz"))
 	if err != nil {
-		t.Fatalf("tarGzList failed: %v", err)
+		t.Fatalf("list failed: %v", err)
 	}
 
 	expected := []expectedFile{
@@ -86,9 +131,9 @@ func TestTarGzList(t *testing.T) {
 
 func TestTarBz2List(t *testing.T) {
 	a := newTestArchive(t)
-	files, err := a.tarBz2List(filepath.Join(a.Workdir, "test.tar.bz2"), 0)
+	files, err := a.list(context.Background(), filepath.Join(a.Workdir, "test.tar.bz2"))
 	if err != nil {
-		t.Fatalf("tarBz2List failed: %v", err)
+		t.Fatalf("list failed: %v", err)
 	}
 
 	expected := []expectedFile{
@@ -110,9 +155,9 @@ func TestTarBz2List(t *testing.T) {
 
 func TestTarXzList(t *testing.T) {
 	a := newTestArchive(t)
-	files, err := a.tarXzList(filepath.Join(a.Workdir, "test.tar.xz"), 0)
+	files, err := a.list(context.Background(), filepath.Join(a.Workdir, "test.tar.xz"))
 	if err != nil {
-		t.Fatalf("tarXzList failed: %v", err)
+		t.Fatalf("list failed: %v", err)
 	}
 
 	expected := []expectedFile{
@@ -134,9 +179,9 @@ func TestTarXzList(t *testing.T) {
 
 func TestZipList(t *testing.T) {
 	a := newTestArchive(t)
-	files, err := a.zipList(filepath.Join(a.Workdir, "test.zip"), 0)
+	files, err := a.list(context.Background(), filepath.Join(a.Workdir, "test.zip"))
 	if err != nil {
-		t.Fatalf("zipList failed: %v", err)
+		t.Fatalf("list failed: %v", err)
 	}
 
 	expected := []expectedFile{
@@ -158,9 +203,9 @@ func TestZipList(t *testing.T) {
 
 func TestCpioExtract(t *testing.T) {
 	a := newTestArchive(t)
-	extractedFiles, err := a.cpioExtract(filepath.Join(a.Workdir, "test.cpio"), []string{"foo/baar.txt"})
+	extractedFiles, err := a.extract(context.Background(), filepath.Join(a.Workdir, "test.cpio"), []string{"foo/baar.txt"})
 	if err != nil {
-		t.Fatalf("cpioExtract failed: %v", err)
+		t.Fatalf("extract failed: %v", err)
 	}
 	if len(extractedFiles) != 1 {
 		t.Fatalf("expected 1 file, got %d", len(extractedFiles))
@@ -169,8 +214,8 @@ func TestCpioExtract(t *testing.T) {
 	if file.Name != "foo/baar.txt" {
 		t.Errorf("unexpected file name: %s", file.Name)
 	}
-	if file.Content != "das Pferd isst Gurkensalat\n" {
-		t.Errorf("unexpected content in extracted file: %s", file.Content)
+	if string(file.RawContent) != "das Pferd isst Gurkensalat\n" {
+		t.Errorf("unexpected content in extracted file: %s", file.RawContent)
 	}
 	if file.Size != 27 {
 		t.Errorf("unexpected file size: %d", file.Size)
@@ -180,7 +225,7 @@ func TestCpioExtract(t *testing.T) {
 func TestCpioExtract_SizeLimit(t *testing.T) {
 	a := newTestArchive(t)
 	a.maxSize = 20
-	_, err := a.cpioExtract(filepath.Join(a.Workdir, "test.cpio"), []string{"foo/baar.txt"})
+	_, err := a.extract(context.Background(), filepath.Join(a.Workdir, "test.cpio"), []string{"foo/baar.txt"})
 	if err == nil {
 		t.Fatal("expected error for large file, but got nil")
 	}
@@ -191,9 +236,9 @@ func TestCpioExtract_SizeLimit(t *testing.T) {
 
 func TestTarGzExtract(t *testing.T) {
 	a := newTestArchive(t)
-	extractedFiles, err := a.tarGzExtract(filepath.Join(a.Workdir, "test.tar.gz"), []string{"foo/baar.txt"})
+	extractedFiles, err := a.extract(context.Background(), filepath.Join(a.Workdir, "test.tar.gz"), []string{"foo/baar.txt"})
 	if err != nil {
-		t.Fatalf("tarGzExtract failed: %v", err)
+		t.Fatalf("extract failed: %v", err)
 	}
 	if len(extractedFiles) != 1 {
 		t.Fatalf("expected 1 file, got %d", len(extractedFiles))
@@ -202,8 +247,8 @@ func TestTarGzExtract(t *testing.T) {
 	if file.Name != "foo/baar.txt" {
 		t.Errorf("unexpected file name: %s", file.Name)
 	}
-	if file.Content != "das Pferd isst Gurkensalat\n" {
-		t.Errorf("unexpected content in extracted file: %s", file.Content)
+	if string(file.RawContent) != "das Pferd isst Gurkensalat\n" {
+		t.Errorf("unexpected content in extracted file: %s", file.RawContent)
 	}
 	if file.Size != 27 {
 		t.Errorf("unexpected file size: %d", file.Size)
@@ -213,7 +258,7 @@ func TestTarGzExtract(t *testing.T) {
 func TestTarGzExtract_SizeLimit(t *testing.T) {
 	a := newTestArchive(t)
 	a.maxSize = 20
-	_, err := a.tarGzExtract(filepath.Join(a.Workdir, "test.tar.gz"), []string{"foo/baar.txt"})
+	_, err := a.extract(context.Background(), filepath.Join(a.Workdir, "test.tar.gz"), []string{"foo/baar.txt"})
 	if err == nil {
 		t.Fatal("expected error for large file, but got nil")
 	}
@@ -224,9 +269,9 @@ func TestTarGzExtract_SizeLimit(t *testing.T) {
 
 func TestTarBz2Extract(t *testing.T) {
 	a := newTestArchive(t)
-	extractedFiles, err := a.tarBz2Extract(filepath.Join(a.Workdir, "test.tar.bz2"), []string{"foo/baar.txt"})
+	extractedFiles, err := a.extract(context.Background(), filepath.Join(a.Workdir, "test.tar.bz2"), []string{"foo/baar.txt"})
 	if err != nil {
-		t.Fatalf("tarBz2Extract failed: %v", err)
+		t.Fatalf("extract failed: %v", err)
 	}
 	if len(extractedFiles) != 1 {
 		t.Fatalf("expected 1 file, got %d", len(extractedFiles))
@@ -235,8 +280,8 @@ func TestTarBz2Extract(t *testing.T) {
 	if file.Name != "foo/baar.txt" {
 		t.Errorf("unexpected file name: %s", file.Name)
 	}
-	if file.Content != "das Pferd isst Gurkensalat\n" {
-		t.Errorf("unexpected content in extracted file: %s", file.Content)
+	if string(file.RawContent) != "das Pferd isst Gurkensalat\n" {
+		t.Errorf("unexpected content in extracted file: %s", file.RawContent)
 	}
 	if file.Size != 27 {
 		t.Errorf("unexpected file size: %d", file.Size)
@@ -246,7 +291,7 @@ func TestTarBz2Extract(t *testing.T) {
 func TestTarBz2Extract_SizeLimit(t *testing.T) {
 	a := newTestArchive(t)
 	a.maxSize = 20
-	_, err := a.tarBz2Extract(filepath.Join(a.Workdir, "test.tar.bz2"), []string{"foo/baar.txt"})
+	_, err := a.extract(context.Background(), filepath.Join(a.Workdir, "test.tar.bz2"), []string{"foo/baar.txt"})
 	if err == nil {
 		t.Fatal("expected error for large file, but got nil")
 	}
@@ -257,9 +302,9 @@ func TestTarBz2Extract_SizeLimit(t *testing.T) {
 
 func TestTarXzExtract(t *testing.T) {
 	a := newTestArchive(t)
-	extractedFiles, err := a.tarXzExtract(filepath.Join(a.Workdir, "test.tar.xz"), []string{"foo/baar.txt"})
+	extractedFiles, err := a.extract(context.Background(), filepath.Join(a.Workdir, "test.tar.xz"), []string{"foo/baar.txt"})
 	if err != nil {
-		t.Fatalf("tarXzExtract failed: %v", err)
+		t.Fatalf("extract failed: %v", err)
 	}
 	if len(extractedFiles) != 1 {
 		t.Fatalf("expected 1 file, got %d", len(extractedFiles))
@@ -268,8 +313,8 @@ func TestTarXzExtract(t *testing.T) {
 	if file.Name != "foo/baar.txt" {
 		t.Errorf("unexpected file name: %s", file.Name)
 	}
-	if file.Content != "das Pferd isst Gurkensalat\n" {
-		t.Errorf("unexpected content in extracted file: %s", file.Content)
+	if string(file.RawContent) != "das Pferd isst Gurkensalat\n" {
+		t.Errorf("unexpected content in extracted file: %s", file.RawContent)
 	}
 	if file.Size != 27 {
 		t.Errorf("unexpected file size: %d", file.Size)
@@ -279,7 +324,7 @@ func TestTarXzExtract(t *testing.T) {
 func TestTarXzExtract_SizeLimit(t *testing.T) {
 	a := newTestArchive(t)
 	a.maxSize = 20
-	_, err := a.tarXzExtract(filepath.Join(a.Workdir, "test.tar.xz"), []string{"foo/baar.txt"})
+	_, err := a.extract(context.Background(), filepath.Join(a.Workdir, "test.tar.xz"), []string{"foo/baar.txt"})
 	if err == nil {
 		t.Fatal("expected error for large file, but got nil")
 	}
@@ -290,9 +335,9 @@ func TestTarXzExtract_SizeLimit(t *testing.T) {
 
 func TestZipExtract(t *testing.T) {
 	a := newTestArchive(t)
-	extractedFiles, err := a.zipExtract(filepath.Join(a.Workdir, "test.zip"), []string{"foo/baar.txt"})
+	extractedFiles, err := a.extract(context.Background(), filepath.Join(a.Workdir, "test.zip"), []string{"foo/baar.txt"})
 	if err != nil {
-		t.Fatalf("zipExtract failed: %v", err)
+		t.Fatalf("extract failed: %v", err)
 	}
 	if len(extractedFiles) != 1 {
 		t.Fatalf("expected 1 file, got %d", len(extractedFiles))
@@ -301,8 +346,8 @@ func TestZipExtract(t *testing.T) {
 	if file.Name != "foo/baar.txt" {
 		t.Errorf("unexpected file name: %s", file.Name)
 	}
-	if file.Content != "das Pferd isst Gurkensalat\n" {
-		t.Errorf("unexpected content in extracted file: %s", file.Content)
+	if string(file.RawContent) != "das Pferd isst Gurkensalat\n" {
+		t.Errorf("unexpected content in extracted file: %s", file.RawContent)
 	}
 	if file.Size != 27 {
 		t.Errorf("unexpected file size: %d", file.Size)
@@ -312,7 +357,7 @@ func TestZipExtract(t *testing.T) {
 func TestZipExtract_SizeLimit(t *testing.T) {
 	a := newTestArchive(t)
 	a.maxSize = 20
-	_, err := a.zipExtract(filepath.Join(a.Workdir, "test.zip"), []string{"foo/baar.txt"})
+	_, err := a.extract(context.Background(), filepath.Join(a.Workdir, "test.zip"), []string{"foo/baar.txt"})
 	if err == nil {
 		t.Fatal("expected error for large file, but got nil")
 	}
@@ -323,10 +368,11 @@ func TestZipExtract_SizeLimit(t *testing.T) {
 
 func TestCpioList_Depth(t *testing.T) {
 	a := newTestArchive(t)
-	files, err := a.cpioList(filepath.Join(a.Workdir, "test.cpio"), 1)
+	files, err := a.list(context.Background(), filepath.Join(a.Workdir, "test.cpio"))
 	if err != nil {
-		t.Fatalf("cpioList failed: %v", err)
+		t.Fatalf("list failed: %v", err)
 	}
+	files = filterDepth(files, 1)
 
 	expected := []expectedFile{
 		{name: "foo", size: 0},
@@ -345,10 +391,11 @@ func TestCpioList_Depth(t *testing.T) {
 
 func TestTarGzList_Depth(t *testing.T) {
 	a := newTestArchive(t)
-	files, err := a.tarGzList(filepath.Join(a.Workdir, "test.tar.gz"), 1)
+	files, err := a.list(context.Background(), filepath.Join(a.Workdir, "test.tar.gz"))
 	if err != nil {
-		t.Fatalf("tarGzList failed: %v", err)
+		t.Fatalf("list failed: %v", err)
 	}
+	files = filterDepth(files, 1)
 
 	expected := []expectedFile{
 		{name: "foo/", size: 0},
@@ -367,10 +414,11 @@ func TestTarGzList_Depth(t *testing.T) {
 
 func TestTarBz2List_Depth(t *testing.T) {
 	a := newTestArchive(t)
-	files, err := a.tarBz2List(filepath.Join(a.Workdir, "test.tar.bz2"), 1)
+	files, err := a.list(context.Background(), filepath.Join(a.Workdir, "test.tar.bz2"))
 	if err != nil {
-		t.Fatalf("tarBz2List failed: %v", err)
+		t.Fatalf("list failed: %v", err)
 	}
+	files = filterDepth(files, 1)
 
 	expected := []expectedFile{
 		{name: "foo/", size: 0},
@@ -389,10 +437,11 @@ func TestTarBz2List_Depth(t *testing.T) {
 
 func TestTarXzList_Depth(t *testing.T) {
 	a := newTestArchive(t)
-	files, err := a.tarXzList(filepath.Join(a.Workdir, "test.tar.xz"), 1)
+	files, err := a.list(context.Background(), filepath.Join(a.Workdir, "test.tar.xz"))
 	if err != nil {
-		t.Fatalf("tarXzList failed: %v", err)
+		t.Fatalf("list failed: %v", err)
 	}
+	files = filterDepth(files, 1)
 
 	expected := []expectedFile{
 		{name: "foo/", size: 0},
@@ -411,10 +460,11 @@ func TestTarXzList_Depth(t *testing.T) {
 
 func TestZipList_Depth(t *testing.T) {
 	a := newTestArchive(t)
-	files, err := a.zipList(filepath.Join(a.Workdir, "test.zip"), 1)
+	files, err := a.list(context.Background(), filepath.Join(a.Workdir, "test.zip"))
 	if err != nil {
-		t.Fatalf("zipList failed: %v", err)
+		t.Fatalf("list failed: %v", err)
 	}
+	files = filterDepth(files, 1)
 
 	expected := []expectedFile{
 		{name: "foo/", size: 0},
@@ -478,7 +528,7 @@ func TestSecurePath_Symlink(t *testing.T) {
 func TestListArchiveFilesAPI(t *testing.T) {
 	a := newTestArchive(t)
 	archiveTypes := []string{
-		"test.cpio", "test.tar.gz", "test.tar.bz2", "test.tar.xz", "test.zip",
+		"test.cpio", "test.cpio.gz", "test.cpio.xz", "test.tar.gz", "test.tar.bz2", "test.tar.xz", "test.tar.zst", "test.tar.sz", "test.zip",
 	}
 
 	for _, archiveType := range archiveTypes {
@@ -488,16 +538,11 @@ func TestListArchiveFilesAPI(t *testing.T) {
 				Depth: 0,
 			}
 			session := &mcp.ServerSession{}
-			_, result, err := a.ListArchiveFiles(context.Background(), &mcp.CallToolRequest{Session: session}, args)
+			_, listResult, err := a.ListArchiveFiles(context.Background(), &mcp.CallToolRequest{Session: session}, args)
 			if err != nil {
 				t.Fatalf("ListArchiveFiles failed for %s: %v", archiveType, err)
 			}
 
-			listResult, ok := result.(ListArchiveFilesResult)
-			if !ok {
-				t.Fatalf("unexpected result type: %T", result)
-			}
-
 			if listResult.TotalFiles < 3 {
 				t.Errorf("expected at least 3 files, got %d", listResult.TotalFiles)
 			}
@@ -508,7 +553,7 @@ func TestListArchiveFilesAPI(t *testing.T) {
 func TestExtractArchiveFilesAPI(t *testing.T) {
 	a := newTestArchive(t)
 	archiveTypes := []string{
-		"test.cpio", "test.tar.gz", "test.tar.bz2", "test.tar.xz", "test.zip",
+		"test.cpio", "test.cpio.gz", "test.cpio.xz", "test.tar.gz", "test.tar.bz2", "test.tar.xz", "test.tar.zst", "test.tar.sz", "test.zip",
 	}
 
 	for _, archiveType := range archiveTypes {
@@ -518,15 +563,11 @@ func TestExtractArchiveFilesAPI(t *testing.T) {
 				Files: []string{"foo/baar.txt"},
 			}
 			session := &mcp.ServerSession{}
-			_, result, err := a.ExtractArchiveFiles(context.Background(), &mcp.CallToolRequest{Session: session}, args)
+			_, extractResult, err := a.ExtractArchiveFiles(context.Background(), &mcp.CallToolRequest{Session: session}, args)
 			if err != nil {
 				t.Fatalf("ExtractArchiveFiles failed for %s: %v", archiveType, err)
 			}
 
-			extractResult, ok := result.(ExtractArchiveFilesResult)
-			if !ok {
-				t.Fatalf("unexpected result type: %T", result)
-			}
 			if len(extractResult.Files) != 1 {
 				t.Fatalf("expected 1 file, got %d", len(extractResult.Files))
 			}
@@ -540,3 +581,1487 @@ func TestExtractArchiveFilesAPI(t *testing.T) {
 		})
 	}
 }
+
+func TestList(t *testing.T) {
+	a := newTestArchive(t)
+	result, err := a.List(context.Background(), ListOptions{
+		Path:           filepath.Join(a.Workdir, "test.tar.gz"),
+		IncludePattern: `^foo/b`,
+		ExcludePattern: `bazz$`,
+	})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if result.TotalFiles != 3 {
+		t.Errorf("expected 3 total files, got %d", result.TotalFiles)
+	}
+	if result.FilteredFiles != 1 || len(result.Files) != 1 {
+		t.Fatalf("expected 1 filtered file, got %d", result.FilteredFiles)
+	}
+	if result.Files[0].Name != "foo/baar.txt" {
+		t.Errorf("unexpected file name: %s", result.Files[0].Name)
+	}
+}
+
+func TestList_Limit(t *testing.T) {
+	a := newTestArchive(t)
+	result, err := a.List(context.Background(), ListOptions{
+		Path:  filepath.Join(a.Workdir, "test.zip"),
+		Limit: 2,
+	})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if result.FilteredFiles != 3 {
+		t.Errorf("expected 3 filtered files, got %d", result.FilteredFiles)
+	}
+	if result.DisplayedFiles != 2 || len(result.Files) != 2 {
+		t.Errorf("expected 2 displayed files, got %d", result.DisplayedFiles)
+	}
+}
+
+func TestList_UnsupportedFormat(t *testing.T) {
+	a := newTestArchive(t)
+	_, err := a.List(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, "Makefile")})
+	if err == nil {
+		t.Fatal("expected error for unsupported format, but got nil")
+	}
+	if !strings.Contains(err.Error(), "unsupported archive format") {
+		t.Fatalf("expected unsupported format error, got: %v", err)
+	}
+}
+
+func TestExtract(t *testing.T) {
+	a := newTestArchive(t)
+	result, err := a.Extract(context.Background(), ExtractOptions{
+		Path:  filepath.Join(a.Workdir, "test.cpio"),
+		Files: []string{"foo/baar.txt", "foo/bazz"},
+	})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(result.Files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(result.Files))
+	}
+}
+
+func TestExtract_All(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "dup.tar.gz")
+	result, err := a.Extract(context.Background(), ExtractOptions{Path: path, All: true})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	var got []string
+	for _, f := range result.Files {
+		got = append(got, f.Name+"="+f.Content)
+	}
+	// Directories are left out by default.
+	want := []string{"foo/bazz=bazz\n", "foo/baar.txt=die Kuh isst Gurkensalat\n"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	include := false
+	result, err = a.Extract(context.Background(), ExtractOptions{Path: path, All: true, SkipDirectories: &include})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	got = got[:0]
+	for _, f := range result.Files {
+		got = append(got, f.Name+"="+f.Content)
+	}
+	want = []string{"foo/=", "foo/bazz=bazz\n", "foo/baar.txt=die Kuh isst Gurkensalat\n"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %q with directories, got %q", want, got)
+	}
+
+	if _, err := a.Extract(context.Background(), ExtractOptions{Path: path, All: true, Files: []string{"foo/bazz"}}); err == nil {
+		t.Error("expected all with files to fail")
+	}
+}
+
+func TestArchive_InvalidArgs(t *testing.T) {
+	a := newTestArchive(t)
+	zipPath := filepath.Join(a.Workdir, "test.zip")
+	for _, tc := range []struct {
+		name string
+		call func() error
+		want string
+	}{
+		{"extract without files", func() error {
+			_, err := a.Extract(context.Background(), ExtractOptions{Path: zipPath})
+			return err
+		}, "no files requested"},
+		{"extract with empty files", func() error {
+			_, err := a.Extract(context.Background(), ExtractOptions{Path: zipPath, Files: []string{}})
+			return err
+		}, "no files requested"},
+		{"extract without path", func() error {
+			_, err := a.Extract(context.Background(), ExtractOptions{Files: []string{"foo/bazz"}})
+			return err
+		}, "path is required"},
+		{"list without path", func() error {
+			_, err := a.List(context.Background(), ListOptions{})
+			return err
+		}, "path is required"},
+		{"extract from directory", func() error {
+			_, err := a.Extract(context.Background(), ExtractOptions{Path: a.Workdir, Files: []string{"foo/bazz"}})
+			return err
+		}, "path is a directory, not an archive"},
+		{"list directory", func() error {
+			_, err := a.List(context.Background(), ListOptions{Path: a.Workdir})
+			return err
+		}, "path is a directory, not an archive"},
+		{"info of directory", func() error {
+			_, err := a.Info(context.Background(), InfoOptions{Path: a.Workdir})
+			return err
+		}, "path is a directory, not an archive"},
+		{"stat without path", func() error {
+			_, err := a.Stat(context.Background(), StatOptions{File: "foo/bazz"})
+			return err
+		}, "path is required"},
+		{"count directory", func() error {
+			_, err := a.Count(context.Background(), WCOptions{Path: a.Workdir})
+			return err
+		}, "path is a directory, not an archive"},
+	} {
+		err := tc.call()
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestList_TypeFilter(t *testing.T) {
+	a := newTestArchive(t)
+	archiveTypes := []string{
+		"test.cpio", "test.tar.gz", "test.tar.bz2", "test.tar.xz", "test.zip",
+	}
+
+	for _, archiveType := range archiveTypes {
+		t.Run(archiveType, func(t *testing.T) {
+			path := filepath.Join(a.Workdir, archiveType)
+			result, err := a.List(context.Background(), ListOptions{Path: path, TypeFilter: "file"})
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			if len(result.Files) != 2 {
+				t.Fatalf("expected 2 files, got %d", len(result.Files))
+			}
+			for _, file := range result.Files {
+				if file.Type != "file" {
+					t.Errorf("unexpected type %q for %s", file.Type, file.Name)
+				}
+			}
+
+			result, err = a.List(context.Background(), ListOptions{Path: path, TypeFilter: "dir"})
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			if len(result.Files) != 1 {
+				t.Fatalf("expected 1 directory, got %d", len(result.Files))
+			}
+			if name := strings.TrimSuffix(result.Files[0].Name, "/"); name != "foo" {
+				t.Errorf("unexpected directory name: %s", result.Files[0].Name)
+			}
+		})
+	}
+}
+
+func TestList_TypeFilterImpliedDirs(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "nodirs.zip")
+	result, err := a.List(context.Background(), ListOptions{Path: path, TypeFilter: "dir"})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+
+	expected := []expectedFile{
+		{name: "foo/", size: 0},
+		{name: "foo/sub/", size: 0},
+	}
+	if len(result.Files) != len(expected) {
+		t.Fatalf("expected %d directories, got %v", len(expected), result.Files)
+	}
+	for _, exp := range expected {
+		if !containsFile(result.Files, exp) {
+			t.Errorf("expected directory '%v' not found in archive", exp)
+		}
+	}
+
+	result, err = a.List(context.Background(), ListOptions{Path: path})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if result.TotalFiles != 2 {
+		t.Errorf("expected implied directories only for the dir filter, got %d files", result.TotalFiles)
+	}
+}
+
+func TestList_SyntheticDirs(t *testing.T) {
+	a := newTestArchive(t)
+	synthetic, stripped := true, false
+	for _, tc := range []struct {
+		archive       string
+		syntheticDirs *bool
+		expected      []string
+	}{
+		{"test.zip", nil, []string{"foo/", "foo/baar.txt", "foo/bazz"}},
+		{"test.zip", &synthetic, []string{"foo/", "foo/baar.txt", "foo/bazz"}},
+		{"test.zip", &stripped, []string{"foo/baar.txt", "foo/bazz"}},
+		{"nodirs.zip", nil, []string{"foo/baar.txt", "foo/sub/bazz"}},
+		{"nodirs.zip", &synthetic, []string{"foo/", "foo/baar.txt", "foo/sub/", "foo/sub/bazz"}},
+		{"nodirs.zip", &stripped, []string{"foo/baar.txt", "foo/sub/bazz"}},
+	} {
+		result, err := a.List(context.Background(), ListOptions{
+			Path:          filepath.Join(a.Workdir, tc.archive),
+			SyntheticDirs: tc.syntheticDirs,
+		})
+		if err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		var names []string
+		for _, file := range result.Files {
+			names = append(names, file.Name)
+		}
+		slices.Sort(names)
+		if !slices.Equal(names, tc.expected) {
+			t.Errorf("%s with synthetic dirs %v: expected %v, got %v", tc.archive, tc.syntheticDirs, tc.expected, names)
+		}
+		if result.TotalFiles != len(tc.expected) {
+			t.Errorf("%s with synthetic dirs %v: expected %d total files, got %d", tc.archive, tc.syntheticDirs, len(tc.expected), result.TotalFiles)
+		}
+	}
+}
+
+func TestList_InvalidTypeFilter(t *testing.T) {
+	a := newTestArchive(t)
+	_, err := a.List(context.Background(), ListOptions{
+		Path:       filepath.Join(a.Workdir, "test.zip"),
+		TypeFilter: "socket",
+	})
+	if err == nil {
+		t.Fatal("expected error for invalid type filter, but got nil")
+	}
+}
+
+func TestList_Duplicates(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "dup.tar.gz")
+	result, err := a.List(context.Background(), ListOptions{Path: path})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if result.TotalFiles != 4 {
+		t.Errorf("expected 4 files including duplicates, got %d", result.TotalFiles)
+	}
+	if len(result.Duplicates) != 1 || result.Duplicates[0] != "foo/baar.txt" {
+		t.Errorf("unexpected duplicates: %v", result.Duplicates)
+	}
+}
+
+func TestList_Deduplicate(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "dup.tar.gz")
+	result, err := a.List(context.Background(), ListOptions{Path: path, Deduplicate: true})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+
+	expected := []expectedFile{
+		{name: "foo/", size: 0},
+		{name: "foo/bazz", size: 5},
+		{name: "foo/baar.txt", size: 25},
+	}
+	if len(result.Files) != len(expected) {
+		t.Fatalf("expected %d files, got %d", len(expected), len(result.Files))
+	}
+	for _, exp := range expected {
+		if !containsFile(result.Files, exp) {
+			t.Errorf("expected file '%v' not found in archive", exp)
+		}
+	}
+	if len(result.Duplicates) != 1 {
+		t.Errorf("unexpected duplicates: %v", result.Duplicates)
+	}
+}
+
+func TestExtract_Dedup(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "dup.tar.gz")
+	first := "das Pferd isst Gurkensalat\n"
+	last := "die Kuh isst Gurkensalat\n"
+	for _, tc := range []struct {
+		dedup string
+		want  []string
+	}{
+		{"", []string{last, "bazz\n"}},
+		{"last", []string{last, "bazz\n"}},
+		{"first", []string{first, "bazz\n"}},
+		{"all", []string{first, last, "bazz\n"}},
+	} {
+		result, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/baar.txt", "foo/bazz"}, Dedup: tc.dedup})
+		if err != nil {
+			t.Fatalf("Extract with dedup %q failed: %v", tc.dedup, err)
+		}
+		var got []string
+		for _, f := range result.Files {
+			got = append(got, f.Content)
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("dedup %q: expected %q, got %q", tc.dedup, tc.want, got)
+		}
+	}
+
+	if _, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/bazz"}, Dedup: "any"}); err == nil {
+		t.Error("expected an error for an invalid dedup value")
+	}
+}
+
+func TestExtract_Occurrence(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "dup.tar.gz")
+	for occurrence, want := range []string{"das Pferd isst Gurkensalat\n", "die Kuh isst Gurkensalat\n"} {
+		result, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"./foo/baar.txt"}, Occurrence: &occurrence})
+		if err != nil {
+			t.Fatalf("Extract of occurrence %d failed: %v", occurrence, err)
+		}
+		if len(result.Files) != 1 || result.Files[0].Content != want {
+			t.Errorf("occurrence %d: expected %q, got %+v", occurrence, want, result.Files)
+		}
+	}
+
+	outOfRange := 2
+	_, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/baar.txt"}, Occurrence: &outOfRange})
+	if err == nil || !strings.Contains(err.Error(), "occurrence 2 of foo/baar.txt out of range: archive has 2 entries") {
+		t.Errorf("expected an out of range error, got %v", err)
+	}
+
+	zero := 0
+	for _, opts := range []ExtractOptions{
+		{Path: path, Files: []string{"foo/baar.txt", "foo/bazz"}, Occurrence: &zero},
+		{Path: path, Occurrence: &zero},
+		{Path: path, Files: []string{"foo/baar.txt"}, Occurrence: &zero, Dedup: "all"},
+		{Path: path, Files: []string{"foo/baar.txt"}, Occurrence: &zero, Index: &zero},
+	} {
+		if _, err := a.Extract(context.Background(), opts); err == nil {
+			t.Errorf("expected invalid options %+v to fail", opts)
+		}
+	}
+}
+
+func TestZipList_DataOffset(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "test.zip")
+	result, err := a.List(context.Background(), ListOptions{Path: path})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+
+	var baar FileInfo
+	for _, file := range result.Files {
+		if file.Name == "foo/baar.txt" {
+			baar = file
+		}
+	}
+	if baar.DataOffset <= 0 {
+		t.Fatalf("expected a data offset, got %d", baar.DataOffset)
+	}
+	if baar.CompressedSize != 27 {
+		t.Fatalf("expected compressed size of stored entry to be 27, got %d", baar.CompressedSize)
+	}
+
+	// The entry is stored uncompressed, so its content can be read directly.
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open archive: %v", err)
+	}
+	defer f.Close()
+	buf := make([]byte, baar.CompressedSize)
+	if _, err := f.ReadAt(buf, baar.DataOffset); err != nil {
+		t.Fatalf("failed to read at data offset: %v", err)
+	}
+	if string(buf) != "das Pferd isst Gurkensalat\n" {
+		t.Errorf("unexpected content at data offset: %q", buf)
+	}
+}
+
+func TestZipList_UnreadableLocalHeader(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestZip(t, dir, "broken.zip", "a.txt", "a", "b.txt", "b")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read zip: %v", err)
+	}
+	// Corrupt the signature of the first local header, which only the
+	// central directory points to.
+	copy(data, "XXXX")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("failed to write zip: %v", err)
+	}
+	a, err := New(dir)
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	result, err := a.List(context.Background(), ListOptions{Path: path})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(result.Files) != 2 || result.Files[0].DataOffset != 0 || result.Files[1].DataOffset <= 0 {
+		t.Errorf("expected both entries, only b.txt with a data offset, got %+v", result.Files)
+	}
+}
+
+func TestTarGzList_NoDataOffset(t *testing.T) {
+	a := newTestArchive(t)
+	files, err := a.list(context.Background(), filepath.Join(a.Workdir, "test.tar.gz"))
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	for _, file := range files {
+		if file.DataOffset != 0 || file.CompressedSize != 0 {
+			t.Errorf("expected no offset information for %s, got %+v", file.Name, file)
+		}
+	}
+}
+
+func TestCheckWritable(t *testing.T) {
+	a := newTestArchive(t)
+	if err := a.checkWritable(); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected read-only error, got: %v", err)
+	}
+
+	a, err := New("../testdata", WithWritable(true))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	if err := a.checkWritable(); err != nil {
+		t.Fatalf("expected writable archive, got: %v", err)
+	}
+}
+
+func TestExtract_Raw(t *testing.T) {
+	a := newTestArchive(t)
+	result, err := a.Extract(context.Background(), ExtractOptions{
+		Path:  filepath.Join(a.Workdir, "test.tar.gz"),
+		Files: []string{"foo/baar.txt"},
+		Raw:   true,
+	})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(result.Files) != 1 {
+		t.Fatalf("expected 1 file, got %d", len(result.Files))
+	}
+	file := result.Files[0]
+	if string(file.RawContent) != "das Pferd isst Gurkensalat\n" {
+		t.Errorf("unexpected raw content in extracted file: %s", file.RawContent)
+	}
+	if file.Content != "" {
+		t.Errorf("expected empty content for raw extraction, got: %s", file.Content)
+	}
+}
+
+func TestExtract_InvalidEntrySize(t *testing.T) {
+	// Build a tar whose header declares an absurd size without the data
+	// to back it.
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{Name: "foo/evil", Mode: 0o644, Size: 1 << 50}); err != nil {
+		t.Fatalf("failed to write tar header: %v", err)
+	}
+	buf.Write(make([]byte, 1024))
+
+	dir := t.TempDir()
+	a, err := New(dir)
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := filepath.Join(a.Workdir, "evil.tar.gz")
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write(buf.Bytes())
+	zw.Close()
+	if err := os.WriteFile(path, gz.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+
+	_, err = a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/evil"}})
+	if err == nil {
+		t.Fatal("expected error for invalid entry size, but got nil")
+	}
+	if !strings.Contains(err.Error(), "invalid entry size") {
+		t.Fatalf("expected invalid entry size error, got: %v", err)
+	}
+}
+
+func TestReadEntry_NegativeSize(t *testing.T) {
+	a := newTestArchive(t)
+	e := &entry{
+		info: FileInfo{Name: "foo/evil", Size: -1},
+		open: func() (io.ReadCloser, error) {
+			t.Fatal("entry with negative size must not be opened")
+			return nil, nil
+		},
+	}
+	_, err := a.readEntry(e)
+	if err == nil || !strings.Contains(err.Error(), "invalid entry size") {
+		t.Fatalf("expected invalid entry size error, got: %v", err)
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	for name, want := range map[string]string{
+		"foo/baar.txt":    "foo/baar.txt",
+		"./foo/baar.txt":  "foo/baar.txt",
+		"././foo//baar":   "foo/baar",
+		".//foo///baar/":  "foo/baar/",
+		"../foo/baar.txt": "../foo/baar.txt",
+	} {
+		if got := normalizeName(name); got != want {
+			t.Errorf("normalizeName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestExtract_NormalizedName(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "dotslash.tar.gz")
+	for _, name := range []string{"foo/baar.txt", "./foo/baar.txt", "foo//baar.txt"} {
+		result, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{name}})
+		if err != nil {
+			t.Fatalf("Extract failed for %s: %v", name, err)
+		}
+		if len(result.Files) != 1 {
+			t.Fatalf("expected 1 file for %s, got %d", name, len(result.Files))
+		}
+		file := result.Files[0]
+		if file.Name != "./foo/baar.txt" {
+			t.Errorf("expected the entry name as stored in the archive, got %s", file.Name)
+		}
+		if file.Content != "das Pferd isst Gurkensalat\n" {
+			t.Errorf("unexpected content in extracted file: %s", file.Content)
+		}
+	}
+}
+
+func TestList_ArchiveHash(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "test.zip")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read archive: %v", err)
+	}
+	sum := sha256.Sum256(data)
+	want := hex.EncodeToString(sum[:])
+
+	result, err := a.List(context.Background(), ListOptions{Path: path, IncludeArchiveHash: true})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if result.ArchiveSHA256 != want {
+		t.Errorf("expected hash %s, got %s", want, result.ArchiveSHA256)
+	}
+
+	result, err = a.List(context.Background(), ListOptions{Path: path})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if result.ArchiveSHA256 != "" {
+		t.Errorf("expected no hash without IncludeArchiveHash, got %s", result.ArchiveSHA256)
+	}
+}
+
+func TestExtract_ZipSizeInDataDescriptor(t *testing.T) {
+	// Build a zip whose central directory declares an uncompressed size of
+	// zero, as written by some streaming zip writers.
+	content := []byte("das Pferd isst Gurkensalat\n")
+	var compressed bytes.Buffer
+	fw, err := flate.NewWriter(&compressed, flate.DefaultCompression)
+	if err != nil {
+		t.Fatalf("failed to create deflate writer: %v", err)
+	}
+	fw.Write(content)
+	fw.Close()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, h := range []*zip.FileHeader{
+		{Name: "foo/stored.txt", Method: zip.Store, CompressedSize64: uint64(len(content))},
+		{Name: "foo/deflated.txt", Method: zip.Deflate, CompressedSize64: uint64(compressed.Len())},
+	} {
+		h.CRC32 = crc32.ChecksumIEEE(content)
+		w, err := zw.CreateRaw(h)
+		if err != nil {
+			t.Fatalf("failed to create zip entry: %v", err)
+		}
+		if h.Method == zip.Store {
+			w.Write(content)
+		} else {
+			w.Write(compressed.Bytes())
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to write zip: %v", err)
+	}
+
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := filepath.Join(a.Workdir, "streamed.zip")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+
+	result, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/stored.txt", "foo/deflated.txt"}})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(result.Files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(result.Files))
+	}
+	for _, file := range result.Files {
+		if file.Content != string(content) {
+			t.Errorf("unexpected content in %s: %q", file.Name, file.Content)
+		}
+		if file.Size != int64(len(content)) {
+			t.Errorf("expected size %d for %s, got %d", len(content), file.Name, file.Size)
+		}
+	}
+
+	a.maxSize = 10
+	_, err = a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/deflated.txt"}})
+	if err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Fatalf("expected too large error, got: %v", err)
+	}
+}
+
+func TestList_Xattrs(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	err := tw.WriteHeader(&tar.Header{
+		Name:     "foo/labeled",
+		Mode:     0o644,
+		Typeflag: tar.TypeReg,
+		Format:   tar.FormatPAX,
+		PAXRecords: map[string]string{
+			"SCHILY.xattr.security.selinux": "system_u:object_r:etc_t:s0",
+			"SCHILY.acl.access":             "user::rw-,group::r--,other::r--",
+			"comment":                       "not an xattr",
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to write tar header: %v", err)
+	}
+	tw.Close()
+
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := filepath.Join(a.Workdir, "xattrs.tar.gz")
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write(buf.Bytes())
+	zw.Close()
+	if err := os.WriteFile(path, gz.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+
+	result, err := a.List(context.Background(), ListOptions{Path: path, IncludeXattrs: true})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(result.Files) != 1 {
+		t.Fatalf("expected 1 file, got %d", len(result.Files))
+	}
+	xattrs := result.Files[0].Xattrs
+	if len(xattrs) != 2 {
+		t.Errorf("expected 2 xattrs, got %v", xattrs)
+	}
+	if xattrs["security.selinux"] != "system_u:object_r:etc_t:s0" {
+		t.Errorf("unexpected SELinux label: %v", xattrs)
+	}
+	if xattrs["SCHILY.acl.access"] != "user::rw-,group::r--,other::r--" {
+		t.Errorf("unexpected ACL: %v", xattrs)
+	}
+
+	result, err = a.List(context.Background(), ListOptions{Path: path})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if result.Files[0].Xattrs != nil {
+		t.Errorf("expected no xattrs without IncludeXattrs, got %v", result.Files[0].Xattrs)
+	}
+}
+
+func TestList_CaseCollisions(t *testing.T) {
+	a := newTestArchive(t)
+	result, err := a.List(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, "case.tar.gz")})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(result.CaseCollisions) != 1 {
+		t.Fatalf("expected 1 case collision, got %v", result.CaseCollisions)
+	}
+	if got := strings.Join(result.CaseCollisions[0], ","); got != "foo/baar.txt,foo/Baar.txt" {
+		t.Errorf("unexpected case collision: %s", got)
+	}
+
+	result, err = a.List(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, "dup.tar.gz")})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(result.CaseCollisions) != 0 {
+		t.Errorf("expected exact duplicates not to be reported as case collisions, got %v", result.CaseCollisions)
+	}
+}
+
+func TestExtract_Index(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "dup.tar.gz")
+	files, err := a.list(context.Background(), path)
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	for index, file := range files {
+		result, err := a.Extract(context.Background(), ExtractOptions{Path: path, Index: &index})
+		if err != nil {
+			t.Fatalf("Extract failed for index %d: %v", index, err)
+		}
+		if len(result.Files) != 1 {
+			t.Fatalf("expected 1 file for index %d, got %d", index, len(result.Files))
+		}
+		if result.Files[0].Name != file.Name || result.Files[0].Size != file.Size {
+			t.Errorf("expected %s of size %d for index %d, got %s of size %d", file.Name, file.Size, index, result.Files[0].Name, result.Files[0].Size)
+		}
+	}
+	// The later occurrence of a duplicate name can be addressed on its own.
+	last := len(files) - 1
+	result, err := a.Extract(context.Background(), ExtractOptions{Path: path, Index: &last})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if result.Files[0].Content != "die Kuh isst Gurkensalat\n" {
+		t.Errorf("unexpected content for index %d: %q", last, result.Files[0].Content)
+	}
+
+	for _, tc := range []struct {
+		index int
+		files []string
+		err   string
+	}{
+		{index: 4, err: "index 4 out of range: archive has 4 entries"},
+		{index: -1, err: "index -1 out of range"},
+		{index: 0, files: []string{"foo/bazz"}, err: "mutually exclusive"},
+	} {
+		_, err := a.Extract(context.Background(), ExtractOptions{Path: path, Index: &tc.index, Files: tc.files})
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("expected error %q for index %d, got: %v", tc.err, tc.index, err)
+		}
+	}
+}
+
+func TestExtract_DirAndSymlink(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "symlink.tar.gz")
+
+	result, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/", "foo/link", "foo/baar.txt"}})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	want := map[string]File{
+		"foo/":         {Name: "foo/", Type: typeDir, Permissions: "drwxr-xr-x"},
+		"foo/link":     {Name: "foo/link", Type: typeSymlink, Permissions: "Lrwxrwxrwx", LinkTarget: "baar.txt"},
+		"foo/baar.txt": {Name: "foo/baar.txt", Type: typeFile, Permissions: "-rw-r--r--", Size: 27, Content: "das Pferd isst Gurkensalat\n"},
+	}
+	if len(result.Files) != len(want) {
+		t.Fatalf("expected %d files, got %v", len(want), result.Files)
+	}
+	for _, file := range result.Files {
+		file.mode = 0
+		if !reflect.DeepEqual(file, want[file.Name]) {
+			t.Errorf("got %+v, want %+v", file, want[file.Name])
+		}
+	}
+
+	_, err = a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/link"}, RangeStart: 1})
+	if err == nil || !strings.Contains(err.Error(), "which is a symlink") {
+		t.Errorf("expected byte range error for symlink, got: %v", err)
+	}
+}
+
+func TestExtract_SkipDirectories(t *testing.T) {
+	a := newTestArchive(t)
+	for _, archiveType := range []string{"test.cpio", "test.tar.gz", "test.zip"} {
+		t.Run(archiveType, func(t *testing.T) {
+			// The directory is named foo in cpio and foo/ in the others.
+			opts := ExtractOptions{Path: filepath.Join(a.Workdir, archiveType), Files: []string{"foo", "foo/", "foo/baar.txt", "foo/bazz"}}
+			result, err := a.Extract(context.Background(), opts)
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+			if len(result.Files) != 3 || result.Files[0].Type != typeDir || result.Files[0].Content != "" {
+				t.Errorf("expected the directory and two files, got %+v", result.Files)
+			}
+
+			skip := true
+			opts.SkipDirectories = &skip
+			result, err = a.Extract(context.Background(), opts)
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+			if len(result.Files) != 2 || result.Files[0].Name != "foo/baar.txt" || result.Files[1].Name != "foo/bazz" {
+				t.Errorf("expected only the two files, got %+v", result.Files)
+			}
+		})
+	}
+}
+
+func TestExtract_ZipSymlink(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	h := &zip.FileHeader{Name: "foo/link"}
+	h.SetMode(fs.ModeSymlink | 0o777)
+	w, err := zw.CreateHeader(h)
+	if err != nil {
+		t.Fatalf("failed to create zip entry: %v", err)
+	}
+	w.Write([]byte("baar.txt"))
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to write zip: %v", err)
+	}
+
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := filepath.Join(a.Workdir, "symlink.zip")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+
+	result, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/link"}})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].Type != typeSymlink || result.Files[0].LinkTarget != "baar.txt" || result.Files[0].Content != "" {
+		t.Errorf("unexpected symlink extraction: %+v", result.Files)
+	}
+}
+
+func TestList_TopN(t *testing.T) {
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := writeSyntheticTar(t, a.Workdir, map[string]string{
+		"a.txt":     "1",
+		"b.txt":     "1234",
+		"c.txt":     "12",
+		"d.txt":     "12345",
+		"e.log":     "123456",
+		"sub/f.txt": "123",
+	})
+
+	result, err := a.List(context.Background(), ListOptions{Path: path, TopN: 2, ExcludePattern: `\.log$`})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	var names []string
+	for _, file := range result.Files {
+		names = append(names, file.Name)
+	}
+	if got := strings.Join(names, ","); got != "d.txt,b.txt" {
+		t.Errorf("expected the two largest files d.txt,b.txt, got %s", got)
+	}
+	if result.FilteredFiles != 5 || result.DisplayedFiles != 2 {
+		t.Errorf("expected 5 filtered and 2 displayed files, got %d and %d", result.FilteredFiles, result.DisplayedFiles)
+	}
+
+	result, err = a.List(context.Background(), ListOptions{Path: path, TopN: 3, Limit: 1})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].Name != "e.log" {
+		t.Errorf("expected the limit to apply after top_n, got %v", result.Files)
+	}
+
+	if _, err := a.List(context.Background(), ListOptions{Path: path, TopN: -1}); err == nil || !strings.Contains(err.Error(), "invalid top_n") {
+		t.Errorf("expected invalid top_n error, got: %v", err)
+	}
+}
+
+func TestList_GNULongName(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "longname.tar.gz")
+	longDir := "foo/" + strings.Repeat("d", 100) + "/"
+	longName := longDir + strings.Repeat("f", 95) + ".txt"
+
+	result, err := a.List(context.Background(), ListOptions{Path: path})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	var names []string
+	for _, file := range result.Files {
+		if strings.Contains(file.Name, "@LongLink") {
+			t.Errorf("unexpected pseudo-entry %s", file.Name)
+		}
+		names = append(names, file.Name)
+	}
+	slices.Sort(names)
+	want := []string{"foo/", longDir, longName, "foo/link"}
+	slices.Sort(want)
+	if !slices.Equal(names, want) {
+		t.Errorf("expected %v, got %v", want, names)
+	}
+
+	extracted, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{longName, "foo/link"}})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(extracted.Files) != 2 {
+		t.Fatalf("expected 2 files, got %v", extracted.Files)
+	}
+	for _, file := range extracted.Files {
+		switch file.Name {
+		case longName:
+			if file.Content != "das Pferd isst Gurkensalat\n" {
+				t.Errorf("unexpected content %q", file.Content)
+			}
+		case "foo/link":
+			if file.LinkTarget != "../"+longName {
+				t.Errorf("unexpected link target %q", file.LinkTarget)
+			}
+		}
+	}
+}
+
+func TestStripComponents(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		n    int
+		want string
+		ok   bool
+	}{
+		{"dist/bin/tool", 1, "bin/tool", true},
+		{"dist/bin/tool", 2, "tool", true},
+		{"./dist/bin/tool", 1, "bin/tool", true},
+		{"dist/bin/", 1, "bin/", true},
+		{"dist/bin/tool", 3, "", false},
+		{"dist/", 1, "", false},
+		{"tool", 1, "", false},
+	} {
+		got, ok := stripComponents(tc.name, tc.n)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("stripComponents(%q, %d) = %q, %v, want %q, %v", tc.name, tc.n, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestExtract_StripComponents(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "test.tar.gz")
+	files := []string{"foo/", "foo/baar.txt", "foo/bazz"}
+
+	result, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: files, StripComponents: 1})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	var names []string
+	for _, file := range result.Files {
+		names = append(names, file.Name)
+	}
+	slices.Sort(names)
+	if got := strings.Join(names, ","); got != "baar.txt,bazz" {
+		t.Errorf("expected baar.txt,bazz, got %s", got)
+	}
+
+	// Stripping beyond the depth of all entries leaves nothing.
+	result, err = a.Extract(context.Background(), ExtractOptions{Path: path, Files: files, StripComponents: 2})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(result.Files) != 0 {
+		t.Errorf("expected no files, got %v", result.Files)
+	}
+
+	_, err = a.Extract(context.Background(), ExtractOptions{Path: path, Files: files, StripComponents: -1})
+	if err == nil || !strings.Contains(err.Error(), "invalid strip_components") {
+		t.Errorf("expected invalid strip_components error, got: %v", err)
+	}
+}
+
+func TestExtract_RequestedOrder(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "test.tar.gz")
+	files, err := a.list(context.Background(), path)
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	var archiveOrder []string
+	for _, file := range files {
+		if file.Type == typeFile {
+			archiveOrder = append(archiveOrder, file.Name)
+		}
+	}
+	requested := slices.Clone(archiveOrder)
+	slices.Reverse(requested)
+	// Names requested twice are returned once, at their first position.
+	requested = append(requested, requested[0], "foo/missing")
+
+	result, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: requested})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	var names []string
+	for _, file := range result.Files {
+		names = append(names, file.Name)
+	}
+	want := slices.Clone(archiveOrder)
+	slices.Reverse(want)
+	if !slices.Equal(names, want) {
+		t.Errorf("expected files in requested order %v, got %v", want, names)
+	}
+}
+
+// zip64Entry is a stored entry of a synthetic zip64 archive. Its declared
+// size may differ from the length of its data.
+type zip64Entry struct {
+	name string
+	data string
+	size uint64
+}
+
+// writeZip64 writes a zip archive whose entries and end of central
+// directory all use zip64 records, as if the archive were larger than 4 GiB.
+func writeZip64(t *testing.T, dir string, entries []zip64Entry) string {
+	var buf bytes.Buffer
+	le := func(v any) { binary.Write(&buf, binary.LittleEndian, v) }
+	offsets := make([]uint64, len(entries))
+	for i, e := range entries {
+		offsets[i] = uint64(buf.Len())
+		le([]uint32{0x04034b50})
+		le([]uint16{45, 0, uint16(zip.Store), 0, 0})
+		le([]uint32{crc32.ChecksumIEEE([]byte(e.data)), 0xffffffff, 0xffffffff})
+		le([]uint16{uint16(len(e.name)), 20})
+		buf.WriteString(e.name)
+		le([]uint16{1, 16})
+		le([]uint64{e.size, uint64(len(e.data))})
+		buf.WriteString(e.data)
+	}
+	cdStart := uint64(buf.Len())
+	for i, e := range entries {
+		le([]uint32{0x02014b50})
+		le([]uint16{3<<8 | 45, 45, 0, uint16(zip.Store), 0, 0})
+		le([]uint32{crc32.ChecksumIEEE([]byte(e.data)), 0xffffffff, 0xffffffff})
+		le([]uint16{uint16(len(e.name)), 28, 0, 0, 0})
+		le([]uint32{0o100644 << 16, 0xffffffff})
+		buf.WriteString(e.name)
+		le([]uint16{1, 24})
+		le([]uint64{e.size, uint64(len(e.data)), offsets[i]})
+	}
+	cdEnd := uint64(buf.Len())
+	le([]uint32{0x06064b50})
+	le([]uint64{44})
+	le([]uint16{45, 45})
+	le([]uint32{0, 0})
+	le([]uint64{uint64(len(entries)), uint64(len(entries)), cdEnd - cdStart, cdStart})
+	le([]uint32{0x07064b50, 0})
+	le([]uint64{cdEnd})
+	le([]uint32{1})
+	le([]uint32{0x06054b50})
+	le([]uint16{0xffff, 0xffff, 0xffff, 0xffff})
+	le([]uint32{0xffffffff, 0xffffffff})
+	le([]uint16{0})
+
+	path := filepath.Join(dir, "zip64.zip")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write zip64 archive: %v", err)
+	}
+	return path
+}
+
+func TestZip64(t *testing.T) {
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	content := "das Pferd isst Gurkensalat\n"
+	path := writeZip64(t, a.Workdir, []zip64Entry{
+		{name: "small.txt", data: content, size: uint64(len(content))},
+		// The size field is that of an entry beyond 4 GiB.
+		{name: "huge.bin", data: content, size: 5 << 30},
+		// Sizes beyond math.MaxInt64 must not turn negative.
+		{name: "crafted.bin", data: content, size: 1<<63 + 1},
+	})
+	ctx := context.Background()
+
+	result, err := a.List(ctx, ListOptions{Path: path})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	sizes := make(map[string]int64)
+	for _, f := range result.Files {
+		sizes[f.Name] = f.Size
+	}
+	want := map[string]int64{"small.txt": int64(len(content)), "huge.bin": 5 << 30, "crafted.bin": math.MaxInt64}
+	if !reflect.DeepEqual(sizes, want) {
+		t.Errorf("listed sizes %v, want %v", sizes, want)
+	}
+
+	extracted, err := a.Extract(ctx, ExtractOptions{Path: path, Files: []string{"small.txt"}})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(extracted.Files) != 1 || extracted.Files[0].Content != content {
+		t.Errorf("unexpected extracted files: %+v", extracted.Files)
+	}
+
+	_, err = a.Extract(ctx, ExtractOptions{Path: path, Files: []string{"huge.bin"}})
+	var rerr *rejectedError
+	if !errors.As(err, &rerr) || rerr.reason != reasonTooLarge {
+		t.Errorf("expected huge.bin to be too large, got: %v", err)
+	}
+	_, err = a.Extract(ctx, ExtractOptions{Path: path, Files: []string{"crafted.bin"}})
+	if !errors.As(err, &rerr) || rerr.reason != reasonInvalidSize {
+		t.Errorf("expected crafted.bin to have an invalid size, got: %v", err)
+	}
+}
+
+// connectTools serves the read-only tools of a over an in-memory transport
+// and returns a connected client session.
+func connectTools(t *testing.T, a *Archive) *mcp.ClientSession {
+	ctx := context.Background()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "list_archive_files"}, a.ListArchiveFiles)
+	mcp.AddTool(server, &mcp.Tool{Name: "extract_archive_files"}, a.ExtractArchiveFiles)
+	mcp.AddTool(server, &mcp.Tool{Name: "stat_archive_file"}, a.StatArchiveFile)
+	mcp.AddTool(server, &mcp.Tool{Name: "extract_matching"}, a.ExtractMatchingFiles)
+	mcp.AddTool(server, &mcp.Tool{Name: "extract_grep"}, a.ExtractGrep)
+	mcp.AddTool(server, &mcp.Tool{Name: "extract_concat"}, a.ExtractConcat)
+	mcp.AddTool(server, &mcp.Tool{Name: "extract_by_hash"}, a.ExtractFilesByHash)
+	mcp.AddTool(server, &mcp.Tool{Name: "archive_wc"}, a.ArchiveWC)
+	mcp.AddTool(server, &mcp.Tool{Name: "largest_dirs"}, a.ListLargestDirs)
+	mcp.AddTool(server, &mcp.Tool{Name: "probe_archive"}, a.ProbeArchive)
+	mcp.AddTool(server, &mcp.Tool{Name: "archive_info"}, a.ArchiveInfo)
+	mcp.AddTool(server, &mcp.Tool{Name: "archive_manifest"}, a.ArchiveManifest)
+	mcp.AddTool(server, &mcp.Tool{Name: "list_changed"}, a.ListChanged)
+	mcp.AddTool(server, &mcp.Tool{Name: "verify_contents"}, a.VerifyContents)
+	mcp.AddTool(server, &mcp.Tool{Name: "list_archives"}, a.ListArchives)
+	mcp.AddTool(server, &mcp.Tool{Name: "resolve_path"}, a.ResolvePath)
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect server: %v", err)
+	}
+	t.Cleanup(func() { ss.Close() })
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect client: %v", err)
+	}
+	t.Cleanup(func() { cs.Close() })
+	return cs
+}
+
+func TestTools_StructuredContent(t *testing.T) {
+	a := newTestArchive(t)
+	cs := connectTools(t, a)
+	ctx := context.Background()
+
+	tools, err := cs.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	for _, tool := range tools.Tools {
+		if tool.OutputSchema == nil {
+			t.Errorf("tool %s has no output schema", tool.Name)
+		}
+	}
+
+	path := filepath.Join(a.Workdir, "test.tar.gz")
+	for _, tc := range []struct {
+		tool string
+		args map[string]any
+		want []string
+	}{
+		{"list_archive_files", map[string]any{"path": path, "depth": 0}, []string{"files", "total_files"}},
+		// Empty results must still match the output schema.
+		{"list_archive_files", map[string]any{"path": path, "depth": 0, "include": "nomatch"}, []string{"files"}},
+		{"extract_archive_files", map[string]any{"path": path, "files": []string{"foo/baar.txt"}}, []string{"files"}},
+		{"stat_archive_file", map[string]any{"path": path, "file": "foo/baar.txt"}, []string{"found", "file"}},
+		{"extract_matching", map[string]any{"path": path, "pattern": "Pferd"}, []string{"files", "scanned"}},
+		{"extract_grep", map[string]any{"path": path, "pattern": "Pferd"}, []string{"files", "scanned"}},
+		{"extract_concat", map[string]any{"path": path, "files": []string{"foo/bazz", "foo/baar.txt"}}, []string{"content", "size"}},
+		{"extract_by_hash", map[string]any{"path": path, "hashes": []string{sha256Hex("bazz\n")}}, []string{"files", "missing", "scanned"}},
+		{"archive_wc", map[string]any{"path": path}, []string{"files", "total"}},
+		{"largest_dirs", map[string]any{"path": path}, []string{"dirs", "total_size"}},
+		{"probe_archive", map[string]any{"path": path}, []string{"supported", "encrypted", "solid", "entries"}},
+		{"archive_info", map[string]any{"path": path}, []string{"format", "entries"}},
+		{"list_changed", map[string]any{"path": path, "baseline": map[string]string{}}, []string{"changed", "added", "removed"}},
+		{"verify_contents", map[string]any{"path": path, "expected": []string{}}, []string{"passed", "missing", "unexpected"}},
+		{"list_archives", map[string]any{"pattern": filepath.Join(a.Workdir, "test.*"), "depth": 0}, []string{"archives"}},
+		{"resolve_path", map[string]any{"path": path}, []string{"status", "path"}},
+	} {
+		t.Run(tc.tool, func(t *testing.T) {
+			res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: tc.tool, Arguments: tc.args})
+			if err != nil {
+				t.Fatalf("CallTool failed: %v", err)
+			}
+			if res.IsError {
+				t.Fatalf("tool failed: %+v", res.Content)
+			}
+			structured, err := json.Marshal(res.StructuredContent)
+			if err != nil {
+				t.Fatalf("failed to marshal structured content: %v", err)
+			}
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(structured, &fields); err != nil {
+				t.Fatalf("structured content is not a JSON object: %s", structured)
+			}
+			for _, key := range tc.want {
+				if _, ok := fields[key]; !ok {
+					t.Errorf("structured content lacks %q: %s", key, structured)
+				}
+			}
+			// The text content carries the same JSON for clients without
+			// structured content support.
+			if len(res.Content) != 1 {
+				t.Fatalf("expected a single content block, got %d", len(res.Content))
+			}
+			text, ok := res.Content[0].(*mcp.TextContent)
+			if !ok {
+				t.Fatalf("unexpected content type %T", res.Content[0])
+			}
+			var textFields map[string]json.RawMessage
+			if err := json.Unmarshal([]byte(text.Text), &textFields); err != nil || !reflect.DeepEqual(mapKeys(textFields), mapKeys(fields)) {
+				t.Errorf("text content %s does not match structured content %s", text.Text, structured)
+			}
+		})
+	}
+}
+
+func mapKeys(m map[string]json.RawMessage) []string {
+	return slices.Sorted(maps.Keys(m))
+}
+
+func TestWithFormats(t *testing.T) {
+	a, err := New("../testdata", WithFormats("zip", "tar.gz"), WithAutoUnwrap(true))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	ctx := context.Background()
+	for _, name := range []string{"test.zip", "test.tar.gz", "nested.tar.gz.gz"} {
+		if _, err := a.List(ctx, ListOptions{Path: filepath.Join(a.Workdir, name)}); err != nil {
+			t.Errorf("List %s failed: %v", name, err)
+		}
+	}
+	for _, name := range []string{"test.tar.xz", "test.cpio", "test.gz"} {
+		_, err := a.List(ctx, ListOptions{Path: filepath.Join(a.Workdir, name)})
+		if !errors.Is(err, ErrFormatDisabled) {
+			t.Errorf("List %s: expected ErrFormatDisabled, got %v", name, err)
+		}
+	}
+	if _, err := a.Info(ctx, InfoOptions{Path: filepath.Join(a.Workdir, "test.tar.bz2")}); !errors.Is(err, ErrFormatDisabled) {
+		t.Errorf("Info: expected ErrFormatDisabled, got %v", err)
+	}
+	if err := a.SelfTest(ctx); err != nil {
+		t.Errorf("SelfTest failed: %v", err)
+	}
+
+	if _, err := New("../testdata", WithFormats("rar")); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestList_NamesOnly(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "test.tar.gz")
+	full, err := a.List(context.Background(), ListOptions{Path: path})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	result, err := a.List(context.Background(), ListOptions{Path: path, NamesOnly: true})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if got := strings.Join(result.Names, ","); got != "foo/,foo/baar.txt,foo/bazz" {
+		t.Errorf("unexpected names: %s", got)
+	}
+	if len(result.Files) != 0 || result.DisplayedFiles != 3 {
+		t.Errorf("expected no file metadata and 3 displayed files, got %v and %d", result.Files, result.DisplayedFiles)
+	}
+	fullJSON, _ := json.Marshal(full)
+	namesJSON, _ := json.Marshal(result)
+	if len(namesJSON) >= len(fullJSON)/2 {
+		t.Errorf("expected the names only listing to be much smaller: %d vs %d bytes", len(namesJSON), len(fullJSON))
+	}
+
+	// Filters and the limit still apply.
+	result, err = a.List(context.Background(), ListOptions{Path: path, NamesOnly: true, TypeFilter: typeFile, Limit: 1})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if got := strings.Join(result.Names, ","); got != "foo/baar.txt" {
+		t.Errorf("unexpected filtered names: %s", got)
+	}
+}
+
+func TestList_SizeMap(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "test.tar.gz")
+	result, err := a.List(context.Background(), ListOptions{Path: path, SizeMap: true, TypeFilter: typeFile})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(result.Files) != 0 {
+		t.Errorf("expected no file metadata, got %v", result.Files)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("failed to marshal result: %v", err)
+	}
+	var got struct {
+		Sizes map[string]int64 `json:"sizes"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("failed to unmarshal result: %v", err)
+	}
+	want := map[string]int64{"foo/baar.txt": 27, "foo/bazz": 5}
+	if !maps.Equal(got.Sizes, want) {
+		t.Errorf("expected sizes %v, got %v in %s", want, got.Sizes, data)
+	}
+
+	// The depth filter applies.
+	result, err = a.List(context.Background(), ListOptions{Path: path, SizeMap: true, Depth: 1})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if !maps.Equal(result.Sizes, map[string]int64{"foo/": 0}) {
+		t.Errorf("unexpected sizes at depth 1: %v", result.Sizes)
+	}
+
+	if _, err := a.List(context.Background(), ListOptions{Path: path, SizeMap: true, NamesOnly: true}); err == nil {
+		t.Error("expected size_map with names_only to fail")
+	}
+}
+
+func TestList_Owners(t *testing.T) {
+	a := newTestArchive(t)
+	files, err := a.list(context.Background(), filepath.Join(a.Workdir, "owners.tar.gz"))
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	type owner struct {
+		uid, gid     int
+		uname, gname string
+	}
+	want := map[string]owner{
+		"srv/www/index.html": {30, 8, "wwwrun", "www"},
+		"srv/data":           {1000, 100, "", ""},
+		"srv/long":           {0, 0, "a-user-name-longer-than-32-characters", "root"},
+	}
+	if len(files) != len(want) {
+		t.Fatalf("expected %d files, got %+v", len(want), files)
+	}
+	for _, file := range files {
+		if file.UID == nil || file.GID == nil {
+			t.Fatalf("%s: missing numeric ids", file.Name)
+		}
+		got := owner{*file.UID, *file.GID, file.Uname, file.Gname}
+		if got != want[file.Name] {
+			t.Errorf("%s: expected owner %+v, got %+v", file.Name, want[file.Name], got)
+		}
+	}
+
+	data, err := json.Marshal(files[1])
+	if err != nil {
+		t.Fatalf("failed to marshal file: %v", err)
+	}
+	if strings.Contains(string(data), "uname") || !strings.Contains(string(data), `"uid":1000`) {
+		t.Errorf("expected numeric ids without names, got %s", data)
+	}
+
+	// Zip archives store no owners.
+	files, err = a.list(context.Background(), filepath.Join(a.Workdir, "test.zip"))
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if files[0].UID != nil || files[0].Uname != "" {
+		t.Errorf("unexpected owner of zip entry: %+v", files[0])
+	}
+}
+
+func TestList_Devices(t *testing.T) {
+	a := newTestArchive(t)
+	result, err := a.List(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, "devices.tar.gz")})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	got := make(map[string]FileInfo)
+	for _, f := range result.Files {
+		got[f.Name] = f
+	}
+	for name, want := range map[string]struct {
+		typ    string
+		device *Device
+	}{
+		"dev/null":    {typeChar, &Device{Major: 1, Minor: 3}},
+		"dev/sda":     {typeDevice, &Device{Major: 8, Minor: 0}},
+		"dev/initctl": {typeFifo, nil},
+	} {
+		f := got[name]
+		if f.Type != want.typ || !reflect.DeepEqual(f.Device, want.device) {
+			t.Errorf("%s: expected type %s and device %v, got %s and %v", name, want.typ, want.device, f.Type, f.Device)
+		}
+	}
+}
+
+func TestExtract_Devices(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "devices.tar.gz")
+	for _, name := range []string{"dev/null", "dev/sda", "dev/initctl"} {
+		_, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{name}})
+		if err == nil || !strings.Contains(err.Error(), "without content") {
+			t.Errorf("%s: expected a descriptive error, got %v", name, err)
+		}
+	}
+
+	result, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"dev/", "dev/null"}, BestEffort: true})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(result.Files) != 1 || len(result.Skipped) != 1 || !strings.Contains(result.Skipped[0].Reason, "character device") {
+		t.Errorf("expected dev/null to be skipped as a character device, got %+v", result)
+	}
+}
+
+func TestWithReadBufferSize(t *testing.T) {
+	a, err := New("../testdata", WithReadBufferSize(64<<10), WithAutoUnwrap(true))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	ctx := context.Background()
+	for _, name := range []string{"test.tar.gz", "test.tar.xz", "test.tar.zst", "test.cpio", "test.gz", "nested.tar.gz.gz"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(a.Workdir, name)
+			listing, err := a.List(ctx, ListOptions{Path: path, TypeFilter: typeFile})
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			if listing.TotalFiles == 0 {
+				t.Fatal("expected entries")
+			}
+			file := listing.Files[len(listing.Files)-1]
+			result, err := a.Extract(ctx, ExtractOptions{Path: path, Files: []string{file.Name}})
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+			if len(result.Files) != 1 || int64(len(result.Files[0].Content)) != file.Size {
+				t.Errorf("expected %s of %d bytes, got %+v", file.Name, file.Size, result.Files)
+			}
+		})
+	}
+}
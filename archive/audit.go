@@ -0,0 +1,69 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// Reasons reported in the audit log when a request is refused.
+const (
+	reasonNotAbsolute   = "not absolute"
+	reasonUnresolvable  = "unresolvable"
+	reasonTraversal     = "path traversal"
+	reasonSymlinkEscape = "symlink escape"
+	reasonDenied        = "denied"
+	reasonIgnored       = "ignored"
+	reasonTooLarge      = "too large"
+	reasonInvalidSize   = "invalid size"
+	reasonMismatch      = "extension mismatch"
+	reasonSymlink       = "symlink"
+)
+
+// rejectedError is returned when a request is refused for security reasons.
+type rejectedError struct {
+	reason string
+	entry  string
+	err    error
+}
+
+func (e *rejectedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *rejectedError) Unwrap() error {
+	return e.err
+}
+
+type sessionKey struct{}
+
+// withSession returns a context carrying the MCP session ID for audit logging.
+func withSession(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, sessionKey{}, id)
+}
+
+func sessionID(ctx context.Context) string {
+	id, _ := ctx.Value(sessionKey{}).(string)
+	return id
+}
+
+// audit emits a warn-level log entry and counts the rejection if err is a
+// security rejection.
+func (a *Archive) audit(ctx context.Context, path string, err error) {
+	var rerr *rejectedError
+	if !errors.As(err, &rerr) {
+		return
+	}
+	a.metrics.rejected(rerr.reason)
+	slog.WarnContext(ctx, "request rejected",
+		"session", sessionID(ctx),
+		"path", path,
+		"reason", rerr.reason,
+		"entry", rerr.entry,
+		"error", err.Error(),
+	)
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// recordHandler is a slog.Handler that captures all records.
+type recordHandler struct {
+	records []slog.Record
+}
+
+func (h *recordHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordHandler) Handle(_ context.Context, r slog.Record) error {
+	h.records = append(h.records, r)
+	return nil
+}
+
+func (h *recordHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *recordHandler) WithGroup(string) slog.Handler { return h }
+
+func captureLogs(t *testing.T) *recordHandler {
+	h := &recordHandler{}
+	old := slog.Default()
+	slog.SetDefault(slog.New(h))
+	t.Cleanup(func() { slog.SetDefault(old) })
+	return h
+}
+
+func (h *recordHandler) warnings() []map[string]string {
+	var warnings []map[string]string
+	for _, r := range h.records {
+		if r.Level != slog.LevelWarn {
+			continue
+		}
+		attrs := map[string]string{"msg": r.Message}
+		r.Attrs(func(a slog.Attr) bool {
+			attrs[a.Key] = a.Value.String()
+			return true
+		})
+		warnings = append(warnings, attrs)
+	}
+	return warnings
+}
+
+func TestAudit_Traversal(t *testing.T) {
+	a := newTestArchive(t)
+	h := captureLogs(t)
+	path := filepath.Join(a.Workdir, "../archive/archive.go")
+	ctx := withSession(context.Background(), "session-1")
+	if _, err := a.List(ctx, ListOptions{Path: path}); err == nil {
+		t.Fatal("expected error for path traversal, but got nil")
+	}
+
+	warnings := h.warnings()
+	if len(warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %d", len(warnings))
+	}
+	w := warnings[0]
+	if w["session"] != "session-1" || w["path"] != path || w["reason"] != reasonTraversal {
+		t.Errorf("unexpected audit record: %v", w)
+	}
+}
+
+func TestAudit_SymlinkEscape(t *testing.T) {
+	a := newTestArchive(t)
+	h := captureLogs(t)
+	symlink := filepath.Join(a.Workdir, "escape.zip")
+	if err := os.Symlink("../archive/archive.go", symlink); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	defer os.Remove(symlink)
+
+	if _, err := a.Extract(context.Background(), ExtractOptions{Path: symlink, Files: []string{"foo"}}); err == nil {
+		t.Fatal("expected error for symlink escape, but got nil")
+	}
+
+	warnings := h.warnings()
+	if len(warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %d", len(warnings))
+	}
+	if warnings[0]["reason"] != reasonSymlinkEscape {
+		t.Errorf("unexpected audit record: %v", warnings[0])
+	}
+}
+
+func TestAudit_TooLarge(t *testing.T) {
+	a := newTestArchive(t)
+	a.maxSize = 20
+	h := captureLogs(t)
+	path := filepath.Join(a.Workdir, "test.tar.gz")
+	ctx := withSession(context.Background(), "session-2")
+	if _, err := a.Extract(ctx, ExtractOptions{Path: path, Files: []string{"foo/baar.txt"}}); err == nil {
+		t.Fatal("expected error for large file, but got nil")
+	}
+
+	warnings := h.warnings()
+	if len(warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %d", len(warnings))
+	}
+	w := warnings[0]
+	if w["session"] != "session-2" || w["path"] != path || w["reason"] != reasonTooLarge || w["entry"] != "foo/baar.txt" {
+		t.Errorf("unexpected audit record: %v", w)
+	}
+}
@@ -0,0 +1,366 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cavaliergopher/cpio"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// benchSizes are the sizes of the extracted file in the benchmark archives.
+var benchSizes = []int{1 << 10, 64 << 10, 1 << 20}
+
+// benchEntries is the number of small entries accompanying the extracted
+// file, so that listing has some work to do.
+const benchEntries = 100
+
+// benchContent returns size bytes of compressible text.
+func benchContent(size int) []byte {
+	line := "das Pferd isst Gurkensalat, die Kuh isst Gras\n"
+	return []byte(strings.Repeat(line, size/len(line)+1)[:size])
+}
+
+// writeBenchArchive creates an archive of format holding benchEntries
+// small files and data.bin of the given size, and returns its path.
+func writeBenchArchive(tb testing.TB, dir, format string, size int) string {
+	tb.Helper()
+	files := make(map[string][]byte)
+	for i := 0; i < benchEntries; i++ {
+		files[fmt.Sprintf("dir%d/file%03d.txt", i%8, i)] = benchContent(64)
+	}
+	files["data.bin"] = benchContent(size)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+
+	var buf bytes.Buffer
+	container, compression, _ := strings.Cut(format, ".")
+	if container == "gz" {
+		// A plain .gz file holding a tarball.
+		container, compression = "tar", "gz"
+	}
+	switch container {
+	case "zip":
+		zw := zip.NewWriter(&buf)
+		for _, name := range names {
+			w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate})
+			if err != nil {
+				tb.Fatalf("failed to add zip entry: %v", err)
+			}
+			w.Write(files[name])
+		}
+		if err := zw.Close(); err != nil {
+			tb.Fatalf("failed to write zip: %v", err)
+		}
+	case "tar":
+		tw := tar.NewWriter(&buf)
+		for _, name := range names {
+			if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(files[name]))}); err != nil {
+				tb.Fatalf("failed to write tar header: %v", err)
+			}
+			tw.Write(files[name])
+		}
+		if err := tw.Close(); err != nil {
+			tb.Fatalf("failed to write tar: %v", err)
+		}
+	case "cpio":
+		cw := cpio.NewWriter(&buf)
+		for _, name := range names {
+			if err := cw.WriteHeader(&cpio.Header{Name: name, Mode: 0o644, Size: int64(len(files[name]))}); err != nil {
+				tb.Fatalf("failed to write cpio header: %v", err)
+			}
+			cw.Write(files[name])
+		}
+		if err := cw.Close(); err != nil {
+			tb.Fatalf("failed to write cpio: %v", err)
+		}
+	default:
+		tb.Fatalf("unknown benchmark format %s", format)
+	}
+
+	data := buf.Bytes()
+	if compression != "" {
+		data = compressBench(tb, compression, data)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("bench-%d.%s", size, format))
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		tb.Fatalf("failed to write archive: %v", err)
+	}
+	return path
+}
+
+// compressBench compresses data with the compression of a format suffix.
+func compressBench(tb testing.TB, compression string, data []byte) []byte {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	var err error
+	switch compression {
+	case "gz":
+		w = gzip.NewWriter(&buf)
+	case "xz":
+		w, err = xz.NewWriter(&buf)
+	case "zst":
+		w, err = zstd.NewWriter(&buf)
+	case "sz":
+		w = snappy.NewBufferedWriter(&buf)
+	case "bz2":
+		// The standard library has no bzip2 compressor.
+		if _, err := exec.LookPath("bzip2"); err != nil {
+			tb.Skip("bzip2 not installed")
+		}
+		cmd := exec.Command("bzip2", "-c")
+		cmd.Stdin = bytes.NewReader(data)
+		out, err := cmd.Output()
+		if err != nil {
+			tb.Fatalf("bzip2 failed: %v", err)
+		}
+		return out
+	default:
+		tb.Fatalf("unknown compression %s", compression)
+	}
+	if err != nil {
+		tb.Fatalf("failed to create %s writer: %v", compression, err)
+	}
+	w.Write(data)
+	if err := w.Close(); err != nil {
+		tb.Fatalf("failed to compress with %s: %v", compression, err)
+	}
+	return buf.Bytes()
+}
+
+// newBenchArchive returns an Archive in a temporary directory whose maximum
+// file size allows extracting the largest benchmark file.
+func newBenchArchive(tb testing.TB) *Archive {
+	a, err := New(tb.TempDir())
+	if err != nil {
+		tb.Fatalf("failed to create archive: %v", err)
+	}
+	a.maxSize = int64(benchSizes[len(benchSizes)-1])
+	return a
+}
+
+func BenchmarkList(b *testing.B) {
+	a := newBenchArchive(b)
+	for _, f := range formats {
+		b.Run(f.name, func(b *testing.B) {
+			path := writeBenchArchive(b, a.Workdir, f.name, benchSizes[0])
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := a.List(context.Background(), ListOptions{Path: path}); err != nil {
+					b.Fatalf("List failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkExtract(b *testing.B) {
+	a := newBenchArchive(b)
+	for _, f := range formats {
+		for _, size := range benchSizes {
+			b.Run(fmt.Sprintf("%s/%d", f.name, size), func(b *testing.B) {
+				path := writeBenchArchive(b, a.Workdir, f.name, size)
+				b.SetBytes(int64(size))
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if _, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"data.bin"}, Raw: true}); err != nil {
+						b.Fatalf("Extract failed: %v", err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// extractStreaming copies the content of data.bin to w instead of
+// buffering it, for comparison with readEntry.
+func extractStreaming(a *Archive, path string, w io.Writer) error {
+	return a.walk(context.Background(), path, func(e *entry) error {
+		if e.info.Name != "data.bin" {
+			return nil
+		}
+		rc, err := e.open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		_, err = io.Copy(w, rc)
+		return err
+	})
+}
+
+// extractBuffered reads data.bin with readEntry like Extract does.
+func extractBuffered(a *Archive, path string) error {
+	return a.walk(context.Background(), path, func(e *entry) error {
+		if e.info.Name != "data.bin" {
+			return nil
+		}
+		_, err := a.readEntry(e)
+		return err
+	})
+}
+
+func BenchmarkExtract_BufferedVsStreaming(b *testing.B) {
+	a := newBenchArchive(b)
+	for _, format := range []string{"tar.gz", "zip"} {
+		for _, size := range benchSizes {
+			path := writeBenchArchive(b, a.Workdir, format, size)
+			b.Run(fmt.Sprintf("%s/%d/buffered", format, size), func(b *testing.B) {
+				b.SetBytes(int64(size))
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if err := extractBuffered(a, path); err != nil {
+						b.Fatalf("extract failed: %v", err)
+					}
+				}
+			})
+			b.Run(fmt.Sprintf("%s/%d/streaming", format, size), func(b *testing.B) {
+				b.SetBytes(int64(size))
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if err := extractStreaming(a, path, io.Discard); err != nil {
+						b.Fatalf("extract failed: %v", err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// TestExtract_BufferedAllocations guards against regressions in the memory
+// used to extract a file: buffering allocates at most about twice the file
+// on top of what streaming needs. It runs benchmarks and is therefore only
+// run along with them, as in go test -bench . -run BufferedAllocations.
+func TestExtract_BufferedAllocations(t *testing.T) {
+	if testing.Short() || flag.Lookup("test.bench").Value.String() == "" {
+		t.Skip("runs benchmarks, pass -bench to run it")
+	}
+	a := newBenchArchive(t)
+	size := benchSizes[len(benchSizes)-1]
+	path := writeBenchArchive(t, a.Workdir, "tar.gz", size)
+
+	buffered := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := extractBuffered(a, path); err != nil {
+				b.Fatalf("extract failed: %v", err)
+			}
+		}
+	})
+	streaming := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := extractStreaming(a, path, io.Discard); err != nil {
+				b.Fatalf("extract failed: %v", err)
+			}
+		}
+	})
+
+	extra := buffered.AllocedBytesPerOp() - streaming.AllocedBytesPerOp()
+	if extra > 2*int64(size) {
+		t.Errorf("buffered extraction allocates %d bytes more than streaming, expected at most %d", extra, 2*size)
+	}
+}
+
+// writeMmapBenchZip writes a zip of n stored entries of size bytes each and
+// returns its path and the names of every tenth entry.
+func writeMmapBenchZip(tb testing.TB, dir string, n, size int) (string, []string) {
+	path := filepath.Join(dir, "mmap.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		tb.Fatalf("failed to create zip: %v", err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	content := benchContent(size)
+	var names []string
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("dir%d/file%05d.txt", i%16, i)
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+		if err != nil {
+			tb.Fatalf("failed to add zip entry: %v", err)
+		}
+		w.Write(content)
+		if i%10 == 0 {
+			names = append(names, name)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		tb.Fatalf("failed to write zip: %v", err)
+	}
+	return path, names
+}
+
+func BenchmarkZipExtract_Mmap(b *testing.B) {
+	dir := b.TempDir()
+	path, names := writeMmapBenchZip(b, dir, 20000, 1<<10)
+	for _, enabled := range []bool{false, true} {
+		b.Run(fmt.Sprintf("mmap=%t", enabled), func(b *testing.B) {
+			a, err := New(dir, WithMmap(enabled))
+			if err != nil {
+				b.Fatalf("failed to create archive: %v", err)
+			}
+			a.maxResponseBytes = 0
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: names, Raw: true}); err != nil {
+					b.Fatalf("Extract failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkList_ReadBufferSize(b *testing.B) {
+	dir := b.TempDir()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	content := benchContent(4 << 10)
+	for i := 0; i < 20000; i++ {
+		if err := tw.WriteHeader(&tar.Header{Name: fmt.Sprintf("dir%d/file%05d.txt", i%16, i), Mode: 0o644, Size: int64(len(content))}); err != nil {
+			b.Fatalf("failed to write tar header: %v", err)
+		}
+		tw.Write(content)
+	}
+	if err := tw.Close(); err != nil {
+		b.Fatalf("failed to write tar: %v", err)
+	}
+	path := filepath.Join(dir, "large.tar.gz")
+	if err := os.WriteFile(path, compressBench(b, "gz", buf.Bytes()), 0o644); err != nil {
+		b.Fatalf("failed to write archive: %v", err)
+	}
+
+	for _, size := range []int{0, 64 << 10, 1 << 20} {
+		b.Run(fmt.Sprintf("buffer=%d", size), func(b *testing.B) {
+			a, err := New(dir, WithReadBufferSize(size))
+			if err != nil {
+				b.Fatalf("failed to create archive: %v", err)
+			}
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := a.List(context.Background(), ListOptions{Path: path}); err != nil {
+					b.Fatalf("List failed: %v", err)
+				}
+			}
+		})
+	}
+}
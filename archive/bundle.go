@@ -0,0 +1,54 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// defaultMaxBundleSize is the default maximum size of a zip bundle of
+// extracted files, before base64 encoding.
+const defaultMaxBundleSize = 10 << 20
+
+// bundleZip packs files into a zip archive, preserving their names, modes
+// and symlink targets. It fails once the archive grows beyond maxSize bytes.
+func bundleZip(files []File, maxSize int64) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, file := range files {
+		h := &zip.FileHeader{Name: file.Name, Method: zip.Deflate}
+		content := file.RawContent
+		switch file.Type {
+		case typeDir:
+			if !strings.HasSuffix(h.Name, "/") {
+				h.Name += "/"
+			}
+			h.Method = zip.Store
+		case typeSymlink:
+			content = []byte(file.LinkTarget)
+		}
+		h.SetMode(file.mode)
+		w, err := zw.CreateHeader(h)
+		if err != nil {
+			return nil, fmt.Errorf("failed to bundle %s: %w", file.Name, err)
+		}
+		if _, err := w.Write(content); err != nil {
+			return nil, fmt.Errorf("failed to bundle %s: %w", file.Name, err)
+		}
+		if int64(buf.Len()) > maxSize {
+			return nil, fmt.Errorf("zip bundle exceeds the maximum size of %d bytes", maxSize)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to bundle files: %w", err)
+	}
+	if int64(buf.Len()) > maxSize {
+		return nil, fmt.Errorf("zip bundle exceeds the maximum size of %d bytes", maxSize)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtract_BundleAsZip(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "symlink.tar.gz")
+
+	result, err := a.Extract(context.Background(), ExtractOptions{
+		Path:        path,
+		Files:       []string{"foo/", "foo/baar.txt", "foo/link"},
+		BundleAsZip: true,
+	})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(result.Files) != 3 {
+		t.Fatalf("expected metadata of 3 files, got %v", result.Files)
+	}
+	for _, file := range result.Files {
+		if file.Content != "" {
+			t.Errorf("expected no content for bundled file %s, got %q", file.Name, file.Content)
+		}
+	}
+
+	data, err := base64.StdEncoding.DecodeString(result.Bundle)
+	if err != nil {
+		t.Fatalf("failed to decode bundle: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("failed to open bundle: %v", err)
+	}
+	want := map[string]struct {
+		mode    fs.FileMode
+		content string
+	}{
+		"foo/":         {fs.ModeDir | 0o755, ""},
+		"foo/baar.txt": {0o644, "das Pferd isst Gurkensalat\n"},
+		"foo/link":     {fs.ModeSymlink | 0o777, "baar.txt"},
+	}
+	if len(zr.File) != len(want) {
+		t.Fatalf("expected %d bundled files, got %d", len(want), len(zr.File))
+	}
+	for _, f := range zr.File {
+		w, ok := want[f.Name]
+		if !ok {
+			t.Errorf("unexpected bundled file %s", f.Name)
+			continue
+		}
+		if f.Mode() != w.mode {
+			t.Errorf("%s: expected mode %v, got %v", f.Name, w.mode, f.Mode())
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", f.Name, err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", f.Name, err)
+		}
+		if string(content) != w.content {
+			t.Errorf("%s: expected content %q, got %q", f.Name, w.content, content)
+		}
+	}
+}
+
+func TestExtract_BundleAsZipErrors(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "test.tar.gz")
+
+	_, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/baar.txt"}, BundleAsZip: true, RangeStart: 1})
+	if err == nil || !strings.Contains(err.Error(), "cannot be bundled") {
+		t.Errorf("expected byte range error, got: %v", err)
+	}
+
+	a.maxBundleSize = 100
+	_, err = a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/baar.txt", "foo/bazz"}, BundleAsZip: true})
+	if err == nil || !strings.Contains(err.Error(), "exceeds the maximum size of 100 bytes") {
+		t.Errorf("expected bundle size error, got: %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// archiveCall is a tool call reading the archive at a client-supplied path,
+// started by startCall. Every tool reading a single archive goes through
+// startCall, so that confinement, auditing and the call limits are applied
+// alike.
+type archiveCall struct {
+	// ctx is the context of the call, bounded by the call timeout.
+	ctx context.Context
+	// path is the resolved path of the archive.
+	path string
+	// format is the format of the archive.
+	format format
+	// ignored reports whether an entry is hidden by the ignore file of the
+	// root of the archive.
+	ignored func(FileInfo) bool
+
+	cancel  context.CancelFunc
+	release func()
+}
+
+// done ends the call, releasing its slot of the concurrency limit.
+func (c *archiveCall) done() {
+	c.release()
+	c.cancel()
+}
+
+// startCall starts a tool call reading the archive at path. It confines
+// path with secureArchivePath, looks up the format of the resolved path
+// with lookup, unless it is nil, and waits for a slot of the concurrency
+// limit within the call timeout. Rejected paths are audited. The call must
+// be ended with done.
+func (a *Archive) startCall(ctx context.Context, path string, lookup func(resolved string) (format, error)) (*archiveCall, error) {
+	resolved, err := a.secureArchivePath(path)
+	if err != nil {
+		a.audit(ctx, path, err)
+		return nil, err
+	}
+	var f format
+	if lookup != nil {
+		if f, err = lookup(resolved); err != nil {
+			return nil, err
+		}
+	}
+
+	callCtx, cancel := a.callContext(ctx)
+	release, err := a.acquire(callCtx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &archiveCall{
+		ctx:     callCtx,
+		path:    resolved,
+		format:  f,
+		ignored: a.ignoredEntries(resolved),
+		cancel:  cancel,
+		release: release,
+	}, nil
+}
+
+// startWalk starts a tool call reading the archive at path like startCall,
+// refusing archives of unsupported or disabled formats.
+func (a *Archive) startWalk(ctx context.Context, path string) (*archiveCall, error) {
+	return a.startCall(ctx, path, func(resolved string) (format, error) {
+		f, ok := formatFor(resolved)
+		if !ok {
+			return format{}, fmt.Errorf("unsupported archive format for %s", path)
+		}
+		return f, a.checkFormat(f)
+	})
+}
+
+// callError audits err, the failure of a call reading the archive at path,
+// and replaces a deadline error caused by the call timeout with a
+// descriptive one. ctx is the context the call was started with.
+func (a *Archive) callError(ctx context.Context, path string, err error) error {
+	a.audit(ctx, path, err)
+	return a.timeoutError(ctx, err)
+}
+
+// walkArchive calls fn for every entry of the archive at path within a call
+// started by startWalk. Hidden entries are passed as well, and fn decides
+// with the ignored predicate of the call whether to skip or refuse them.
+func (a *Archive) walkArchive(ctx context.Context, path string, fn func(call *archiveCall, e *entry) error) error {
+	c, err := a.startWalk(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer c.done()
+	err = a.walk(c.ctx, c.path, func(e *entry) error {
+		return fn(c, e)
+	})
+	if err != nil {
+		return a.callError(ctx, path, err)
+	}
+	return nil
+}
+
+// listArchive returns the entries of the archive at path that are not
+// hidden by an ignore file, within a call started by startWalk.
+func (a *Archive) listArchive(ctx context.Context, path string) ([]FileInfo, error) {
+	c, err := a.startWalk(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer c.done()
+	files, err := listWalk(a.pathWalker(c.ctx, c.path), c.ignored)
+	if err != nil {
+		return nil, a.callError(ctx, path, err)
+	}
+	return files, nil
+}
+
+// callTool runs fn with opts for the MCP tool name on behalf of the session
+// of req. The call is logged and counted for the archive at path.
+func callTool[O, R any](ctx context.Context, a *Archive, req *mcp.CallToolRequest, name, path string, opts O, fn func(context.Context, O) (R, error)) (*mcp.CallToolResult, R, error) {
+	slog.Debug("mcp tool call: "+name, "session", req.Session.ID(), "params", opts)
+	a.metrics.toolCall(name, path)
+	result, err := fn(withSession(ctx, req.Session.ID()), opts)
+	if err != nil {
+		var zero R
+		return nil, zero, err
+	}
+	return nil, result, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWalkArchive(t *testing.T) {
+	a, err := New("../testdata", WithMaxConcurrent(1), WithTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := filepath.Join(a.Workdir, "test.tar.gz")
+	// A slot leaked by the first walk would make the second one wait until
+	// the call timeout.
+	for range 2 {
+		var names []string
+		err := a.walkArchive(context.Background(), path, func(call *archiveCall, e *entry) error {
+			if call.path != path || call.format.name != "tar.gz" {
+				t.Errorf("unexpected call %+v", call)
+			}
+			names = append(names, e.info.Name)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("walkArchive failed: %v", err)
+		}
+		if len(names) == 0 {
+			t.Error("no entries were walked")
+		}
+	}
+
+	h := captureLogs(t)
+	for path, want := range map[string]string{
+		"relative.tar.gz":                          "not an absolute path",
+		a.Workdir:                                  "path is a directory, not an archive",
+		filepath.Join(a.Workdir, "Makefile"):       "unsupported archive format",
+		filepath.Join(a.Workdir, "missing.tar.gz"): "failed to evaluate symlinks",
+	} {
+		err := a.walkArchive(context.Background(), path, func(*archiveCall, *entry) error {
+			t.Errorf("%s was walked", path)
+			return nil
+		})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", path, want, err)
+		}
+	}
+	if warnings := h.warnings(); len(warnings) != 2 {
+		t.Errorf("expected the 2 rejected paths to be audited, got %v", warnings)
+	}
+
+	errStop := errors.New("stop")
+	err = a.walkArchive(context.Background(), path, func(*archiveCall, *entry) error {
+		return errStop
+	})
+	if !errors.Is(err, errStop) {
+		t.Errorf("expected the error of fn, got %v", err)
+	}
+}
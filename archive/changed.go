@@ -0,0 +1,130 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultMaxHashTotal is the default cap on the total content size hashed
+// by Changed.
+const defaultMaxHashTotal = 1 << 30
+
+// ChangedOptions are the options for comparing the files of an archive
+// with a baseline manifest.
+type ChangedOptions struct {
+	Path         string            `json:"path" jsonschema:"the path to the archive"`
+	Baseline     map[string]string `json:"baseline" jsonschema:"the hex encoded SHA256 of the content of each file of the baseline, by file name"`
+	MaxTotalSize int64             `json:"max_total_size,omitempty" jsonschema:"the maximum total size in bytes of the content hashed. If not set, it will default to 1 GiB"`
+}
+
+// ListChangedArgs are the arguments for the list_changed tool.
+type ListChangedArgs ChangedOptions
+
+// ListChangedResult holds the result of the list_changed tool. The names
+// are sorted.
+type ListChangedResult struct {
+	// Changed are the files whose content differs from the baseline.
+	Changed []string `json:"changed"`
+	// Added are the files of the archive missing from the baseline.
+	Added []string `json:"added"`
+	// Removed are the files of the baseline missing from the archive.
+	Removed []string `json:"removed"`
+	// Unchanged is the number of files matching the baseline.
+	Unchanged int `json:"unchanged"`
+}
+
+// Changed hashes the content of the regular files of an archive and
+// compares it with a baseline of SHA256 hashes by name, as with the output
+// of sha256sum. Names are compared after normalization. Of files occurring
+// more than once, the last occurrence counts, matching tar extraction
+// semantics. The call fails once more than MaxTotalSize bytes would be
+// hashed.
+func (a *Archive) Changed(ctx context.Context, opts ChangedOptions) (ListChangedResult, error) {
+	baseline := make(map[string]string, len(opts.Baseline))
+	for name, sum := range opts.Baseline {
+		if b, err := hex.DecodeString(sum); err != nil || len(b) != sha256.Size {
+			return ListChangedResult{}, fmt.Errorf("invalid SHA256 %q for %s", sum, name)
+		}
+		baseline[normalizeName(name)] = strings.ToLower(sum)
+	}
+	if opts.MaxTotalSize < 0 {
+		return ListChangedResult{}, fmt.Errorf("invalid maximum total size %d", opts.MaxTotalSize)
+	}
+	maxTotal := opts.MaxTotalSize
+	if maxTotal == 0 {
+		maxTotal = defaultMaxHashTotal
+	}
+
+	sums := make(map[string]string)
+	var total int64
+	err := a.walkArchive(ctx, opts.Path, func(call *archiveCall, e *entry) error {
+		if e.info.Type != typeFile || call.ignored(e.info) {
+			return nil
+		}
+		if err := checkEntrySize(e); err != nil {
+			return err
+		}
+		return a.withinEntryTimeout(e, func() error {
+			rc, err := e.open()
+			if err != nil {
+				return err
+			}
+			defer rc.Close()
+			h := sha256.New()
+			n, err := io.Copy(h, io.LimitReader(&ctxReader{ctx: call.ctx, r: rc}, maxTotal-total+1))
+			if err != nil {
+				return fmt.Errorf("could not read file %s from archive: %w", e.info.Name, err)
+			}
+			total += n
+			if total > maxTotal {
+				return &rejectedError{
+					reason: reasonTooLarge,
+					entry:  e.info.Name,
+					err:    fmt.Errorf("files too large to hash: more than %d bytes in total", maxTotal),
+				}
+			}
+			sums[normalizeName(e.info.Name)] = hex.EncodeToString(h.Sum(nil))
+			return nil
+		})
+	})
+	if err != nil {
+		return ListChangedResult{}, err
+	}
+
+	result := ListChangedResult{Changed: []string{}, Added: []string{}, Removed: []string{}}
+	for name, sum := range sums {
+		switch base, ok := baseline[name]; {
+		case !ok:
+			result.Added = append(result.Added, name)
+		case base != sum:
+			result.Changed = append(result.Changed, name)
+		default:
+			result.Unchanged++
+		}
+	}
+	for name := range baseline {
+		if _, ok := sums[name]; !ok {
+			result.Removed = append(result.Removed, name)
+		}
+	}
+	slices.Sort(result.Changed)
+	slices.Sort(result.Added)
+	slices.Sort(result.Removed)
+	return result, nil
+}
+
+// ListChanged lists the files of an archive that differ from a baseline.
+func (a *Archive) ListChanged(ctx context.Context, req *mcp.CallToolRequest, args ListChangedArgs) (*mcp.CallToolResult, ListChangedResult, error) {
+	return callTool(ctx, a, req, "list_changed", args.Path, ChangedOptions(args), a.Changed)
+}
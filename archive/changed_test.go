@@ -0,0 +1,64 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestChanged(t *testing.T) {
+	a := newTestArchive(t)
+	for _, archiveType := range []string{"test.cpio", "test.tar.gz", "test.zip"} {
+		result, err := a.Changed(context.Background(), ChangedOptions{
+			Path: filepath.Join(a.Workdir, archiveType),
+			Baseline: map[string]string{
+				"./foo/baar.txt": strings.ToUpper(sha256Hex("das Pferd isst Gurkensalat\n")),
+				"foo/bazz":       sha256Hex("old bazz\n"),
+				"foo/removed":    sha256Hex(""),
+			},
+		})
+		if err != nil {
+			t.Fatalf("Changed %s failed: %v", archiveType, err)
+		}
+		if result.Unchanged != 1 || !slices.Equal(result.Changed, []string{"foo/bazz"}) || len(result.Added) != 0 || !slices.Equal(result.Removed, []string{"foo/removed"}) {
+			t.Errorf("%s: unexpected result %+v", archiveType, result)
+		}
+	}
+}
+
+func TestChanged_Added(t *testing.T) {
+	a := newTestArchive(t)
+	result, err := a.Changed(context.Background(), ChangedOptions{Path: filepath.Join(a.Workdir, "test.tar.gz")})
+	if err != nil {
+		t.Fatalf("Changed failed: %v", err)
+	}
+	if !slices.Equal(result.Added, []string{"foo/baar.txt", "foo/bazz"}) {
+		t.Errorf("expected all files to be added, got %+v", result)
+	}
+}
+
+func TestChanged_Limits(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "test.tar.gz")
+	_, err := a.Changed(context.Background(), ChangedOptions{Path: path, MaxTotalSize: 30})
+	if err == nil || !strings.Contains(err.Error(), "too large to hash") {
+		t.Errorf("expected an error for exceeding the total size, got %v", err)
+	}
+	_, err = a.Changed(context.Background(), ChangedOptions{Path: path, Baseline: map[string]string{"foo/bazz": "abc"}})
+	if err == nil || !strings.Contains(err.Error(), "invalid SHA256") {
+		t.Errorf("expected an error for an invalid hash, got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"path"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// CompressionInfo holds the hints a compressed stream carries about how it
+// was produced. Fields the format does not expose are left empty.
+type CompressionInfo struct {
+	// Method is the outermost compression: gzip, zstd or bzip2.
+	Method string `json:"method"`
+	// Level is the compression level hint of the gzip extra flags, "best"
+	// or "fastest", or the block size level of bzip2, "1" to "9".
+	Level string `json:"level,omitempty"`
+	// OS is the operating system that wrote a gzip stream.
+	OS string `json:"os,omitempty"`
+	// Name is the original file name stored in a gzip header.
+	Name string `json:"name,omitempty"`
+	// WindowSize is the window size in bytes of the first zstd frame.
+	WindowSize uint64 `json:"window_size,omitempty"`
+	// ContentSize is the uncompressed size declared by the first zstd
+	// frame.
+	ContentSize uint64 `json:"content_size,omitempty"`
+	// Checksum tells whether zstd frames end with a content checksum.
+	Checksum bool `json:"checksum,omitempty"`
+	// DictionaryID is the ID of the dictionary the zstd frame requires.
+	DictionaryID uint32 `json:"dictionary_id,omitempty"`
+}
+
+// gzipOS names the operating systems of the gzip header, see RFC 1952.
+var gzipOS = map[byte]string{
+	0:  "FAT",
+	1:  "Amiga",
+	2:  "VMS",
+	3:  "Unix",
+	4:  "VM/CMS",
+	5:  "Atari TOS",
+	6:  "HPFS",
+	7:  "Macintosh",
+	8:  "Z-System",
+	9:  "CP/M",
+	10: "TOPS-20",
+	11: "NTFS",
+	12: "QDOS",
+	13: "Acorn RISCOS",
+}
+
+// compressionInfo reads the compression hints from the header of the
+// archive of format f in r. It returns nil for uncompressed formats, for
+// compressions without hints and for headers that cannot be parsed.
+func compressionInfo(f format, r io.ReaderAt, size int64) *CompressionInfo {
+	header := make([]byte, zstd.HeaderMaxSize)
+	n, _ := r.ReadAt(header, 0)
+	header = header[:n]
+
+	switch path.Ext("." + f.name) {
+	case ".gz":
+		if len(header) < 10 {
+			return nil
+		}
+		zr, err := gzip.NewReader(io.NewSectionReader(r, 0, size))
+		if err != nil {
+			return nil
+		}
+		defer zr.Close()
+		info := &CompressionInfo{Method: "gzip", OS: gzipOS[zr.OS], Name: zr.Name}
+		switch header[8] {
+		case 2:
+			info.Level = "best"
+		case 4:
+			info.Level = "fastest"
+		}
+		return info
+	case ".zst":
+		var h zstd.Header
+		if err := h.Decode(header); err != nil || h.Skippable {
+			return nil
+		}
+		info := &CompressionInfo{Method: "zstd", WindowSize: h.WindowSize, Checksum: h.HasCheckSum, DictionaryID: h.DictionaryID}
+		if h.HasFCS {
+			info.ContentSize = h.FrameContentSize
+		}
+		return info
+	case ".bz2":
+		if len(header) < 4 || !bytes.HasPrefix(header, []byte("BZh")) || header[3] < '1' || header[3] > '9' {
+			return nil
+		}
+		return &CompressionInfo{Method: "bzip2", Level: string(header[3])}
+	}
+	return nil
+}
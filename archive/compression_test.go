@@ -0,0 +1,88 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInfo_Compression(t *testing.T) {
+	a := newTestArchive(t)
+	for name, method := range map[string]string{
+		"test.tar.gz":  "gzip",
+		"test.cpio.gz": "gzip",
+		"test.tar.zst": "zstd",
+		"test.tar.bz2": "bzip2",
+		"test.tar.xz":  "",
+		"test.cpio":    "",
+		"test.zip":     "",
+	} {
+		result, err := a.Info(context.Background(), InfoOptions{Path: filepath.Join(a.Workdir, name)})
+		if err != nil {
+			t.Fatalf("Info %s failed: %v", name, err)
+		}
+		if method == "" {
+			if result.Compression != nil {
+				t.Errorf("%s: expected no compression info, got %+v", name, result.Compression)
+			}
+			continue
+		}
+		if result.Compression == nil || result.Compression.Method != method {
+			t.Errorf("%s: expected %s compression info, got %+v", name, method, result.Compression)
+		}
+	}
+
+	result, err := a.Info(context.Background(), InfoOptions{Path: filepath.Join(a.Workdir, "test.tar.bz2")})
+	if err != nil {
+		t.Fatalf("Info failed: %v", err)
+	}
+	if result.Compression.Level != "9" {
+		t.Errorf("expected bzip2 level 9, got %q", result.Compression.Level)
+	}
+	result, err = a.Info(context.Background(), InfoOptions{Path: filepath.Join(a.Workdir, "test.tar.zst")})
+	if err != nil {
+		t.Fatalf("Info failed: %v", err)
+	}
+	if result.Compression.WindowSize == 0 && result.Compression.ContentSize == 0 {
+		t.Errorf("expected a zstd window or content size, got %+v", result.Compression)
+	}
+}
+
+func TestInfo_GzipHeader(t *testing.T) {
+	dir := t.TempDir()
+	a, err := New(dir)
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	for level, want := range map[int]string{gzip.BestCompression: "best", gzip.BestSpeed: "fastest", gzip.DefaultCompression: ""} {
+		var buf bytes.Buffer
+		zw, err := gzip.NewWriterLevel(&buf, level)
+		if err != nil {
+			t.Fatalf("failed to create gzip writer: %v", err)
+		}
+		zw.Name = "baar.txt"
+		zw.OS = 3
+		zw.Write([]byte("das Pferd isst Gurkensalat\n"))
+		zw.Close()
+		path := filepath.Join(dir, "single.gz")
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			t.Fatalf("failed to write gzip: %v", err)
+		}
+
+		result, err := a.Info(context.Background(), InfoOptions{Path: path})
+		if err != nil {
+			t.Fatalf("Info failed: %v", err)
+		}
+		c := result.Compression
+		if c == nil || c.Level != want || c.OS != "Unix" || c.Name != "baar.txt" {
+			t.Errorf("level %d: expected level %q, OS Unix and name baar.txt, got %+v", level, want, c)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ConcatOptions are the options for concatenating files of an archive.
+type ConcatOptions struct {
+	Path  string   `json:"path" jsonschema:"the path to the archive"`
+	Files []string `json:"files" jsonschema:"the files to concatenate, in order, e.g. part.001 and part.002 of a file split inside the archive"`
+}
+
+// ExtractConcatArgs are the arguments for the extract_concat tool.
+type ExtractConcatArgs ConcatOptions
+
+// ExtractConcatResult holds the result of the extract_concat tool.
+type ExtractConcatResult struct {
+	// Content is the concatenated content of the files.
+	Content string `json:"content"`
+	Size    int64  `json:"size"`
+}
+
+// Concat returns the content of the requested regular files of an archive
+// concatenated in the requested order. A file may be requested more than
+// once. Of files occurring more than once in the archive, the last
+// occurrence is used. Each file is limited by the maximum file size and the
+// result by the maximum response size.
+func (a *Archive) Concat(ctx context.Context, opts ConcatOptions) (ExtractConcatResult, error) {
+	if len(opts.Files) == 0 {
+		return ExtractConcatResult{}, errors.New("no files to concatenate")
+	}
+	requested := make([]string, len(opts.Files))
+	for i, name := range opts.Files {
+		requested[i] = normalizeName(name)
+	}
+	slices.Sort(requested)
+	extracted, err := a.Extract(ctx, ExtractOptions{Path: opts.Path, Files: slices.Compact(requested), Raw: true})
+	if err != nil {
+		return ExtractConcatResult{}, err
+	}
+
+	files := make(map[string]File, len(extracted.Files))
+	for _, file := range extracted.Files {
+		files[normalizeName(file.Name)] = file
+	}
+	var buf bytes.Buffer
+	for _, name := range opts.Files {
+		file, ok := files[normalizeName(name)]
+		if !ok {
+			return ExtractConcatResult{}, fmt.Errorf("file %s not found in archive", name)
+		}
+		if file.Type != typeFile {
+			return ExtractConcatResult{}, fmt.Errorf("cannot concatenate %s, which is a %s", file.Name, file.Type)
+		}
+		if a.maxResponseBytes > 0 && int64(buf.Len()+len(file.RawContent)) > a.maxResponseBytes {
+			return ExtractConcatResult{}, fmt.Errorf("concatenated content exceeds the maximum response size of %d bytes at %s", a.maxResponseBytes, file.Name)
+		}
+		buf.Write(file.RawContent)
+	}
+	return ExtractConcatResult{Content: buf.String(), Size: int64(buf.Len())}, nil
+}
+
+// ExtractConcat returns the concatenated content of files of an archive.
+func (a *Archive) ExtractConcat(ctx context.Context, req *mcp.CallToolRequest, args ExtractConcatArgs) (*mcp.CallToolResult, ExtractConcatResult, error) {
+	return callTool(ctx, a, req, "extract_concat", args.Path, ConcatOptions(args), a.Concat)
+}
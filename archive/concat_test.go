@@ -0,0 +1,59 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConcat(t *testing.T) {
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := writeSyntheticTar(t, a.Workdir, map[string]string{
+		"parts/data.bin.001": "first half,",
+		"parts/data.bin.002": " second half",
+	})
+	ctx := context.Background()
+
+	// The requested order wins over the archive order.
+	for _, tc := range []struct {
+		files []string
+		want  string
+	}{
+		{[]string{"parts/data.bin.001", "parts/data.bin.002"}, "first half, second half"},
+		{[]string{"parts/data.bin.002", "./parts/data.bin.001"}, " second halffirst half,"},
+		{[]string{"parts/data.bin.001", "parts/data.bin.001"}, "first half,first half,"},
+	} {
+		result, err := a.Concat(ctx, ConcatOptions{Path: path, Files: tc.files})
+		if err != nil {
+			t.Fatalf("Concat failed: %v", err)
+		}
+		if result.Content != tc.want || result.Size != int64(len(tc.want)) {
+			t.Errorf("%v: expected %q, got %q of %d bytes", tc.files, tc.want, result.Content, result.Size)
+		}
+	}
+
+	for _, files := range [][]string{nil, {"parts/data.bin.003"}, {"parts/"}} {
+		if _, err := a.Concat(ctx, ConcatOptions{Path: path, Files: files}); err == nil {
+			t.Errorf("expected concatenating %v to fail", files)
+		}
+	}
+}
+
+func TestConcat_MaxResponseBytes(t *testing.T) {
+	a, err := New("../testdata", WithMaxResponseBytes(30))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	_, err = a.Concat(context.Background(), ConcatOptions{Path: filepath.Join(a.Workdir, "test.tar.gz"), Files: []string{"foo/bazz", "foo/baar.txt"}})
+	if err == nil || !strings.Contains(err.Error(), "maximum response size of 30 bytes at foo/baar.txt") {
+		t.Errorf("expected the response size to be exceeded, got %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/ianaindex"
+)
+
+// lookupCharset returns the encoding for an IANA charset name or alias such
+// as "ISO-8859-1", "latin1" or "Shift_JIS".
+func lookupCharset(name string) (encoding.Encoding, error) {
+	enc, err := ianaindex.IANA.Encoding(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown charset %q: %w", name, err)
+	}
+	if enc == nil {
+		return nil, fmt.Errorf("unsupported charset %q", name)
+	}
+	return enc, nil
+}
+
+// toUTF8 transcodes data from enc to UTF-8.
+func toUTF8(enc encoding.Encoding, data []byte) ([]byte, error) {
+	utf8, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transcode content: %w", err)
+	}
+	return utf8, nil
+}
+
+// contentType detects the media type of an extracted file from its name,
+// falling back to sniffing its content.
+func contentType(name string, data []byte) string {
+	if t := mime.TypeByExtension(path.Ext(name)); t != "" {
+		return t
+	}
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed) {
+		return "application/json"
+	}
+	return http.DetectContentType(data)
+}
+
+// dataURI encodes data as a base64 data URI of its detected media type.
+func dataURI(name string, data []byte) []byte {
+	// Data URIs allow no whitespace between the media type parameters.
+	prefix := "data:" + strings.ReplaceAll(contentType(name, data), "; ", ";") + ";base64,"
+	uri := make([]byte, len(prefix)+base64.StdEncoding.EncodedLen(len(data)))
+	copy(uri, prefix)
+	base64.StdEncoding.Encode(uri[len(prefix):], data)
+	return uri
+}
+
+// encodingGzipBase64 is the File.Encoding of content returned with
+// ExtractOptions.CompressResponse.
+const encodingGzipBase64 = "gzip+base64"
+
+// gzipBase64 compresses data with gzip and encodes the result as base64.
+func gzipBase64(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := base64.NewEncoder(base64.StdEncoding, &buf)
+	zw := gzip.NewWriter(enc)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// prettyPrint re-indents JSON and XML content. Other or invalid content is
+// returned as is.
+func prettyPrint(name string, data []byte) []byte {
+	mediaType, _, _ := mime.ParseMediaType(contentType(name, data))
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, data, "", "  "); err != nil {
+			return data
+		}
+		buf.WriteByte('\n')
+		return buf.Bytes()
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		indented, err := indentXML(data)
+		if err != nil {
+			return data
+		}
+		return indented
+	}
+	return data
+}
+
+// indentXML re-indents an XML document. Documents using namespace prefixes
+// are refused, as encoding/xml cannot reproduce them faithfully.
+func indentXML(data []byte) ([]byte, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var buf bytes.Buffer
+	e := xml.NewEncoder(&buf)
+	e.Indent("", "  ")
+	for {
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space != "" {
+				return nil, fmt.Errorf("namespace prefix %s is not supported", t.Name.Space)
+			}
+			for _, attr := range t.Attr {
+				if attr.Name.Space != "" || attr.Name.Local == "xmlns" {
+					return nil, fmt.Errorf("namespace attribute %s is not supported", attr.Name.Local)
+				}
+			}
+		case xml.CharData:
+			// The encoder adds its own indentation.
+			if len(bytes.TrimSpace(t)) == 0 {
+				continue
+			}
+		}
+		if err := e.EncodeToken(tok); err != nil {
+			return nil, err
+		}
+		// The encoder does not break lines after the prolog.
+		switch tok.(type) {
+		case xml.ProcInst, xml.Directive:
+			if err := e.Flush(); err != nil {
+				return nil, err
+			}
+			buf.WriteByte('\n')
+		}
+	}
+	if err := e.Close(); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtract_Charset(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "latin1.tar.gz")
+
+	for _, charset := range []string{"ISO-8859-1", "latin1"} {
+		result, err := a.Extract(context.Background(), ExtractOptions{
+			Path:    path,
+			Files:   []string{"foo/latin1.txt"},
+			Charset: charset,
+		})
+		if err != nil {
+			t.Fatalf("Extract failed for %s: %v", charset, err)
+		}
+		if len(result.Files) != 1 {
+			t.Fatalf("expected 1 file, got %d", len(result.Files))
+		}
+		if result.Files[0].Content != "Grüße aus Köln\n" {
+			t.Errorf("unexpected content for %s: %q", charset, result.Files[0].Content)
+		}
+	}
+}
+
+func TestExtract_CharsetUnset(t *testing.T) {
+	a := newTestArchive(t)
+	result, err := a.Extract(context.Background(), ExtractOptions{
+		Path:  filepath.Join(a.Workdir, "latin1.tar.gz"),
+		Files: []string{"foo/latin1.txt"},
+	})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if result.Files[0].Content != "Gr\xfc\xdfe aus K\xf6ln\n" {
+		t.Errorf("expected unchanged content, got: %q", result.Files[0].Content)
+	}
+}
+
+func TestExtract_InvalidCharset(t *testing.T) {
+	a := newTestArchive(t)
+	_, err := a.Extract(context.Background(), ExtractOptions{
+		Path:    filepath.Join(a.Workdir, "latin1.tar.gz"),
+		Files:   []string{"foo/latin1.txt"},
+		Charset: "klingon-1",
+	})
+	if err == nil {
+		t.Fatal("expected error for invalid charset, but got nil")
+	}
+	if !strings.Contains(err.Error(), `unknown charset "klingon-1"`) {
+		t.Fatalf("expected unknown charset error, got: %v", err)
+	}
+}
+
+func TestPrettyPrint(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"data.json", `{"a":[1,2]}`, "{\n  \"a\": [\n    1,\n    2\n  ]\n}\n"},
+		{"data", `{"a":1}`, "{\n  \"a\": 1\n}\n"},
+		{"data.json", `{"a":`, `{"a":`},
+		{"data.xml", "<a>\n<b x=\"1\">text</b></a>", "<a>\n  <b x=\"1\">text</b>\n</a>\n"},
+		{"data.xml", `<?xml version="1.0"?><a><b/></a>`, "<?xml version=\"1.0\"?>\n<a>\n  <b></b>\n</a>\n"},
+		{"data.xml", `<a><b></a>`, `<a><b></a>`},
+		{"data.xml", "<!DOCTYPE a><a>x</a>", "<!DOCTYPE a>\n<a>x</a>\n"},
+		{"data.xml", `<x:a xmlns:x="urn:x"/>`, `<x:a xmlns:x="urn:x"/>`},
+		{"data.txt", `{"a":1}`, `{"a":1}`},
+	} {
+		if got := string(prettyPrint(tc.name, []byte(tc.in))); got != tc.want {
+			t.Errorf("prettyPrint(%s, %q) = %q, want %q", tc.name, tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestExtract_Pretty(t *testing.T) {
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := writeSyntheticTar(t, a.Workdir, map[string]string{
+		"foo/data.json": `{"a":1}`,
+		"foo/bad.json":  `{"a":`,
+	})
+	for pretty, want := range map[bool]string{false: `{"a":1}`, true: "{\n  \"a\": 1\n}\n"} {
+		result, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/data.json", "foo/bad.json"}, Pretty: pretty})
+		if err != nil {
+			t.Fatalf("Extract failed: %v", err)
+		}
+		if len(result.Files) != 2 {
+			t.Fatalf("expected 2 files, got %d", len(result.Files))
+		}
+		for _, f := range result.Files {
+			switch f.Name {
+			case "foo/data.json":
+				if f.Content != want {
+					t.Errorf("with pretty %v: unexpected content %q", pretty, f.Content)
+				}
+			case "foo/bad.json":
+				if f.Content != `{"a":` {
+					t.Errorf("expected invalid JSON unchanged, got %q", f.Content)
+				}
+			}
+		}
+	}
+}
+
+func TestExtract_DataURI(t *testing.T) {
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	var pixel bytes.Buffer
+	if err := png.Encode(&pixel, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatalf("failed to encode PNG: %v", err)
+	}
+	path := writeTestZip(t, a.Workdir, "images.zip", "img/pixel.png", pixel.String(), "img/readme.txt", "pixel")
+
+	result, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"img/pixel.png", "img/readme.txt"}, DataURI: true})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	want := map[string]string{
+		"img/pixel.png":  "data:image/png;base64," + base64.StdEncoding.EncodeToString(pixel.Bytes()),
+		"img/readme.txt": "data:text/plain;charset=utf-8;base64,cGl4ZWw=",
+	}
+	if len(result.Files) != len(want) {
+		t.Fatalf("expected %d files, got %d", len(want), len(result.Files))
+	}
+	for _, f := range result.Files {
+		if f.Content != want[f.Name] {
+			t.Errorf("%s: expected %q, got %q", f.Name, want[f.Name], f.Content)
+		}
+	}
+
+	result, err = a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"img/readme.txt"}})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if result.Files[0].Content != "pixel" {
+		t.Errorf("expected plain content without data_uri, got %q", result.Files[0].Content)
+	}
+
+	if _, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"img/pixel.png"}, DataURI: true, BundleAsZip: true}); err == nil {
+		t.Error("expected data_uri with bundle_as_zip to fail")
+	}
+}
+
+func TestExtract_CompressResponse(t *testing.T) {
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	content := strings.Repeat("all work and no play makes jack a dull boy\n", 1000)
+	path := writeTestZip(t, a.Workdir, "logs.zip", "logs/", "", "logs/boring.log", content)
+
+	result, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"logs/", "logs/boring.log"}, CompressResponse: true})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(result.Files) != 2 {
+		t.Fatalf("expected 2 files, got %+v", result.Files)
+	}
+	dir, file := result.Files[0], result.Files[1]
+	if dir.Encoding != "" || dir.Content != "" {
+		t.Errorf("expected directory to be returned as is, got %+v", dir)
+	}
+	if file.Encoding != "gzip+base64" {
+		t.Fatalf("expected encoding gzip+base64, got %q", file.Encoding)
+	}
+	if len(file.Content) >= len(content)/10 {
+		t.Errorf("expected compressed content to be much smaller than %d bytes, got %d", len(content), len(file.Content))
+	}
+	compressed, err := base64.StdEncoding.DecodeString(file.Content)
+	if err != nil {
+		t.Fatalf("failed to decode content: %v", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatalf("failed to open gzip stream: %v", err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("failed to decompress content: %v", err)
+	}
+	if string(data) != content {
+		t.Error("decompressed content does not match the entry")
+	}
+
+	if _, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"logs/boring.log"}, CompressResponse: true, BundleAsZip: true}); err == nil {
+		t.Error("expected compress_response with bundle_as_zip to fail")
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cavaliergopher/cpio"
+)
+
+// cpioTrailer is the name of the entry marking the end of a cpio archive.
+const cpioTrailer = "TRAILER!!!"
+
+// errMissingTrailer is returned by cpioReader.Next at the end of the input
+// of a cpio archive lacking the trailer entry, which is likely truncated.
+var errMissingTrailer = errors.New("unexpected EOF: cpio archive is missing its " + cpioTrailer + " entry and may be truncated")
+
+// maxCpioLinkTarget caps the length of a symlink target, which is read
+// into memory, like cavaliergopher/cpio does.
+const maxCpioLinkTarget = 4096
+
+// cpioReader reads the entries of a cpio archive like cpio.Reader.
+type cpioReader interface {
+	Next() (*cpio.Header, error)
+	Read(p []byte) (int, error)
+}
+
+// newCpioReader detects the variant of the cpio archive read from r by its
+// magic. The SVR4 "newc" and "crc" variants are read by cavaliergopher/cpio,
+// the portable ASCII "odc" and the old binary variants by oldCpioReader.
+func newCpioReader(r io.Reader) (cpioReader, error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(6)
+	switch {
+	case len(magic) == 0:
+		return cpio.NewReader(br), nil
+	case string(magic) == "070701" || string(magic) == "070702":
+		in := &eofReader{r: br}
+		return &svr4Reader{Reader: cpio.NewReader(in), in: in}, nil
+	case string(magic) == "070707":
+		return &oldCpioReader{r: br, ascii: true}, nil
+	case len(magic) >= 2 && binary.LittleEndian.Uint16(magic) == 0o70707:
+		return &oldCpioReader{r: br, order: binary.LittleEndian}, nil
+	case len(magic) >= 2 && binary.BigEndian.Uint16(magic) == 0o70707:
+		return &oldCpioReader{r: br, order: binary.BigEndian}, nil
+	}
+	return nil, fmt.Errorf("unsupported cpio format: %q", magic)
+}
+
+// eofReader records whether reading from r reached its end.
+type eofReader struct {
+	r   io.Reader
+	eof bool
+}
+
+func (r *eofReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err == io.EOF {
+		r.eof = true
+	}
+	return n, err
+}
+
+// svr4Reader reads SVR4 cpio archives with cavaliergopher/cpio, which
+// returns io.EOF both for the trailer entry and at the end of the input.
+// As it reads no further than the trailer, reaching the end of the input
+// means that the trailer is missing.
+type svr4Reader struct {
+	*cpio.Reader
+	in *eofReader
+}
+
+func (r *svr4Reader) Next() (*cpio.Header, error) {
+	h, err := r.Reader.Next()
+	if err == io.EOF && r.in.eof {
+		return nil, errMissingTrailer
+	}
+	return h, err
+}
+
+// oldCpioReader reads the portable ASCII (odc) and old binary cpio
+// variants, which the cavaliergopher/cpio reader does not support.
+type oldCpioReader struct {
+	r     *bufio.Reader
+	ascii bool
+	// order is the byte order of the old binary variant.
+	order binary.ByteOrder
+	// remaining is the number of unread bytes of the current entry and
+	// padding the bytes following them up to the next header.
+	remaining int64
+	padding   int64
+}
+
+// Next advances to the next entry, skipping the rest of the current one.
+func (r *oldCpioReader) Next() (*cpio.Header, error) {
+	if _, err := io.CopyN(io.Discard, r.r, r.remaining+r.padding); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	r.remaining, r.padding = 0, 0
+	if _, err := r.r.Peek(1); err == io.EOF {
+		return nil, errMissingTrailer
+	}
+
+	var h *cpio.Header
+	var nameSize int64
+	var err error
+	if r.ascii {
+		h, nameSize, err = r.readODCHeader()
+	} else {
+		h, nameSize, err = r.readBinaryHeader()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if nameSize < 1 {
+		return nil, cpio.ErrHeader
+	}
+	name := make([]byte, nameSize)
+	if _, err := io.ReadFull(r.r, name); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	h.Name = strings.TrimRight(string(name), "\x00")
+	if !r.ascii && (binaryHeaderSize+nameSize)%2 != 0 {
+		// The old binary variant pads names and data to even sizes.
+		if _, err := r.r.Discard(1); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+	}
+	if h.Name == cpioTrailer {
+		return nil, io.EOF
+	}
+	r.remaining = h.Size
+	if !r.ascii {
+		r.padding = h.Size % 2
+	}
+	if h.Mode&cpio.ModeType == cpio.TypeSymlink {
+		// The target of a symlink is stored as its content.
+		if h.Size < 1 || h.Size > maxCpioLinkTarget {
+			return nil, cpio.ErrHeader
+		}
+		target := make([]byte, h.Size)
+		if _, err := io.ReadFull(r, target); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		h.Linkname = string(target)
+		h.Size = 0
+	}
+	return h, nil
+}
+
+// Read reads from the content of the current entry.
+func (r *oldCpioReader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n, err := r.r.Read(p)
+	r.remaining -= int64(n)
+	if err == io.EOF && r.remaining > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// odcHeaderSize is the size of a portable ASCII header: the magic, eight
+// fields of six octal digits, the 11 digit modification time, the six
+// digit name size and the 11 digit file size.
+const odcHeaderSize = 76
+
+func (r *oldCpioReader) readODCHeader() (*cpio.Header, int64, error) {
+	var buf [odcHeaderSize]byte
+	if _, err := io.ReadFull(r.r, buf[:]); err != nil {
+		return nil, 0, unexpectedEOF(err)
+	}
+	var fields [10]int64
+	widths := [10]int{6, 6, 6, 6, 6, 6, 6, 11, 6, 11}
+	pos := 6
+	for i, w := range widths {
+		v, err := strconv.ParseInt(string(buf[pos:pos+w]), 8, 64)
+		if err != nil {
+			return nil, 0, cpio.ErrHeader
+		}
+		fields[i] = v
+		pos += w
+	}
+	// dev, ino, mode, uid, gid, nlink, rdev, mtime, namesize, filesize
+	return &cpio.Header{
+		Inode:   fields[1],
+		Mode:    cpio.FileMode(fields[2]),
+		Uid:     int(fields[3]),
+		Guid:    int(fields[4]),
+		Links:   int(fields[5]),
+		ModTime: time.Unix(fields[7], 0),
+		Size:    fields[9],
+	}, fields[8], nil
+}
+
+// binaryHeaderSize is the size of an old binary header of 13 16-bit words.
+const binaryHeaderSize = 26
+
+func (r *oldCpioReader) readBinaryHeader() (*cpio.Header, int64, error) {
+	var buf [binaryHeaderSize]byte
+	if _, err := io.ReadFull(r.r, buf[:]); err != nil {
+		return nil, 0, unexpectedEOF(err)
+	}
+	var words [13]uint16
+	for i := range words {
+		words[i] = r.order.Uint16(buf[2*i:])
+	}
+	// magic, dev, ino, mode, uid, gid, nlink, rdev, mtime[2], namesize,
+	// filesize[2], with the most significant word of 32-bit values first
+	long := func(i int) int64 { return int64(words[i])<<16 | int64(words[i+1]) }
+	return &cpio.Header{
+		Inode:   int64(words[2]),
+		Mode:    cpio.FileMode(words[3]),
+		Uid:     int(words[4]),
+		Guid:    int(words[5]),
+		Links:   int(words[6]),
+		ModTime: time.Unix(long(8), 0),
+		Size:    long(11),
+	}, int64(words[10]), nil
+}
+
+// unexpectedEOF turns io.EOF inside a cpio archive into
+// io.ErrUnexpectedEOF.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
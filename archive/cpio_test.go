@@ -0,0 +1,155 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCpio_OldVariants(t *testing.T) {
+	a := newTestArchive(t)
+	for _, name := range []string{"test-odc.cpio", "test-bin.cpio"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(a.Workdir, name)
+			files, err := a.list(context.Background(), path)
+			if err != nil {
+				t.Fatalf("list failed: %v", err)
+			}
+			expected := []expectedFile{
+				{name: "foo", size: 0},
+				{name: "foo/baar.txt", size: 27},
+				{name: "foo/bazz", size: 5},
+			}
+			if len(files) != len(expected) {
+				t.Fatalf("expected %d files, got %+v", len(expected), files)
+			}
+			for _, exp := range expected {
+				if !containsFile(files, exp) {
+					t.Errorf("expected file '%v' not found in archive", exp)
+				}
+			}
+			if files[0].Type != typeDir || files[0].Permissions != "drwxr-xr-x" {
+				t.Errorf("unexpected type or permissions of foo: %+v", files[0])
+			}
+
+			// Skipping foo/baar.txt must not disturb reading foo/bazz.
+			extracted, err := a.extract(context.Background(), path, []string{"foo/bazz", "foo/baar.txt"})
+			if err != nil {
+				t.Fatalf("extract failed: %v", err)
+			}
+			if len(extracted) != 2 || string(extracted[0].RawContent) != "bazz\n" || string(extracted[1].RawContent) != "das Pferd isst Gurkensalat\n" {
+				t.Errorf("unexpected extracted files: %+v", extracted)
+			}
+		})
+	}
+}
+
+// odcEntry returns a portable ASCII cpio entry.
+func odcEntry(name string, mode int, content string) string {
+	return fmt.Sprintf("070707%06o%06o%06o%06o%06o%06o%06o%011o%06o%011o%s\x00%s",
+		0, 1, mode, 0, 0, 1, 0, 0, len(name)+1, len(content), name, content)
+}
+
+func TestCpio_OldVariantSymlink(t *testing.T) {
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	data := odcEntry("link", 0o120777, "target") + odcEntry("target", 0o100644, "content") + odcEntry("TRAILER!!!", 0, "")
+	path := filepath.Join(a.Workdir, "symlink.cpio")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+	files, err := a.list(context.Background(), path)
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(files) != 2 || files[0].Type != typeSymlink || files[0].Size != 0 || files[1].Name != "target" || files[1].Size != 7 {
+		t.Errorf("unexpected files: %+v", files)
+	}
+}
+
+func TestCpio_UnsupportedVariant(t *testing.T) {
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := filepath.Join(a.Workdir, "bad.cpio")
+	if err := os.WriteFile(path, []byte("not a cpio archive"), 0o644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+	_, err = a.list(context.Background(), path)
+	if err == nil || !strings.Contains(err.Error(), `unsupported cpio format: "not a "`) {
+		t.Errorf("expected unsupported cpio format error, got: %v", err)
+	}
+
+	// A truncated old variant archive fails cleanly.
+	data := odcEntry("file", 0o100644, "content")
+	if err := os.WriteFile(path, []byte(data[:len(data)-3]), 0o644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+	if _, err := a.extract(context.Background(), path, []string{"file"}); err == nil {
+		t.Error("expected error for truncated archive")
+	}
+}
+
+func TestCpio_Trailer(t *testing.T) {
+	a := newTestArchive(t)
+	for _, name := range []string{"test.cpio", "test.cpio.gz", "test.cpio.xz", "test-odc.cpio", "test-bin.cpio"} {
+		h := captureLogs(t)
+		files, err := a.list(context.Background(), filepath.Join(a.Workdir, name))
+		if err != nil {
+			t.Fatalf("list %s failed: %v", name, err)
+		}
+		for _, file := range files {
+			if file.Name == cpioTrailer {
+				t.Errorf("%s: trailer listed as an entry", name)
+			}
+		}
+		if len(files) != 3 {
+			t.Errorf("%s: expected 3 entries, got %d", name, len(files))
+		}
+		if w := h.warnings(); len(w) != 0 {
+			t.Errorf("%s: unexpected warnings: %v", name, w)
+		}
+	}
+}
+
+func TestCpio_MissingTrailer(t *testing.T) {
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	newc, err := os.ReadFile("../testdata/test.cpio")
+	if err != nil {
+		t.Fatalf("failed to read test.cpio: %v", err)
+	}
+	// The trailer is the last header, 110 bytes before its name.
+	newc = newc[:strings.LastIndex(string(newc), cpioTrailer)-110]
+	for name, data := range map[string]string{
+		"newc.cpio": string(newc),
+		"odc.cpio":  odcEntry("file", 0o100644, "content"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			h := captureLogs(t)
+			path := filepath.Join(a.Workdir, name)
+			if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+				t.Fatalf("failed to write archive: %v", err)
+			}
+			if _, err := a.list(context.Background(), path); err != nil {
+				t.Fatalf("list failed: %v", err)
+			}
+			w := h.warnings()
+			if len(w) != 1 || w[0]["msg"] != "truncated cpio archive" || !strings.Contains(w[0]["error"], "missing its TRAILER!!! entry") {
+				t.Errorf("expected a missing trailer warning, got %v", w)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"archive/zip"
+	"fmt"
+	"hash/crc32"
+)
+
+// verifyingCRC wraps read to check the content of regular zip entries
+// against their stored CRC32. archive/zip only verifies the checksum when
+// an entry is read to EOF, which readEntry does not do for entries of known
+// size.
+func verifyingCRC(read func(*entry) (File, error)) func(*entry) (File, error) {
+	return func(e *entry) (File, error) {
+		file, err := read(e)
+		if err != nil {
+			return File{}, err
+		}
+		f, ok := e.sys.(*zip.File)
+		if !ok || file.Type != typeFile {
+			return file, nil
+		}
+		if sum := crc32.ChecksumIEEE(file.RawContent); sum != f.CRC32 {
+			return File{}, fmt.Errorf("CRC32 mismatch for %s: stored %08x, computed %08x, the archive is corrupt", e.info.Name, f.CRC32, sum)
+		}
+		return file, nil
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestList_ZipCRC32(t *testing.T) {
+	a := newTestArchive(t)
+	result, err := a.List(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, "test.zip")})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	for _, f := range result.Files {
+		if f.Name == "foo/baar.txt" {
+			if want := crc32.ChecksumIEEE([]byte("das Pferd isst Gurkensalat\n")); f.CRC32 != want {
+				t.Errorf("CRC32 = %08x, want %08x", f.CRC32, want)
+			}
+			return
+		}
+	}
+	t.Errorf("foo/baar.txt not listed: %+v", result.Files)
+}
+
+func TestExtract_VerifyCRC(t *testing.T) {
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range []string{"good.txt", "bad.txt"} {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+		if err != nil {
+			t.Fatalf("failed to add zip entry: %v", err)
+		}
+		w.Write([]byte("content of " + name))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to write zip: %v", err)
+	}
+	// Corrupt the stored content of bad.txt.
+	data := bytes.Replace(buf.Bytes(), []byte("content of bad.txt"), []byte("CONTENT of bad.txt"), 1)
+	path := filepath.Join(a.Workdir, "corrupt.zip")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := a.Extract(ctx, ExtractOptions{Path: path, Files: []string{"good.txt"}, VerifyCRC: true}); err != nil {
+		t.Errorf("Extract of intact entry failed: %v", err)
+	}
+	_, err = a.Extract(ctx, ExtractOptions{Path: path, Files: []string{"bad.txt"}, VerifyCRC: true})
+	if err == nil || !strings.Contains(err.Error(), "CRC32 mismatch for bad.txt") {
+		t.Errorf("expected CRC32 mismatch, got: %v", err)
+	}
+	// Without verification, the corruption goes unnoticed.
+	result, err := a.Extract(ctx, ExtractOptions{Path: path, Files: []string{"bad.txt"}})
+	if err != nil || len(result.Files) != 1 || result.Files[0].Content != "CONTENT of bad.txt" {
+		t.Errorf("Extract without verification returned %+v, %v", result.Files, err)
+	}
+
+	_, err = a.Extract(ctx, ExtractOptions{Path: path, Files: []string{"good.txt"}, VerifyCRC: true, RangeStart: 1})
+	if err == nil || !strings.Contains(err.Error(), "cannot be verified") {
+		t.Errorf("expected byte range error, got: %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"bufio"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+	"github.com/ulikunitz/xz/lzma"
+)
+
+// decoderLimits bound the resources a decompressor may use.
+type decoderLimits struct {
+	// maxMemory is the largest window or dictionary, in bytes, a
+	// decompressor may allocate.
+	maxMemory uint64
+	// concurrency is the number of goroutines a decompressor may use.
+	concurrency int
+}
+
+// defaultDecoderLimits are conservative limits that still cover archives
+// produced with the highest standard compression levels.
+var defaultDecoderLimits = decoderLimits{
+	maxMemory:   128 << 20,
+	concurrency: 1,
+}
+
+// decompressor unwraps the compression layer of a stream.
+type decompressor func(r io.Reader, limits decoderLimits) (io.ReadCloser, error)
+
+func gunzip(r io.Reader, _ decoderLimits) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+func bunzip2(r io.Reader, _ decoderLimits) (io.ReadCloser, error) {
+	return io.NopCloser(bzip2.NewReader(r)), nil
+}
+
+// xzHeaderSize covers the stream header and the largest possible block
+// header of an xz stream.
+const xzHeaderSize = 12 + 1024
+
+// unxz returns an xz reader. The xz package allocates whatever dictionary a
+// block header declares, so the dictionary of the first block is checked
+// against the memory limit before the reader is created.
+func unxz(r io.Reader, limits decoderLimits) (io.ReadCloser, error) {
+	br := bufio.NewReaderSize(r, xzHeaderSize)
+	header, _ := br.Peek(xzHeaderSize)
+	if dictCap, err := xzDictCap(header); err == nil && limits.maxMemory > 0 && uint64(dictCap) > limits.maxMemory {
+		return nil, fmt.Errorf("xz dictionary of %d bytes exceeds the decoder memory limit of %d bytes", dictCap, limits.maxMemory)
+	}
+	xzr, err := xz.NewReader(br)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(xzr), nil
+}
+
+// xzDictCap returns the LZMA2 dictionary capacity declared in the first
+// block header of an xz stream, or 0 if the stream has no blocks.
+func xzDictCap(header []byte) (int64, error) {
+	const streamHeaderSize = 12
+	if len(header) < streamHeaderSize+2 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	block := header[streamHeaderSize:]
+	if block[0] == 0 {
+		// An index indicator instead of a block header: no blocks.
+		return 0, nil
+	}
+	size := (int(block[0]) + 1) * 4
+	if len(block) < size {
+		return 0, io.ErrUnexpectedEOF
+	}
+	flags := block[1]
+	p := block[2:size]
+
+	next := func() (uint64, error) {
+		var v uint64
+		for i := 0; i < 9 && i < len(p); i++ {
+			v |= uint64(p[i]&0x7f) << (7 * i)
+			if p[i]&0x80 == 0 {
+				p = p[i+1:]
+				return v, nil
+			}
+		}
+		return 0, errors.New("invalid xz multibyte integer")
+	}
+	if flags&0x40 != 0 {
+		if _, err := next(); err != nil {
+			return 0, err
+		}
+	}
+	if flags&0x80 != 0 {
+		if _, err := next(); err != nil {
+			return 0, err
+		}
+	}
+
+	const lzma2FilterID = 0x21
+	for i := 0; i <= int(flags&0x03); i++ {
+		id, err := next()
+		if err != nil {
+			return 0, err
+		}
+		propsSize, err := next()
+		if err != nil {
+			return 0, err
+		}
+		if uint64(len(p)) < propsSize {
+			return 0, io.ErrUnexpectedEOF
+		}
+		props := p[:propsSize]
+		p = p[propsSize:]
+		if id == lzma2FilterID && len(props) == 1 {
+			return lzma.DecodeDictCap(props[0])
+		}
+	}
+	return 0, errors.New("no LZMA2 filter in xz block header")
+}
+
+func unzstd(r io.Reader, limits decoderLimits) (io.ReadCloser, error) {
+	opts := []zstd.DOption{zstd.WithDecoderConcurrency(limits.concurrency)}
+	if limits.maxMemory > 0 {
+		opts = append(opts,
+			zstd.WithDecoderMaxMemory(limits.maxMemory),
+			zstd.WithDecoderMaxWindow(limits.maxMemory),
+		)
+	}
+	d, err := zstd.NewReader(r, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return d.IOReadCloser(), nil
+}
+
+// unsnappy reads the Snappy framing format, whose blocks are at most 64 KiB,
+// so that no memory limit applies.
+func unsnappy(r io.Reader, _ decoderLimits) (io.ReadCloser, error) {
+	return io.NopCloser(snappy.NewReader(r)), nil
+}
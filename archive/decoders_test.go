@@ -0,0 +1,61 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestXzDictCap(t *testing.T) {
+	data, err := os.ReadFile("../testdata/test.tar.xz")
+	if err != nil {
+		t.Fatalf("failed to read archive: %v", err)
+	}
+	dictCap, err := xzDictCap(data)
+	if err != nil {
+		t.Fatalf("xzDictCap failed: %v", err)
+	}
+	if dictCap != 8<<20 {
+		t.Errorf("expected the default dictionary of 8 MiB, got %d", dictCap)
+	}
+}
+
+func TestDecoderLimits_Xz(t *testing.T) {
+	a := newTestArchive(t)
+	a.decoderLimits.maxMemory = 4 << 20
+	_, err := a.List(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, "test.tar.xz")})
+	if err == nil {
+		t.Fatal("expected error for exceeded decoder memory limit, but got nil")
+	}
+	if !strings.Contains(err.Error(), "exceeds the decoder memory limit") {
+		t.Fatalf("expected decoder memory limit error, got: %v", err)
+	}
+}
+
+func TestDecoderLimits_Zstd(t *testing.T) {
+	a := newTestArchive(t)
+	a.decoderLimits.maxMemory = 1 << 20
+	_, err := a.List(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, "test.tar.zst")})
+	if err == nil {
+		t.Fatal("expected error for exceeded decoder window limit, but got nil")
+	}
+}
+
+func TestDecoderLimits_Default(t *testing.T) {
+	a := newTestArchive(t)
+	for _, archiveType := range []string{"test.tar.xz", "test.tar.zst"} {
+		result, err := a.List(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, archiveType)})
+		if err != nil {
+			t.Fatalf("List failed for %s: %v", archiveType, err)
+		}
+		if result.TotalFiles != 3 {
+			t.Errorf("expected 3 files in %s, got %d", archiveType, result.TotalFiles)
+		}
+	}
+}
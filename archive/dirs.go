@@ -0,0 +1,111 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultTopDirs is the default number of directories returned by
+// LargestDirs.
+const defaultTopDirs = 10
+
+// DirsOptions are the options for listing the directories of an archive
+// holding the most data.
+type DirsOptions struct {
+	Path string `json:"path" jsonschema:"the path to the archive"`
+	// Depth is the number of leading path segments directories are
+	// aggregated by. A file counts towards each of its parent directories
+	// up to that depth, like du --max-depth.
+	Depth int `json:"depth,omitempty" jsonschema:"how many levels of directories to aggregate sizes for. If not set, it will default to 1, the top-level directories"`
+	TopN  int `json:"top_n,omitempty" jsonschema:"the number of largest directories to return. If not set, it will default to 10"`
+}
+
+// LargestDirsArgs are the arguments for the largest_dirs tool.
+type LargestDirsArgs DirsOptions
+
+// DirSize is the aggregate size of the files below a directory.
+type DirSize struct {
+	Name string `json:"name"`
+	// Size is the total uncompressed size of the files below the
+	// directory.
+	Size int64 `json:"size"`
+	// Files is the number of files below the directory.
+	Files int `json:"files"`
+}
+
+// LargestDirsResult holds the result of the largest_dirs tool.
+type LargestDirsResult struct {
+	// Dirs are the largest directories by descending size.
+	Dirs []DirSize `json:"dirs"`
+	// TotalSize is the total uncompressed size of all files.
+	TotalSize int64 `json:"total_size"`
+}
+
+// aggregateDirs sums the sizes of the non-directory entries of files by
+// their parent directories up to depth levels and returns the n largest
+// directories. Directories of equal size are ordered by name.
+func aggregateDirs(files []FileInfo, depth, n int) LargestDirsResult {
+	result := LargestDirsResult{Dirs: []DirSize{}}
+	dirs := make(map[string]*DirSize)
+	for _, file := range files {
+		if file.Type == typeDir {
+			continue
+		}
+		result.TotalSize += file.Size
+		parts := strings.Split(strings.Trim(normalizeName(file.Name), "/"), "/")
+		for i := 1; i < len(parts) && i <= depth; i++ {
+			name := path.Join(parts[:i]...) + "/"
+			d, ok := dirs[name]
+			if !ok {
+				d = &DirSize{Name: name}
+				dirs[name] = d
+			}
+			d.Size += file.Size
+			d.Files++
+		}
+	}
+	for _, d := range dirs {
+		result.Dirs = append(result.Dirs, *d)
+	}
+	slices.SortFunc(result.Dirs, func(a, b DirSize) int {
+		return cmp.Or(cmp.Compare(b.Size, a.Size), strings.Compare(a.Name, b.Name))
+	})
+	result.Dirs = result.Dirs[:min(n, len(result.Dirs))]
+	return result
+}
+
+// LargestDirs aggregates the sizes of the files of an archive by their
+// parent directories and returns the largest ones. Of files occurring more
+// than once, only the last occurrence counts. Only the headers are read.
+func (a *Archive) LargestDirs(ctx context.Context, opts DirsOptions) (LargestDirsResult, error) {
+	if opts.Depth < 0 {
+		return LargestDirsResult{}, fmt.Errorf("invalid depth %d: must not be negative", opts.Depth)
+	}
+	if opts.TopN < 0 {
+		return LargestDirsResult{}, fmt.Errorf("invalid top_n %d: must not be negative", opts.TopN)
+	}
+	depth := cmp.Or(opts.Depth, 1)
+	topN := cmp.Or(opts.TopN, defaultTopDirs)
+
+	files, err := a.listArchive(ctx, opts.Path)
+	if err != nil {
+		return LargestDirsResult{}, err
+	}
+	return aggregateDirs(keepLast(files), depth, topN), nil
+}
+
+// ListLargestDirs lists the directories of an archive holding the most
+// data.
+func (a *Archive) ListLargestDirs(ctx context.Context, req *mcp.CallToolRequest, args LargestDirsArgs) (*mcp.CallToolResult, LargestDirsResult, error) {
+	return callTool(ctx, a, req, "largest_dirs", args.Path, DirsOptions(args), a.LargestDirs)
+}
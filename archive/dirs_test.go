@@ -0,0 +1,59 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLargestDirs(t *testing.T) {
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := writeSyntheticTar(t, a.Workdir, map[string]string{
+		"usr/share/doc/README":   strings.Repeat("d", 100),
+		"usr/lib/libbig.so":      strings.Repeat("x", 5000),
+		"usr/lib/modules/a.ko":   strings.Repeat("m", 2000),
+		"etc/config":             strings.Repeat("c", 50),
+		"var/log/app.log":        strings.Repeat("l", 300),
+		"toplevel.txt":           strings.Repeat("t", 10),
+		"usr/share/doc/COPYING":  strings.Repeat("g", 200),
+		"usr/share/locale/de.mo": strings.Repeat("s", 400),
+	})
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		depth, topN int
+		want        []DirSize
+	}{
+		{0, 0, []DirSize{{"usr/", 7700, 5}, {"var/", 300, 1}, {"etc/", 50, 1}}},
+		{2, 3, []DirSize{{"usr/", 7700, 5}, {"usr/lib/", 7000, 2}, {"usr/share/", 700, 3}}},
+		{3, 0, []DirSize{
+			{"usr/", 7700, 5}, {"usr/lib/", 7000, 2}, {"usr/lib/modules/", 2000, 1}, {"usr/share/", 700, 3},
+			{"usr/share/locale/", 400, 1}, {"usr/share/doc/", 300, 2}, {"var/", 300, 1}, {"var/log/", 300, 1}, {"etc/", 50, 1},
+		}},
+	} {
+		result, err := a.LargestDirs(ctx, DirsOptions{Path: path, Depth: tc.depth, TopN: tc.topN})
+		if err != nil {
+			t.Fatalf("LargestDirs failed: %v", err)
+		}
+		if result.TotalSize != 8060 {
+			t.Errorf("expected a total size of 8060, got %d", result.TotalSize)
+		}
+		if !slices.Equal(result.Dirs, tc.want) {
+			t.Errorf("depth %d top %d: expected %v, got %v", tc.depth, tc.topN, tc.want, result.Dirs)
+		}
+	}
+
+	for _, opts := range []DirsOptions{{Path: path, Depth: -1}, {Path: path, TopN: -1}} {
+		if _, err := a.LargestDirs(ctx, opts); err == nil {
+			t.Errorf("expected options %+v to fail", opts)
+		}
+	}
+}
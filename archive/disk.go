@@ -0,0 +1,318 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ExtractToDiskOptions are the options for extracting the entries of an
+// archive to a directory.
+type ExtractToDiskOptions struct {
+	Path string `json:"path" jsonschema:"the path to the archive"`
+	// Dest is created if it does not exist, but its parent must.
+	Dest  string   `json:"dest" jsonschema:"the absolute path of the directory to extract to, which must lie in the working directory. It is created if it does not exist"`
+	Files []string `json:"files,omitempty" jsonschema:"the files and directories to extract. If not set, all entries are extracted"`
+	// PreserveOwnership applies the owner and group of the entries. It is
+	// a no-op unless the server runs as root.
+	PreserveOwnership bool `json:"preserve_ownership,omitempty" jsonschema:"apply the owner and group of the entries to the written files, which only takes effect if the server runs as root"`
+}
+
+// ExtractToDiskArgs are the arguments for the extract_to_disk tool.
+type ExtractToDiskArgs ExtractToDiskOptions
+
+// ExtractToDiskResult holds the result of the extract_to_disk tool.
+type ExtractToDiskResult struct {
+	// Dest is the resolved directory the entries were written to.
+	Dest string `json:"dest"`
+	// Written are the names of the entries written, in archive order.
+	Written []string `json:"written"`
+	// Skipped are the entries that are neither files nor directories,
+	// such as symlinks, which are not written.
+	Skipped []string `json:"skipped"`
+	// Missing are the requested files the archive does not contain.
+	Missing []string `json:"missing"`
+}
+
+// ExtractToDisk writes the files and directories of an archive below a
+// directory in the working directory, with the permission bits of the
+// entries. Files larger than the maximum file size are refused, as are
+// entries that would be written outside of the directory. It fails with
+// ErrReadOnly unless the Archive was created with WithWritable.
+func (a *Archive) ExtractToDisk(ctx context.Context, opts ExtractToDiskOptions) (ExtractToDiskResult, error) {
+	if err := a.checkWritable(); err != nil {
+		return ExtractToDiskResult{}, err
+	}
+	if opts.Dest == "" {
+		return ExtractToDiskResult{}, errors.New("dest is required: pass the absolute path of a directory")
+	}
+	c, err := a.startWalk(ctx, opts.Path)
+	if err != nil {
+		return ExtractToDiskResult{}, err
+	}
+	defer c.done()
+	dest, err := a.secureWritePath(opts.Dest)
+	if err != nil {
+		a.audit(ctx, opts.Dest, err)
+		return ExtractToDiskResult{}, err
+	}
+	if err := mkdirExisting(dest, 0o755); err != nil {
+		return ExtractToDiskResult{}, fmt.Errorf("could not create %s: %w", opts.Dest, err)
+	}
+
+	var wanted map[string]bool
+	if len(opts.Files) > 0 {
+		wanted = make(map[string]bool, len(opts.Files))
+		for _, name := range opts.Files {
+			wanted[verifyKey(name)] = false
+		}
+	}
+	result := ExtractToDiskResult{Dest: dest, Written: []string{}, Skipped: []string{}, Missing: []string{}}
+	var dirs []dirMode
+	err = a.walk(c.ctx, c.path, func(e *entry) error {
+		if c.ignored(e.info) {
+			return nil
+		}
+		if diskTarget(dest, e.info.Name) == dest {
+			// Entries such as "./" name dest itself, which exists.
+			return nil
+		}
+		if wanted != nil {
+			key := verifyKey(e.info.Name)
+			if _, ok := wanted[key]; !ok {
+				return nil
+			}
+			wanted[key] = true
+		}
+		if e.info.Type != typeFile && e.info.Type != typeDir {
+			result.Skipped = append(result.Skipped, e.info.Name)
+			return nil
+		}
+		if err := a.withinEntryTimeout(e, func() error { return a.writeEntry(dest, e, opts, &dirs) }); err != nil {
+			return err
+		}
+		result.Written = append(result.Written, e.info.Name)
+		return nil
+	})
+	if err != nil {
+		return ExtractToDiskResult{}, a.callError(ctx, opts.Path, err)
+	}
+	if err := applyDirModes(dirs); err != nil {
+		return ExtractToDiskResult{}, err
+	}
+	for _, name := range opts.Files {
+		if !wanted[verifyKey(name)] {
+			result.Missing = append(result.Missing, name)
+		}
+	}
+	return result, nil
+}
+
+// ExtractArchiveToDisk extracts the entries of an archive to a directory.
+func (a *Archive) ExtractArchiveToDisk(ctx context.Context, req *mcp.CallToolRequest, args ExtractToDiskArgs) (*mcp.CallToolResult, ExtractToDiskResult, error) {
+	return callTool(ctx, a, req, "extract_to_disk", args.Path, ExtractToDiskOptions(args), a.ExtractToDisk)
+}
+
+// writeEntry writes the entry e below dest, the resolved and confined
+// target directory of ExtractToDisk, and applies the permission bits of
+// the entry. The modes of directory entries are appended to dirs instead,
+// to be applied by applyDirModes once every entry is written, so that a
+// read-only directory can still receive its files. Setuid, setgid and
+// sticky bits are dropped. With opts.PreserveOwnership, the owner and group
+// of the entry are applied as well, which requires privilege and is skipped
+// otherwise. Entries naming dest itself, such as "./", are a no-op. Entries
+// other than files and directories and names that would leave dest,
+// lexically or through a symlink already below dest, are refused.
+func (a *Archive) writeEntry(dest string, e *entry, opts ExtractToDiskOptions, dirs *[]dirMode) error {
+	target := diskTarget(dest, e.info.Name)
+	if target == dest {
+		return nil
+	}
+	if !within(dest, target) {
+		return &rejectedError{
+			reason: reasonTraversal,
+			entry:  e.info.Name,
+			err:    fmt.Errorf("entry %s would be written outside of %s", e.info.Name, dest),
+		}
+	}
+	if err := checkContent(e.info); err != nil {
+		return err
+	}
+	if err := checkEntrySize(e); err != nil {
+		return err
+	}
+	if e.info.Size > a.maxSize {
+		return tooLargeToWrite(e, a.maxSize)
+	}
+	if e.info.Type != typeDir && e.info.Type != typeFile {
+		return fmt.Errorf("cannot write %s to disk, which is a %s", e.info.Name, e.info.Type)
+	}
+	if err := a.mkdirBelow(dest, filepath.Dir(target), e.info.Name); err != nil {
+		return err
+	}
+	target, err := a.secureDiskPath(dest, target, e.info.Name)
+	if err != nil {
+		return err
+	}
+
+	if e.info.Type == typeDir {
+		// Accessible to the owner only until its mode is applied.
+		if err := mkdirExisting(target, 0o700); err != nil {
+			return fmt.Errorf("could not create directory %s: %w", e.info.Name, err)
+		}
+		*dirs = append(*dirs, dirMode{path: target, name: e.info.Name, mode: e.mode.Perm()})
+	} else {
+		if err := writeFile(target, e, a.maxSize); err != nil {
+			return err
+		}
+		if err := os.Chmod(target, e.mode.Perm()); err != nil {
+			return fmt.Errorf("could not set the mode of %s: %w", e.info.Name, err)
+		}
+	}
+	// Only root may give files away, so ownership is a no-op otherwise.
+	if opts.PreserveOwnership && os.Geteuid() == 0 && e.info.UID != nil && e.info.GID != nil {
+		if err := os.Lchown(target, *e.info.UID, *e.info.GID); err != nil {
+			return fmt.Errorf("could not set the owner of %s: %w", e.info.Name, err)
+		}
+	}
+	return nil
+}
+
+// diskTarget returns the path below dest the entry named name is written to.
+// It is dest itself for names such as "./" and "/".
+func diskTarget(dest, name string) string {
+	return filepath.Join(dest, filepath.FromSlash(normalizeName(name)))
+}
+
+// dirMode is the mode of a directory written by writeEntry.
+type dirMode struct {
+	path string
+	name string
+	mode fs.FileMode
+}
+
+// applyDirModes applies the modes of the directories in dirs, deepest
+// first, as tar does, so that no directory is made read-only before its
+// subdirectories are.
+func applyDirModes(dirs []dirMode) error {
+	slices.SortStableFunc(dirs, func(x, y dirMode) int {
+		return strings.Count(y.path, string(filepath.Separator)) - strings.Count(x.path, string(filepath.Separator))
+	})
+	for _, d := range dirs {
+		if err := os.Chmod(d.path, d.mode); err != nil {
+			return fmt.Errorf("could not set the mode of %s: %w", d.name, err)
+		}
+	}
+	return nil
+}
+
+// tooLargeToWrite is the error for a file entry e larger than maxSize.
+func tooLargeToWrite(e *entry, maxSize int64) error {
+	return &rejectedError{
+		reason: reasonTooLarge,
+		entry:  e.info.Name,
+		err:    fmt.Errorf("file %s is too large to write: more than %d bytes", e.info.Name, maxSize),
+	}
+}
+
+// secureDiskPath resolves target, a path below dest whose parent exists,
+// with secureWritePath and checks that it still lies below dest. The entry
+// named name is reported in errors.
+func (a *Archive) secureDiskPath(dest, target, name string) (string, error) {
+	evalPath, err := a.secureWritePath(target)
+	if err != nil {
+		return "", err
+	}
+	if evalPath == dest || !within(dest, evalPath) {
+		return "", &rejectedError{
+			reason: reasonSymlinkEscape,
+			entry:  name,
+			err:    fmt.Errorf("entry %s would be written outside of %s through a symlink", name, dest),
+		}
+	}
+	return evalPath, nil
+}
+
+// mkdirBelow creates dir, which lies below dest, and its missing parents.
+// Each parent is resolved and confined by secureDiskPath before the next
+// one is created in it, so that a symlink below dest cannot redirect the
+// creation elsewhere, as os.MkdirAll would.
+func (a *Archive) mkdirBelow(dest, dir, name string) error {
+	rel, err := filepath.Rel(dest, dir)
+	if err != nil || rel == "." {
+		return err
+	}
+	path := dest
+	for _, segment := range strings.Split(rel, string(filepath.Separator)) {
+		path, err = a.secureDiskPath(dest, filepath.Join(path, segment), name)
+		if err != nil {
+			return err
+		}
+		if err := mkdirExisting(path, 0o755); err != nil {
+			return fmt.Errorf("could not create parent directory of %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// mkdirExisting creates the directory path with perm unless a directory
+// exists there.
+func mkdirExisting(path string, perm fs.FileMode) error {
+	err := os.Mkdir(path, perm)
+	if !errors.Is(err, fs.ErrExist) {
+		return err
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s exists and is not a directory", path)
+	}
+	return nil
+}
+
+// writeFile writes the content of the file entry e to target, which is
+// created accessible to the owner only until writeEntry applies its mode.
+// An existing target is replaced only if it is a regular file. Content
+// beyond maxSize, which only entries of unknown size can have, is refused.
+func writeFile(target string, e *entry, maxSize int64) error {
+	if info, err := os.Lstat(target); err == nil && !info.Mode().IsRegular() {
+		return fmt.Errorf("could not create %s: %s exists and is not a regular file", e.info.Name, target)
+	}
+	rc, err := e.open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("could not create %s: %w", e.info.Name, err)
+	}
+	n, err := io.Copy(f, io.LimitReader(rc, maxSize+1))
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("could not write %s: %w", e.info.Name, err)
+	}
+	if n > maxSize {
+		f.Close()
+		os.Remove(target)
+		return tooLargeToWrite(e, maxSize)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("could not write %s: %w", e.info.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,305 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// modeEntries are the entries of the archives written by writeModesTar and
+// writeModesZip.
+var modeEntries = []struct {
+	name    string
+	mode    fs.FileMode
+	content string
+}{
+	{"bin/", fs.ModeDir | 0o750, ""},
+	{"bin/tool", fs.ModeSetuid | 0o755, "tool"},
+	{"etc/conf", 0o640, "conf"},
+	{"etc/link", fs.ModeSymlink | 0o777, "conf"},
+}
+
+// writeModesTar writes a tar.gz archive holding modeEntries and, if escape
+// is set, an entry leaving the extraction directory to the working
+// directory of a.
+func writeModesTar(t *testing.T, a *Archive, escape bool) string {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	for _, e := range modeEntries {
+		h := &tar.Header{Name: e.name, Mode: 0o644, Typeflag: tar.TypeReg, Size: int64(len(e.content))}
+		switch {
+		case e.mode.IsDir():
+			h.Typeflag = tar.TypeDir
+		case e.mode&fs.ModeSymlink != 0:
+			h = &tar.Header{Name: e.name, Linkname: e.content, Typeflag: tar.TypeSymlink}
+		}
+		h.Mode = int64(e.mode.Perm())
+		if e.mode&fs.ModeSetuid != 0 {
+			h.Mode |= 0o4000
+		}
+		if err := tw.WriteHeader(h); err != nil {
+			t.Fatalf("failed to write tar header: %v", err)
+		}
+		tw.Write([]byte(e.content)[:h.Size])
+	}
+	if escape {
+		tw.WriteHeader(&tar.Header{Name: "../escape", Mode: 0o644, Typeflag: tar.TypeReg, Size: 4})
+		tw.Write([]byte("xxxx"))
+	}
+	tw.Close()
+	zw.Close()
+	path := filepath.Join(a.Workdir, "modes.tar.gz")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+	return path
+}
+
+// writeModesZip writes a zip archive holding modeEntries to the working
+// directory of a.
+func writeModesZip(t *testing.T, a *Archive) string {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range modeEntries {
+		h := &zip.FileHeader{Name: e.name}
+		h.SetMode(e.mode)
+		w, err := zw.CreateHeader(h)
+		if err != nil {
+			t.Fatalf("failed to create zip entry: %v", err)
+		}
+		w.Write([]byte(e.content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to write zip: %v", err)
+	}
+	path := filepath.Join(a.Workdir, "modes.zip")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+	return path
+}
+
+func TestExtractToDisk_Modes(t *testing.T) {
+	a, err := New(t.TempDir(), WithWritable(true))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	for _, path := range []string{writeModesTar(t, a, false), writeModesZip(t, a)} {
+		dest := filepath.Join(a.Workdir, "out-"+filepath.Ext(path)[1:])
+		result, err := a.ExtractToDisk(context.Background(), ExtractToDiskOptions{
+			Path:  path,
+			Dest:  dest,
+			Files: []string{"bin", "bin/tool", "etc/conf", "etc/link", "missing.txt"},
+		})
+		if err != nil {
+			t.Fatalf("ExtractToDisk %s failed: %v", path, err)
+		}
+		if result.Dest != dest || !slices.Equal(result.Written, []string{"bin/", "bin/tool", "etc/conf"}) {
+			t.Errorf("%s: unexpected result %+v", path, result)
+		}
+		if !slices.Equal(result.Skipped, []string{"etc/link"}) || !slices.Equal(result.Missing, []string{"missing.txt"}) {
+			t.Errorf("%s: expected etc/link skipped and missing.txt missing, got %+v", path, result)
+		}
+
+		// Setuid bits are dropped.
+		for name, want := range map[string]fs.FileMode{
+			"bin":      fs.ModeDir | 0o750,
+			"bin/tool": 0o755,
+			"etc/conf": 0o640,
+		} {
+			info, err := os.Lstat(filepath.Join(dest, name))
+			if err != nil {
+				t.Errorf("%s: %s was not written: %v", path, name, err)
+				continue
+			}
+			if info.Mode() != want {
+				t.Errorf("%s: %s: expected mode %v, got %v", path, name, want, info.Mode())
+			}
+		}
+		data, err := os.ReadFile(filepath.Join(dest, "etc/conf"))
+		if err != nil || string(data) != "conf" {
+			t.Errorf("%s: unexpected content of etc/conf: %q, %v", path, data, err)
+		}
+		if _, err := os.Lstat(filepath.Join(dest, "etc/link")); err == nil {
+			t.Errorf("%s: the symlink was written", path)
+		}
+	}
+}
+
+func TestExtractToDisk_ReadOnly(t *testing.T) {
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	dest := filepath.Join(a.Workdir, "out")
+	_, err = a.ExtractToDisk(context.Background(), ExtractToDiskOptions{Path: writeModesZip(t, a), Dest: dest})
+	if !errors.Is(err, ErrReadOnly) || err.Error() != "server is read-only" {
+		t.Errorf("expected the read-only error, got %v", err)
+	}
+	if _, err := os.Stat(dest); err == nil {
+		t.Error("a read-only server created the destination")
+	}
+}
+
+func TestExtractToDisk_Escape(t *testing.T) {
+	a, err := New(t.TempDir(), WithWritable(true))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := writeModesTar(t, a, true)
+	dest := filepath.Join(a.Workdir, "out")
+	_, err = a.ExtractToDisk(context.Background(), ExtractToDiskOptions{Path: path, Dest: dest})
+	var rerr *rejectedError
+	if !errors.As(err, &rerr) || rerr.reason != reasonTraversal || !strings.Contains(err.Error(), "outside of") {
+		t.Errorf("expected the escaping entry to be refused, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(a.Workdir, "escape")); err == nil {
+		t.Error("escaping entry was written")
+	}
+
+	for _, dest := range []string{"out", filepath.Join(filepath.Dir(a.Workdir), "out"), ""} {
+		if _, err := a.ExtractToDisk(context.Background(), ExtractToDiskOptions{Path: path, Dest: dest}); err == nil {
+			t.Errorf("expected dest %q to be refused", dest)
+		}
+	}
+}
+
+func TestExtractToDisk_TooLarge(t *testing.T) {
+	a, err := New(t.TempDir(), WithWritable(true))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	a.maxSize = 3
+	_, err = a.ExtractToDisk(context.Background(), ExtractToDiskOptions{Path: writeModesZip(t, a), Dest: filepath.Join(a.Workdir, "out")})
+	var rerr *rejectedError
+	if !errors.As(err, &rerr) || rerr.reason != reasonTooLarge || rerr.entry != "bin/tool" {
+		t.Errorf("expected bin/tool to be refused as too large, got %v", err)
+	}
+}
+
+func TestExtractToDisk_DotRooted(t *testing.T) {
+	a, err := New(t.TempDir(), WithWritable(true))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	for _, h := range []*tar.Header{
+		{Name: "./", Mode: 0o755, Typeflag: tar.TypeDir},
+		{Name: "./ro/", Mode: 0o555, Typeflag: tar.TypeDir},
+		{Name: "./ro/sub/", Mode: 0o555, Typeflag: tar.TypeDir},
+		{Name: "./ro/sub/file", Mode: 0o444, Typeflag: tar.TypeReg, Size: 4},
+	} {
+		if err := tw.WriteHeader(h); err != nil {
+			t.Fatalf("failed to write tar header: %v", err)
+		}
+		tw.Write([]byte("file")[:h.Size])
+	}
+	tw.Close()
+	zw.Close()
+	path := filepath.Join(a.Workdir, "dot.tar.gz")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+	dest := filepath.Join(a.Workdir, "out")
+	t.Cleanup(func() {
+		os.Chmod(filepath.Join(dest, "ro", "sub"), 0o755)
+		os.Chmod(filepath.Join(dest, "ro"), 0o755)
+	})
+
+	result, err := a.ExtractToDisk(context.Background(), ExtractToDiskOptions{Path: path, Dest: dest})
+	if err != nil {
+		t.Fatalf("ExtractToDisk failed: %v", err)
+	}
+	if want := []string{"./ro/", "./ro/sub/", "./ro/sub/file"}; !slices.Equal(result.Written, want) {
+		t.Errorf("expected %v written, got %v", want, result.Written)
+	}
+	// The modes of read-only directories are applied after their content is
+	// written.
+	for name, want := range map[string]fs.FileMode{
+		"ro":          fs.ModeDir | 0o555,
+		"ro/sub":      fs.ModeDir | 0o555,
+		"ro/sub/file": 0o444,
+	} {
+		info, err := os.Lstat(filepath.Join(dest, name))
+		if err != nil {
+			t.Errorf("%s was not written: %v", name, err)
+			continue
+		}
+		if info.Mode() != want {
+			t.Errorf("%s: expected mode %v, got %v", name, want, info.Mode())
+		}
+	}
+}
+
+// writeAll writes every entry of the archive at path to dest and returns
+// the error for each entry.
+func writeAll(t *testing.T, a *Archive, path, dest string) map[string]error {
+	errs := make(map[string]error)
+	var dirs []dirMode
+	err := a.walk(context.Background(), path, func(e *entry) error {
+		errs[e.info.Name] = a.writeEntry(dest, e, ExtractToDiskOptions{}, &dirs)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walk failed: %v", err)
+	}
+	if err := applyDirModes(dirs); err != nil {
+		t.Fatalf("applyDirModes failed: %v", err)
+	}
+	return errs
+}
+
+func TestWriteEntry_SymlinkBelowDest(t *testing.T) {
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := writeModesTar(t, a, false)
+	dest := filepath.Join(a.Workdir, "out")
+	outside := filepath.Join(a.Workdir, "outside")
+	for _, dir := range []string{filepath.Join(dest, "bin"), outside} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+	}
+	// A directory and a file planted as symlinks pointing out of dest.
+	if err := os.Symlink(outside, filepath.Join(dest, "etc")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(outside, "tool"), nil, 0o644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "tool"), filepath.Join(dest, "bin", "tool")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	errs := writeAll(t, a, path, dest)
+	for _, name := range []string{"etc/conf", "bin/tool"} {
+		var rerr *rejectedError
+		if !errors.As(errs[name], &rerr) || rerr.reason != reasonSymlinkEscape {
+			t.Errorf("expected %s to be refused, got %v", name, errs[name])
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outside, "conf")); err == nil {
+		t.Error("etc/conf was written through the symlink")
+	}
+	if data, _ := os.ReadFile(filepath.Join(outside, "tool")); len(data) != 0 {
+		t.Error("bin/tool was written through the symlink")
+	}
+}
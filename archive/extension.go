@@ -0,0 +1,63 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// detectFormat returns the suffix of the outermost format, such as ".gz"
+// or ".zip", whose magic data starts with, or "" if none matches.
+func detectFormat(data []byte) string {
+	for suffix, magics := range formatMagic {
+		for _, magic := range magics {
+			if !bytes.HasPrefix(data, magic) {
+				continue
+			}
+			// The bzip2 magic is short enough to occur by chance.
+			if magic[0] == 'B' && !isBzip2Header(data) {
+				continue
+			}
+			return suffix
+		}
+	}
+	return ""
+}
+
+// claimedSuffix returns the suffix of name dispatched to format f, such as
+// ".tar.gz" or ".jar".
+func claimedSuffix(name string, f format) string {
+	name = strings.TrimSuffix(name, splitSuffix)
+	for _, suffix := range f.suffixes {
+		if strings.HasSuffix(name, suffix) {
+			return suffix
+		}
+	}
+	return "." + f.name
+}
+
+// checkExtension refuses the archive at p of format f, read from r, if
+// WithStrictExtension is set and its content does not start with the magic
+// of the format claimed by its extension. With WithTolerant, the magic may
+// follow a few junk bytes.
+func (a *Archive) checkExtension(p string, f format, r io.ReaderAt, size int64) error {
+	if !a.strictExtension {
+		return nil
+	}
+	r, size = a.skipPrelude(f, r, size)
+	head := make([]byte, min(size, 16))
+	n, _ := r.ReadAt(head, 0)
+	if detectFormat(head[:n]) == path.Ext("."+f.name) {
+		return nil
+	}
+	return &rejectedError{
+		reason: reasonMismatch,
+		err:    fmt.Errorf("content does not match %s extension", claimedSuffix(p, f)),
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	for name, want := range map[string]string{
+		"test.tar.gz":  ".gz",
+		"test.tar.bz2": ".bz2",
+		"test.tar.xz":  ".xz",
+		"test.tar.zst": ".zst",
+		"test.tar.sz":  ".sz",
+		"test.cpio":    ".cpio",
+		"test.zip":     ".zip",
+		"Makefile":     "",
+	} {
+		data, err := os.ReadFile(filepath.Join("../testdata", name))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+		if got := detectFormat(data); got != want {
+			t.Errorf("detectFormat(%s) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestStrictExtension(t *testing.T) {
+	a, err := New("../testdata", WithStrictExtension(true))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	ctx := context.Background()
+	for _, name := range []string{"test.zip", "test.jar", "test.tar.gz", "test.cpio", "test.gz", "split.zip.001"} {
+		if _, err := a.List(ctx, ListOptions{Path: filepath.Join(a.Workdir, name)}); err != nil {
+			t.Errorf("List %s failed: %v", name, err)
+		}
+	}
+
+	dir := t.TempDir()
+	a, err = New(dir, WithStrictExtension(true))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	data, err := os.ReadFile("../testdata/test.tar.gz")
+	if err != nil {
+		t.Fatalf("failed to read test.tar.gz: %v", err)
+	}
+	for name, content := range map[string][]byte{
+		"program.zip":  []byte("\x7fELF\x02\x01\x01\x00"),
+		"gzipped.zip":  data,
+		"plain.tar.xz": data,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		_, err := a.List(ctx, ListOptions{Path: path})
+		var rerr *rejectedError
+		if !errors.As(err, &rerr) || rerr.reason != reasonMismatch {
+			t.Errorf("%s: expected an extension mismatch, got %v", name, err)
+		}
+		if _, err := a.Info(ctx, InfoOptions{Path: path}); err == nil {
+			t.Errorf("%s: expected Info to fail", name)
+		}
+	}
+	_, err = a.List(ctx, ListOptions{Path: filepath.Join(dir, "program.zip")})
+	if err == nil || err.Error() != "content does not match .zip extension" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Without the option, the mismatch only surfaces while decoding.
+	lax, err := New(dir)
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	_, err = lax.List(ctx, ListOptions{Path: filepath.Join(dir, "program.zip")})
+	var rerr *rejectedError
+	if err == nil || errors.As(err, &rerr) {
+		t.Errorf("expected a decoding error, got %v", err)
+	}
+}
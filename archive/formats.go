@@ -0,0 +1,542 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"cmp"
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+)
+
+// container is the layout of the entries inside an archive, independent of
+// any compression applied on top of it.
+type container int
+
+const (
+	containerCpio container = iota
+	containerTar
+	containerZip
+	// containerSniffed is a tar archive or a single compressed file, told
+	// apart by the decompressed content.
+	containerSniffed
+)
+
+// format describes a supported archive format.
+type format struct {
+	// name is the canonical name of the format, e.g. "tar.gz".
+	name string
+	// suffixes are the file name suffixes dispatched to this format.
+	suffixes  []string
+	container container
+	// decompress is nil for uncompressed formats.
+	decompress decompressor
+	// base is the name of the format extended by extra compression
+	// layers, see nestedFormatFor.
+	base string
+}
+
+// formats are the supported archive formats. Longer suffixes must come
+// before shorter ones sharing the same ending.
+var formats = []format{
+	{name: "cpio", suffixes: []string{".cpio"}, container: containerCpio},
+	{name: "cpio.gz", suffixes: []string{".cpio.gz"}, container: containerCpio, decompress: gunzip},
+	{name: "cpio.xz", suffixes: []string{".cpio.xz"}, container: containerCpio, decompress: unxz},
+	{name: "tar.gz", suffixes: []string{".tar.gz"}, container: containerTar, decompress: gunzip},
+	{name: "tar.bz2", suffixes: []string{".tar.bz2"}, container: containerTar, decompress: bunzip2},
+	{name: "tar.xz", suffixes: []string{".tar.xz"}, container: containerTar, decompress: unxz},
+	{name: "tar.zst", suffixes: []string{".tar.zst"}, container: containerTar, decompress: unzstd},
+	{name: "tar.sz", suffixes: []string{".tar.sz"}, container: containerTar, decompress: unsnappy},
+	{name: "zip", suffixes: []string{".zip", ".jar", ".war", ".ear"}, container: containerZip},
+	{name: "gz", suffixes: []string{".gz"}, container: containerSniffed, decompress: gunzip},
+}
+
+// formatFor returns the format for the archive at path based on its suffix.
+// The first volume of a split archive has the format of the joined archive.
+// Compressed stream formats may carry extra compression suffixes, see
+// nestedFormatFor.
+func formatFor(path string) (format, bool) {
+	path = strings.TrimSuffix(path, splitSuffix)
+	f, ok := exactFormatFor(path)
+	if ok && f.container != containerSniffed {
+		return f, true
+	}
+	// A plain .gz suffix only applies if the name is not that of a
+	// compressed archive with an extra compression layer.
+	if nested, ok := nestedFormatFor(path); ok {
+		return nested, true
+	}
+	return f, ok
+}
+
+func exactFormatFor(path string) (format, bool) {
+	for _, f := range formats {
+		for _, suffix := range f.suffixes {
+			if strings.HasSuffix(path, suffix) {
+				return f, true
+			}
+		}
+	}
+	return format{}, false
+}
+
+// ErrFormatDisabled is returned for archives of a supported format that is
+// not enabled by WithFormats.
+var ErrFormatDisabled = errors.New("format disabled by server policy")
+
+// formatEnabled reports whether f is enabled by WithFormats. A format with
+// extra compression layers is enabled with its base format.
+func (a *Archive) formatEnabled(f format) bool {
+	return a.enabledFormats == nil || a.enabledFormats[cmp.Or(f.base, f.name)]
+}
+
+// checkFormat returns an error wrapping ErrFormatDisabled unless f is
+// enabled.
+func (a *Archive) checkFormat(f format) error {
+	if !a.formatEnabled(f) {
+		return fmt.Errorf("%w: %s", ErrFormatDisabled, f.name)
+	}
+	return nil
+}
+
+// entry is a single member of an archive passed to a walkFunc.
+type entry struct {
+	info FileInfo
+	// open returns a reader for the content of the entry. For stream
+	// formats the reader is only valid until the walkFunc returns.
+	open func() (io.ReadCloser, error)
+	// linkTarget is the target of a link entry of a tar or cpio archive.
+	linkTarget string
+	// mode is the file mode, formatted into info.Permissions by
+	// walkReader.
+	mode fs.FileMode
+	// sys is the underlying *cpio.Header, *tar.Header or *zip.File.
+	sys any
+	// sizeUnknown is set if info.Size cannot be trusted and the content
+	// has to be read until EOF instead.
+	sizeUnknown bool
+}
+
+// walkFunc is called for each entry while walking an archive.
+type walkFunc func(e *entry) error
+
+// errStopWalk can be returned by a walkFunc to end the walk early without
+// an error.
+var errStopWalk = errors.New("stop walk")
+
+// walk calls fn for every entry of the archive at path, which must already
+// have been confined by secureArchivePath.
+func (a *Archive) walk(ctx context.Context, path string, fn walkFunc) error {
+	f, ok := formatFor(path)
+	if !ok {
+		return fmt.Errorf("unsupported archive format for %s", path)
+	}
+	r, err := a.openArchive(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	if err := a.checkExtension(path, f, r, r.size); err != nil {
+		return err
+	}
+	return a.walkReader(ctx, f, r, r.size, memberName(path, f), fn)
+}
+
+// walkReader calls fn for every entry of the archive of format f read from
+// r, with decoded names and formatted permissions, enforcing the entry
+// limits. The time spent is recorded in the metrics.
+func (a *Archive) walkReader(ctx context.Context, f format, r io.ReaderAt, size int64, name string, fn walkFunc) error {
+	if err := a.checkFormat(f); err != nil {
+		return err
+	}
+	r, size = a.skipPrelude(f, r, size)
+	start := time.Now()
+	defer func() { a.metrics.decompressed(f.name, time.Since(start)) }()
+	limited := a.limitEntries(a.applySymlinkPolicy(fn))
+	err := a.walkFormat(ctx, f, r, size, name, func(e *entry) error {
+		e.info.Name = a.decodeName(e)
+		e.info.Permissions = formatPermissions(e.mode, a.permissionFormat)
+		if err := setLinkTarget(e); err != nil {
+			return err
+		}
+		return limited(e)
+	})
+	if errors.Is(err, errStopWalk) {
+		return nil
+	}
+	return err
+}
+
+// walker visits the entries of a single archive.
+type walker func(fn walkFunc) error
+
+// pathWalker returns a walker for the archive at path.
+func (a *Archive) pathWalker(ctx context.Context, path string) walker {
+	return func(fn walkFunc) error {
+		return a.walk(ctx, path, fn)
+	}
+}
+
+// walkFormat calls fn for every entry of the archive of format f read from
+// r. The name is that of the only entry of a single compressed file if the
+// compression format does not store one.
+func (a *Archive) walkFormat(ctx context.Context, f format, r io.ReaderAt, size int64, name string, fn walkFunc) error {
+	if f.container == containerZip {
+		return walkZip(ctx, r, size, a.maxEntries, a.parallelThreshold, fn)
+	}
+
+	var stream io.Reader = io.NewSectionReader(r, 0, size)
+	if a.readBufferSize > 0 {
+		stream = bufio.NewReaderSize(stream, a.readBufferSize)
+	}
+	var gz *gzip.Reader
+	if f.decompress != nil {
+		dr, err := f.decompress(stream, a.decoderLimits)
+		if err != nil {
+			return err
+		}
+		defer dr.Close()
+		gz, _ = dr.(*gzip.Reader)
+
+		stream = dr
+		if a.autoUnwrap {
+			inner, closers, err := unwrap(dr, maxAutoUnwrap, a.decoderLimits)
+			for _, c := range closers {
+				defer c.Close()
+			}
+			if err != nil {
+				return err
+			}
+			if len(closers) > 0 {
+				gz = nil
+			}
+			stream = inner
+		}
+		if a.readBufferSize > 0 {
+			stream = bufio.NewReaderSize(stream, a.readBufferSize)
+		}
+	}
+
+	stream = &ctxReader{ctx: ctx, r: stream}
+	switch f.container {
+	case containerCpio:
+		return walkCpio(ctx, stream, name, fn)
+	case containerTar, containerSniffed:
+		br := bufio.NewReaderSize(stream, tarBlockSize)
+		if block, _ := br.Peek(tarBlockSize); !isTarHeader(block) {
+			return fn(singleEntry(br, gz, r, size, name))
+		}
+		return walkTar(ctx, br, fn)
+	}
+	return fmt.Errorf("unsupported archive format %s", f.name)
+}
+
+// walkCpio calls fn for every entry of a cpio stream. The trailer entry
+// ending the archive is never visited. An archive lacking it is listed up
+// to the end of the input with a warning, as it is likely truncated; name
+// identifies the archive in the warning.
+func walkCpio(ctx context.Context, r io.Reader, name string, fn walkFunc) error {
+	reader, err := newCpioReader(r)
+	if err != nil {
+		return err
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if errors.Is(err, errMissingTrailer) {
+			slog.WarnContext(ctx, "truncated cpio archive", "session", sessionID(ctx), "archive", name, "error", err.Error())
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		e := &entry{
+			info: FileInfo{
+				Name: header.Name,
+				Size: header.Size,
+				Type: fileType(header.Name, header.FileInfo().Mode()),
+				UID:  &header.Uid,
+				GID:  &header.Guid,
+			},
+			mode:       header.FileInfo().Mode(),
+			linkTarget: header.Linkname,
+			open: func() (io.ReadCloser, error) {
+				return io.NopCloser(reader), nil
+			},
+			sys: header,
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+}
+
+// tarDevice returns the device numbers of a device node entry.
+func tarDevice(h *tar.Header) *Device {
+	if h.Typeflag != tar.TypeChar && h.Typeflag != tar.TypeBlock {
+		return nil
+	}
+	return &Device{Major: h.Devmajor, Minor: h.Devminor}
+}
+
+// walkTar calls fn for every entry of a tar stream. Long names and link
+// targets stored in PAX records or in GNU ././@LongLink entries are merged
+// into the header of the entry they belong to by archive/tar, so the
+// ././@LongLink entries themselves are never visited.
+func walkTar(ctx context.Context, r io.Reader, fn walkFunc) error {
+	tr := tar.NewReader(r)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		e := &entry{
+			info: FileInfo{
+				Name:   header.Name,
+				Size:   header.Size,
+				Type:   fileType(header.Name, header.FileInfo().Mode()),
+				Xattrs: paxXattrs(header.PAXRecords),
+				Device: tarDevice(header),
+				UID:    &header.Uid,
+				GID:    &header.Gid,
+				Uname:  header.Uname,
+				Gname:  header.Gname,
+			},
+			mode:       header.FileInfo().Mode(),
+			linkTarget: header.Linkname,
+			open: func() (io.ReadCloser, error) {
+				return io.NopCloser(tr), nil
+			},
+			sys: header,
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+}
+
+// PAX record prefixes used by GNU tar, star and bsdtar for extended
+// attributes and POSIX ACLs.
+const (
+	paxXattrPrefix = "SCHILY.xattr."
+	paxACLPrefix   = "SCHILY.acl."
+)
+
+// paxXattrs returns the extended attributes, keyed by attribute name, and
+// the ACLs, keyed by their PAX record name, of a tar entry.
+func paxXattrs(records map[string]string) map[string]string {
+	var xattrs map[string]string
+	for key, value := range records {
+		var name string
+		switch {
+		case strings.HasPrefix(key, paxXattrPrefix):
+			name = strings.TrimPrefix(key, paxXattrPrefix)
+		case strings.HasPrefix(key, paxACLPrefix):
+			name = key
+		default:
+			continue
+		}
+		if xattrs == nil {
+			xattrs = make(map[string]string)
+		}
+		xattrs[name] = value
+	}
+	return xattrs
+}
+
+func walkZip(ctx context.Context, r io.ReaderAt, size int64, maxEntries, threshold int, fn walkFunc) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+	// The central directory reveals the number of entries up front.
+	if maxEntries > 0 && len(zr.File) > maxEntries {
+		return tooManyEntries(maxEntries)
+	}
+
+	// Large central directories are transformed in parallel.
+	infos := make([]FileInfo, len(zr.File))
+	err = forEachChunk(len(zr.File), threshold, func(start, end int) error {
+		for i := start; i < end; i++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			infos[i] = zipFileInfo(zr.File[i])
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, f := range zr.File {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		e := &entry{
+			info: infos[i],
+			open: func() (io.ReadCloser, error) {
+				rc, err := f.Open()
+				if err != nil {
+					return nil, err
+				}
+				return struct {
+					io.Reader
+					io.Closer
+				}{&ctxReader{ctx: ctx, r: rc}, rc}, nil
+			},
+			mode: f.Mode(),
+			sys:  f,
+		}
+		if zipSizeUnknown(f) {
+			e.open = func() (io.ReadCloser, error) { return openZipRaw(ctx, f) }
+			e.sizeUnknown = true
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// zipSizeUnknown reports whether the central directory declares an empty
+// entry that nevertheless has data. Some streaming writers only record the
+// size in the data descriptor.
+func zipSizeUnknown(f *zip.File) bool {
+	return f.UncompressedSize64 == 0 && f.CompressedSize64 > 0 && !f.Mode().IsDir()
+}
+
+// openZipRaw opens an entry bypassing the size and checksum checks of
+// archive/zip, which would reject content beyond the declared size.
+func openZipRaw(ctx context.Context, f *zip.File) (io.ReadCloser, error) {
+	raw, err := f.OpenRaw()
+	if err != nil {
+		return nil, err
+	}
+	r := &ctxReader{ctx: ctx, r: raw}
+	switch f.Method {
+	case zip.Store:
+		return io.NopCloser(r), nil
+	case zip.Deflate:
+		return flate.NewReader(r), nil
+	}
+	return nil, fmt.Errorf("unsupported compression method %d for %s", f.Method, f.Name)
+}
+
+// zipSize converts a zip64 size to an int64, saturating sizes beyond
+// math.MaxInt64 that only crafted headers declare. checkEntrySize refuses
+// those on extraction.
+func zipSize(n uint64) int64 {
+	if n > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(n)
+}
+
+func zipFileInfo(f *zip.File) FileInfo {
+	return FileInfo{
+		Name:           f.Name,
+		Size:           zipSize(f.UncompressedSize64),
+		Type:           fileType(f.Name, f.Mode()),
+		CompressedSize: zipSize(f.CompressedSize64),
+		CRC32:          f.CRC32,
+	}
+}
+
+// dataOffset returns the offset of the data of the zip entry e in the
+// archive, or 0 for entries of other formats. Locating the data reads the
+// local header of the entry, so only listings reporting FileInfo.DataOffset
+// call it. An entry whose local header cannot be read is listed without an
+// offset rather than failing the listing.
+func dataOffset(e *entry) int64 {
+	f, ok := e.sys.(*zip.File)
+	if !ok {
+		return 0
+	}
+	offset, err := f.DataOffset()
+	if err != nil {
+		return 0
+	}
+	return offset
+}
+
+// withDataOffsets returns a walker that sets FileInfo.DataOffset of the
+// entries visited by walk, see dataOffset.
+func withDataOffsets(walk walker) walker {
+	return func(fn walkFunc) error {
+		return walk(func(e *entry) error {
+			e.info.DataOffset = dataOffset(e)
+			return fn(e)
+		})
+	}
+}
+
+// Entry types reported in FileInfo.Type.
+const (
+	typeFile    = "file"
+	typeDir     = "dir"
+	typeSymlink = "symlink"
+	typeDevice  = "device"
+	typeChar    = "char"
+	typeFifo    = "fifo"
+	typeOther   = "other"
+)
+
+// checkContent returns an error for entries of a special type, which have
+// no content to extract.
+func checkContent(info FileInfo) error {
+	switch info.Type {
+	case typeDevice:
+		return fmt.Errorf("cannot extract %s, which is a block device without content", info.Name)
+	case typeChar:
+		return fmt.Errorf("cannot extract %s, which is a character device without content", info.Name)
+	case typeFifo:
+		return fmt.Errorf("cannot extract %s, which is a FIFO without content", info.Name)
+	}
+	return nil
+}
+
+// fileType derives the entry type from its mode, treating names with a
+// trailing slash as directories for archivers that do not set the mode.
+func fileType(name string, mode fs.FileMode) string {
+	switch {
+	case mode.IsDir() || strings.HasSuffix(name, "/"):
+		return typeDir
+	case mode&fs.ModeSymlink != 0:
+		return typeSymlink
+	case mode&fs.ModeCharDevice != 0:
+		return typeChar
+	case mode&fs.ModeDevice != 0:
+		return typeDevice
+	case mode&fs.ModeNamedPipe != 0:
+		return typeFifo
+	case mode.IsRegular():
+		return typeFile
+	default:
+		return typeOther
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultMaxGlobArchives is the default maximum number of archives a
+// list_archives pattern may match.
+const defaultMaxGlobArchives = 100
+
+// GlobOptions are the options for listing the files of all archives
+// matching a pattern.
+type GlobOptions struct {
+	Pattern        string `json:"pattern" jsonschema:"an absolute glob pattern matching the archives to list, e.g. /data/logs-2024-*.tar.gz"`
+	Depth          int    `json:"depth" jsonschema:"the depth of the directory tree to list. 0 means the complete directory tree"`
+	Limit          int    `json:"limit,omitempty" jsonschema:"the maximum number of files to display across all archives. If not set, it will default to 100"`
+	IncludePattern string `json:"include,omitempty" jsonschema:"an optional regular expression, or glob with pattern_syntax glob, to include files"`
+	ExcludePattern string `json:"exclude,omitempty" jsonschema:"an optional regular expression, or glob with pattern_syntax glob, to exclude files"`
+	PatternSyntax  string `json:"pattern_syntax,omitempty" jsonschema:"the syntax of include and exclude: regexp or glob. If not set, it will default to regexp"`
+	TypeFilter     string `json:"type,omitempty" jsonschema:"list only entries of this type: file or dir. If not set, all entries are listed"`
+}
+
+// ListArchivesArgs are the arguments for the list_archives tool.
+type ListArchivesArgs GlobOptions
+
+// ListArchivesResult holds the result of the list_archives tool.
+type ListArchivesResult struct {
+	// Archives are the archives matching the pattern that were listed.
+	Archives       []string   `json:"archives"`
+	TotalFiles     int        `json:"total_files"`
+	FilteredFiles  int        `json:"filtered_files"`
+	DisplayedFiles int        `json:"displayed_files"`
+	Files          []FileInfo `json:"files"`
+}
+
+// ListGlob lists the files of all archives matching a glob pattern. Each
+// file is annotated with the archive it came from. Matches that are not
+// supported archives, whose format is disabled or that are rejected by the
+// path confinement are skipped.
+func (a *Archive) ListGlob(ctx context.Context, opts GlobOptions) (ListArchivesResult, error) {
+	listOpts := ListOptions{
+		Depth:          opts.Depth,
+		IncludePattern: opts.IncludePattern,
+		ExcludePattern: opts.ExcludePattern,
+		PatternSyntax:  opts.PatternSyntax,
+		TypeFilter:     opts.TypeFilter,
+	}
+	if err := listOpts.validate(); err != nil {
+		return ListArchivesResult{}, err
+	}
+	if !filepath.IsAbs(opts.Pattern) {
+		return ListArchivesResult{}, fmt.Errorf("pattern is not an absolute path: %s", opts.Pattern)
+	}
+	matches, err := filepath.Glob(opts.Pattern)
+	if err != nil {
+		return ListArchivesResult{}, fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	archives := []string{}
+	for _, match := range matches {
+		path, err := a.secureArchivePath(match)
+		if err != nil {
+			a.audit(ctx, match, err)
+			continue
+		}
+		if f, ok := formatFor(path); !ok || !a.formatEnabled(f) {
+			continue
+		}
+		archives = append(archives, path)
+	}
+	if len(archives) > a.maxGlobArchives {
+		return ListArchivesResult{}, fmt.Errorf("pattern %s matches %d archives, more than the maximum of %d", opts.Pattern, len(archives), a.maxGlobArchives)
+	}
+
+	callCtx, cancel := a.callContext(ctx)
+	defer cancel()
+	release, err := a.acquire(callCtx)
+	if err != nil {
+		return ListArchivesResult{}, err
+	}
+	defer release()
+
+	result := ListArchivesResult{Archives: archives}
+	filtered := []FileInfo{}
+	for _, path := range archives {
+		files, err := a.list(callCtx, path)
+		if err != nil {
+			return ListArchivesResult{}, fmt.Errorf("%s: %w", path, a.timeoutError(ctx, err))
+		}
+		l, err := a.filterListing(files, listOpts)
+		if err != nil {
+			return ListArchivesResult{}, err
+		}
+		for i := range l.filtered {
+			l.filtered[i].Archive = path
+		}
+		result.TotalFiles += l.total
+		filtered = append(filtered, l.filtered...)
+	}
+
+	result.FilteredFiles = len(filtered)
+	result.DisplayedFiles = displayLimit(opts.Limit, len(filtered))
+	result.Files = filtered[:result.DisplayedFiles]
+	return result, nil
+}
+
+// ListArchives lists the files of all archives matching a pattern.
+func (a *Archive) ListArchives(ctx context.Context, req *mcp.CallToolRequest, args ListArchivesArgs) (*mcp.CallToolResult, ListArchivesResult, error) {
+	return callTool(ctx, a, req, "list_archives", args.Pattern, GlobOptions(args), a.ListGlob)
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestListGlob(t *testing.T) {
+	a := newTestArchive(t)
+	result, err := a.ListGlob(context.Background(), GlobOptions{
+		Pattern: filepath.Join(a.Workdir, "test.tar.*"),
+		Limit:   1000,
+	})
+	if err != nil {
+		t.Fatalf("ListGlob failed: %v", err)
+	}
+	if len(result.Archives) != 5 {
+		t.Fatalf("expected 5 archives, got %v", result.Archives)
+	}
+	if result.TotalFiles != 15 || result.DisplayedFiles != 15 {
+		t.Errorf("expected 15 files, got total %d displayed %d", result.TotalFiles, result.DisplayedFiles)
+	}
+	perArchive := map[string]int{}
+	for _, f := range result.Files {
+		perArchive[f.Archive]++
+	}
+	for _, path := range result.Archives {
+		if perArchive[path] != 3 {
+			t.Errorf("expected 3 files from %s, got %d", path, perArchive[path])
+		}
+	}
+}
+
+func TestListGlob_Filter(t *testing.T) {
+	a := newTestArchive(t)
+	result, err := a.ListGlob(context.Background(), GlobOptions{
+		Pattern:    filepath.Join(a.Workdir, "test.*"),
+		TypeFilter: typeFile,
+		Limit:      2,
+	})
+	if err != nil {
+		t.Fatalf("ListGlob failed: %v", err)
+	}
+	if result.FilteredFiles <= 2 || result.DisplayedFiles != 2 || len(result.Files) != 2 {
+		t.Errorf("expected 2 of %d filtered files displayed, got %d", result.FilteredFiles, result.DisplayedFiles)
+	}
+	for _, f := range result.Files {
+		if f.Type != typeFile {
+			t.Errorf("expected only files, got %s of type %s", f.Name, f.Type)
+		}
+	}
+}
+
+func TestListGlob_TooManyArchives(t *testing.T) {
+	a := newTestArchive(t)
+	a.maxGlobArchives = 2
+	_, err := a.ListGlob(context.Background(), GlobOptions{Pattern: filepath.Join(a.Workdir, "test.tar.*")})
+	if err == nil || !strings.Contains(err.Error(), "more than the maximum of 2") {
+		t.Errorf("expected too many archives error, got %v", err)
+	}
+}
+
+func TestListGlob_RelativePattern(t *testing.T) {
+	a := newTestArchive(t)
+	_, err := a.ListGlob(context.Background(), GlobOptions{Pattern: "*.zip"})
+	if err == nil || !strings.Contains(err.Error(), "not an absolute path") {
+		t.Errorf("expected relative pattern error, got %v", err)
+	}
+}
+
+func TestListGlob_Denied(t *testing.T) {
+	tmp := t.TempDir()
+	data, err := os.ReadFile("../testdata/test.tar.gz")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	for _, dir := range []string{"pub", "secret"} {
+		if err := os.MkdirAll(filepath.Join(tmp, dir), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(tmp, dir, "test.tar.gz"), data, 0o644); err != nil {
+			t.Fatalf("failed to write archive: %v", err)
+		}
+	}
+	a, err := New(tmp, WithDeny(filepath.Join(tmp, "secret")))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	h := captureLogs(t)
+	result, err := a.ListGlob(context.Background(), GlobOptions{Pattern: filepath.Join(a.Workdir, "*", "test.tar.gz")})
+	if err != nil {
+		t.Fatalf("ListGlob failed: %v", err)
+	}
+	if len(result.Archives) != 1 || filepath.Base(filepath.Dir(result.Archives[0])) != "pub" {
+		t.Errorf("expected only the pub archive, got %v", result.Archives)
+	}
+	warnings := h.warnings()
+	if len(warnings) != 1 || warnings[0]["reason"] != reasonDenied {
+		t.Errorf("expected 1 denied audit record, got %v", warnings)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultMaxGrepLines is the default maximum number of lines returned by
+// Grep across all files.
+const defaultMaxGrepLines = 1000
+
+// GrepOptions are the options for returning the lines of the files of an
+// archive that match a regular expression.
+type GrepOptions struct {
+	Path           string `json:"path" jsonschema:"the path to the archive"`
+	Pattern        string `json:"pattern" jsonschema:"a regular expression matched against each line of each file"`
+	IncludePattern string `json:"include,omitempty" jsonschema:"an optional regular expression, or glob with pattern_syntax glob, to restrict the scanned files by name"`
+	ExcludePattern string `json:"exclude,omitempty" jsonschema:"an optional regular expression, or glob with pattern_syntax glob, to exclude files by name"`
+	PatternSyntax  string `json:"pattern_syntax,omitempty" jsonschema:"the syntax of include and exclude: regexp or glob. The line pattern is always a regular expression. If not set, it will default to regexp"`
+	// Context is the number of lines returned before and after each
+	// matching line, like grep -C.
+	Context  int  `json:"context,omitempty" jsonschema:"the number of lines to return before and after each matching line, like grep -C"`
+	Binary   bool `json:"binary,omitempty" jsonschema:"also scan files that are not valid UTF-8. They are skipped by default"`
+	MaxLines int  `json:"max_lines,omitempty" jsonschema:"the maximum number of lines to return across all files, including context lines. If not set, it will default to 1000"`
+}
+
+// ExtractGrepArgs are the arguments for the extract_grep tool.
+type ExtractGrepArgs GrepOptions
+
+// GrepLine is a line of a file returned by Grep.
+type GrepLine struct {
+	// Number is the one-based line number within the file.
+	Number int    `json:"number"`
+	Text   string `json:"text"`
+	// Match is set for lines matching the pattern, as opposed to context
+	// lines.
+	Match bool `json:"match,omitempty"`
+}
+
+// GrepFile holds the lines returned for a file with matching lines.
+type GrepFile struct {
+	Name string `json:"name"`
+	// Lines are the matching lines and their context in file order. A gap
+	// in the line numbers separates the groups, like -- in grep output.
+	Lines []GrepLine `json:"lines"`
+}
+
+// ExtractGrepResult holds the result of the extract_grep tool.
+type ExtractGrepResult struct {
+	Files []GrepFile `json:"files"`
+	// Scanned is the number of files whose content was tested.
+	Scanned int `json:"scanned"`
+	// Truncated is set if scanning stopped because the maximum number of
+	// lines was reached.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// grepLines returns the lines of data matching re together with around
+// context lines before and after them, at most limit lines. It reports
+// whether lines were left out because of limit. A trailing carriage return
+// is stripped from each line.
+func grepLines(data []byte, re *regexp.Regexp, around, limit int) ([]GrepLine, bool) {
+	lines := bytes.Split(data, []byte("\n"))
+	if len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	var result []GrepLine
+	// next is the index of the first line not yet returned.
+	next := 0
+	for i, line := range lines {
+		if !re.Match(bytes.TrimSuffix(line, []byte("\r"))) {
+			continue
+		}
+		start := max(next, i-around)
+		end := min(len(lines), i+around+1)
+		for j := start; j < end; j++ {
+			if len(result) == limit {
+				return result, true
+			}
+			text := bytes.TrimSuffix(lines[j], []byte("\r"))
+			result = append(result, GrepLine{Number: j + 1, Text: string(text), Match: re.Match(text)})
+		}
+		next = max(next, end)
+	}
+	return result, false
+}
+
+// Grep returns the lines of the regular files of an archive that match a
+// regular expression, with optional context lines. Files larger than the
+// maximum file size are skipped, as are files that are not valid UTF-8
+// unless Binary is set. Scanning stops once MaxLines lines were collected.
+func (a *Archive) Grep(ctx context.Context, opts GrepOptions) (ExtractGrepResult, error) {
+	if opts.Pattern == "" {
+		return ExtractGrepResult{}, errors.New("pattern is required")
+	}
+	re, err := regexp.Compile(opts.Pattern)
+	if err != nil {
+		return ExtractGrepResult{}, fmt.Errorf("invalid pattern: %w", err)
+	}
+	filter, err := newFileFilter(ListOptions{IncludePattern: opts.IncludePattern, ExcludePattern: opts.ExcludePattern, PatternSyntax: opts.PatternSyntax, TypeFilter: typeFile})
+	if err != nil {
+		return ExtractGrepResult{}, err
+	}
+	if opts.Context < 0 {
+		return ExtractGrepResult{}, fmt.Errorf("invalid context %d: must not be negative", opts.Context)
+	}
+	if opts.MaxLines < 0 {
+		return ExtractGrepResult{}, fmt.Errorf("invalid maximum number of lines %d", opts.MaxLines)
+	}
+	maxLines := opts.MaxLines
+	if maxLines == 0 {
+		maxLines = defaultMaxGrepLines
+	}
+
+	read := a.timedRead(a.readEntry)
+	result := ExtractGrepResult{Files: []GrepFile{}}
+	returned := 0
+	err = a.walkArchive(ctx, opts.Path, func(call *archiveCall, e *entry) error {
+		if !filter.match(e.info) || call.ignored(e.info) {
+			return nil
+		}
+		if !e.sizeUnknown && e.info.Size > a.maxSize {
+			return nil
+		}
+		file, err := read(e)
+		var rerr *rejectedError
+		if errors.As(err, &rerr) && rerr.reason == reasonTooLarge {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !opts.Binary && !utf8.Valid(file.RawContent) {
+			return nil
+		}
+		result.Scanned++
+		lines, truncated := grepLines(file.RawContent, re, opts.Context, maxLines-returned)
+		if len(lines) > 0 {
+			result.Files = append(result.Files, GrepFile{Name: file.Name, Lines: lines})
+			returned += len(lines)
+		}
+		if truncated {
+			result.Truncated = true
+			return errStopWalk
+		}
+		return nil
+	})
+	if err != nil {
+		return ExtractGrepResult{}, err
+	}
+	return result, nil
+}
+
+// ExtractGrep returns the lines of the files of an archive that match a
+// regular expression.
+func (a *Archive) ExtractGrep(ctx context.Context, req *mcp.CallToolRequest, args ExtractGrepArgs) (*mcp.CallToolResult, ExtractGrepResult, error) {
+	return callTool(ctx, a, req, "extract_grep", args.Path, GrepOptions(args), a.Grep)
+}
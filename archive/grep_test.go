@@ -0,0 +1,120 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestGrepLines(t *testing.T) {
+	data := []byte("one\ntwo ERROR\r\nthree\nfour\nfive\nsix ERROR\nseven\neight\n")
+	re := regexp.MustCompile("ERROR")
+	for _, tc := range []struct {
+		around, limit int
+		want          []int
+		truncated     bool
+	}{
+		{0, 10, []int{2, 6}, false},
+		{1, 10, []int{1, 2, 3, 5, 6, 7}, false},
+		{2, 10, []int{1, 2, 3, 4, 5, 6, 7, 8}, false},
+		{1, 4, []int{1, 2, 3, 5}, true},
+	} {
+		lines, truncated := grepLines(data, re, tc.around, tc.limit)
+		var numbers []int
+		for _, line := range lines {
+			numbers = append(numbers, line.Number)
+		}
+		if !slices.Equal(numbers, tc.want) || truncated != tc.truncated {
+			t.Errorf("context %d limit %d: expected lines %v truncated %v, got %v %v", tc.around, tc.limit, tc.want, tc.truncated, numbers, truncated)
+		}
+	}
+
+	lines, _ := grepLines(data, re, 0, 10)
+	if lines[0] != (GrepLine{Number: 2, Text: "two ERROR", Match: true}) {
+		t.Errorf("unexpected line: %+v", lines[0])
+	}
+}
+
+func TestGrep(t *testing.T) {
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := writeSyntheticTar(t, a.Workdir, map[string]string{
+		"logs/app.log": "start\nERROR disk full\nretry\nERROR disk full\nstop\n",
+		"logs/db.log":  "ok\nok\n",
+		"logs/bin":     "ERROR\xff\n",
+	})
+	ctx := context.Background()
+
+	result, err := a.Grep(ctx, GrepOptions{Path: path, Pattern: "ERROR", Context: 1})
+	if err != nil {
+		t.Fatalf("Grep failed: %v", err)
+	}
+	if result.Scanned != 2 || result.Truncated {
+		t.Errorf("expected 2 files scanned without truncation, got %d, %v", result.Scanned, result.Truncated)
+	}
+	if len(result.Files) != 1 || result.Files[0].Name != "logs/app.log" {
+		t.Fatalf("expected matches in logs/app.log only, got %+v", result.Files)
+	}
+	want := []GrepLine{
+		{1, "start", false},
+		{2, "ERROR disk full", true},
+		{3, "retry", false},
+		{4, "ERROR disk full", true},
+		{5, "stop", false},
+	}
+	if !slices.Equal(result.Files[0].Lines, want) {
+		t.Errorf("expected %+v, got %+v", want, result.Files[0].Lines)
+	}
+
+	result, err = a.Grep(ctx, GrepOptions{Path: path, Pattern: "ERROR", Binary: true, MaxLines: 2})
+	if err != nil {
+		t.Fatalf("Grep failed: %v", err)
+	}
+	if !result.Truncated {
+		t.Error("expected the result to be truncated")
+	}
+	var total int
+	for _, f := range result.Files {
+		total += len(f.Lines)
+	}
+	if total != 2 {
+		t.Errorf("expected 2 lines, got %d", total)
+	}
+
+	for _, opts := range []GrepOptions{
+		{Path: path},
+		{Path: path, Pattern: "("},
+		{Path: path, Pattern: "ERROR", Context: -1},
+		{Path: path, Pattern: "ERROR", MaxLines: -1},
+	} {
+		if _, err := a.Grep(ctx, opts); err == nil {
+			t.Errorf("expected options %+v to fail", opts)
+		}
+	}
+}
+
+func TestGrep_MaxSize(t *testing.T) {
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	a.maxSize = 8
+	path := writeSyntheticTar(t, a.Workdir, map[string]string{
+		"big.log":   "ERROR in a large file\n",
+		"small.log": "ERROR\n",
+	})
+	result, err := a.Grep(context.Background(), GrepOptions{Path: path, Pattern: "ERROR"})
+	if err != nil {
+		t.Fatalf("Grep failed: %v", err)
+	}
+	if result.Scanned != 1 || len(result.Files) != 1 || result.Files[0].Name != "small.log" {
+		t.Errorf("expected only small.log to be scanned, got %+v", result)
+	}
+}
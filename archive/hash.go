@@ -0,0 +1,117 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// HashOptions are the options for extracting the files of an archive by
+// the SHA256 of their content.
+type HashOptions struct {
+	Path   string   `json:"path" jsonschema:"the path to the archive"`
+	Hashes []string `json:"hashes" jsonschema:"the hex encoded SHA256 of the content of each file to extract"`
+}
+
+// ExtractByHashArgs are the arguments for the extract_by_hash tool.
+type ExtractByHashArgs HashOptions
+
+// HashMatch is a file whose content has one of the requested hashes.
+type HashMatch struct {
+	SHA256 string `json:"sha256"`
+	File
+}
+
+// ExtractByHashResult holds the result of the extract_by_hash tool.
+type ExtractByHashResult struct {
+	// Files holds the first file found for each requested hash, in
+	// archive order.
+	Files []HashMatch `json:"files"`
+	// Missing are the requested hashes no file has, sorted.
+	Missing []string `json:"missing"`
+	// Scanned is the number of files whose content was hashed.
+	Scanned int `json:"scanned"`
+}
+
+// ExtractByHash returns the content of the regular files of an archive
+// whose SHA256 is one of the requested hashes, for clients that know a blob
+// but not its name. Of files with the same content, the first one is
+// returned. Files larger than the maximum file size are skipped, and
+// scanning stops once every hash was found.
+func (a *Archive) ExtractByHash(ctx context.Context, opts HashOptions) (ExtractByHashResult, error) {
+	if len(opts.Hashes) == 0 {
+		return ExtractByHashResult{}, errors.New("hashes are required")
+	}
+	wanted := make(map[string]bool, len(opts.Hashes))
+	for _, sum := range opts.Hashes {
+		if b, err := hex.DecodeString(sum); err != nil || len(b) != sha256.Size {
+			return ExtractByHashResult{}, fmt.Errorf("invalid SHA256 %q", sum)
+		}
+		wanted[strings.ToLower(sum)] = true
+	}
+
+	read := a.timedRead(a.readEntry)
+	result := ExtractByHashResult{Files: []HashMatch{}, Missing: []string{}}
+	var files []File
+	err := a.walkArchive(ctx, opts.Path, func(call *archiveCall, e *entry) error {
+		if e.info.Type != typeFile || call.ignored(e.info) {
+			return nil
+		}
+		if !e.sizeUnknown && e.info.Size > a.maxSize {
+			return nil
+		}
+		file, err := read(e)
+		var rerr *rejectedError
+		if errors.As(err, &rerr) && rerr.reason == reasonTooLarge {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		result.Scanned++
+		sum := sha256.Sum256(file.RawContent)
+		key := hex.EncodeToString(sum[:])
+		if !wanted[key] {
+			return nil
+		}
+		delete(wanted, key)
+		result.Files = append(result.Files, HashMatch{SHA256: key, File: file})
+		files = append(files, file)
+		if len(wanted) == 0 {
+			return errStopWalk
+		}
+		return nil
+	})
+	if err != nil {
+		return ExtractByHashResult{}, err
+	}
+	if err := checkResponseSize(files, false, 0, a.maxResponseBytes); err != nil {
+		return ExtractByHashResult{}, err
+	}
+	a.metrics.extracted(files)
+	for i := range result.Files {
+		result.Files[i].Content = string(result.Files[i].RawContent)
+		result.Files[i].RawContent = nil
+	}
+	for sum := range wanted {
+		result.Missing = append(result.Missing, sum)
+	}
+	slices.Sort(result.Missing)
+	return result, nil
+}
+
+// ExtractFilesByHash extracts the files of an archive with the given
+// SHA256 hashes.
+func (a *Archive) ExtractFilesByHash(ctx context.Context, req *mcp.CallToolRequest, args ExtractByHashArgs) (*mcp.CallToolResult, ExtractByHashResult, error) {
+	return callTool(ctx, a, req, "extract_by_hash", args.Path, HashOptions(args), a.ExtractByHash)
+}
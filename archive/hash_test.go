@@ -0,0 +1,49 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"crypto/sha256"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExtractByHash(t *testing.T) {
+	a := newTestArchive(t)
+	baar := sha256Hex("das Pferd isst Gurkensalat\n")
+	unknown := sha256Hex("not in the archive")
+	for _, archiveType := range []string{"test.cpio", "test.tar.gz", "test.zip"} {
+		result, err := a.ExtractByHash(context.Background(), HashOptions{
+			Path:   filepath.Join(a.Workdir, archiveType),
+			Hashes: []string{strings.ToUpper(baar), unknown},
+		})
+		if err != nil {
+			t.Fatalf("ExtractByHash %s failed: %v", archiveType, err)
+		}
+		if len(result.Files) != 1 {
+			t.Fatalf("%s: expected 1 file, got %+v", archiveType, result.Files)
+		}
+		got := result.Files[0]
+		if got.Name != "foo/baar.txt" || got.SHA256 != baar || got.Content != "das Pferd isst Gurkensalat\n" {
+			t.Errorf("%s: unexpected file %+v", archiveType, got)
+		}
+		if !slices.Equal(result.Missing, []string{unknown}) {
+			t.Errorf("%s: expected %s to be missing, got %v", archiveType, unknown, result.Missing)
+		}
+	}
+}
+
+func TestExtractByHash_InvalidHash(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "test.zip")
+	for _, hashes := range [][]string{nil, {"abc"}, {strings.Repeat("zz", sha256.Size)}} {
+		if _, err := a.ExtractByHash(context.Background(), HashOptions{Path: path, Hashes: hashes}); err == nil {
+			t.Errorf("expected an error for hashes %q", hashes)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ignoreFileName is the name of the file in a working directory root that
+// lists, in gitignore syntax, the archives and archive entries hidden from
+// clients. Patterns are matched against archive paths relative to the root
+// and against entry names inside the archives.
+const ignoreFileName = ".mcparchiveignore"
+
+// ignoreRule is a single pattern of an ignore file.
+type ignoreRule struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// ignoreList holds the rules of an ignore file in order.
+type ignoreList []ignoreRule
+
+// parseIgnore parses the gitignore syntax of an ignore file: blank lines and
+// lines starting with # are skipped, ! negates a pattern, a trailing slash
+// matches directories only and a slash at the start or in the middle anchors
+// the pattern at the top level. *, ? and character classes do not match a
+// slash, while ** matches across directories.
+func parseIgnore(data string) (ignoreList, error) {
+	var rules ignoreList
+	for i, line := range strings.Split(data, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var rule ignoreRule
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+
+		var expr strings.Builder
+		expr.WriteString("^")
+		if !anchored {
+			expr.WriteString("(?:.*/)?")
+		}
+		for j := 0; j < len(line); j++ {
+			switch c := line[j]; {
+			case strings.HasPrefix(line[j:], "**/"):
+				expr.WriteString("(?:.*/)?")
+				j += 2
+			case strings.HasPrefix(line[j:], "**"):
+				expr.WriteString(".*")
+				j++
+			case c == '*':
+				expr.WriteString("[^/]*")
+			case c == '?':
+				expr.WriteString("[^/]")
+			case c == '[':
+				end := strings.IndexByte(line[j+1:], ']')
+				if end < 0 {
+					return nil, fmt.Errorf("%s:%d: unterminated character class", ignoreFileName, i+1)
+				}
+				class := line[j+1 : j+1+end]
+				if strings.HasPrefix(class, "!") {
+					class = "^" + class[1:]
+				}
+				expr.WriteString("[" + class + "]")
+				j += end + 1
+			default:
+				expr.WriteString(regexp.QuoteMeta(string(c)))
+			}
+		}
+		// Matching a directory hides everything below it as well.
+		expr.WriteString("(/.*)?$")
+
+		re, err := regexp.Compile(expr.String())
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid pattern: %w", ignoreFileName, i+1, err)
+		}
+		rule.re = re
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// match reports whether name, a slash separated path, is ignored. The last
+// matching rule decides.
+func (l ignoreList) match(name string, isDir bool) bool {
+	name = strings.Trim(normalizeName(name), "/")
+	ignored := false
+	for _, rule := range l {
+		m := rule.re.FindStringSubmatch(name)
+		if m == nil {
+			continue
+		}
+		// A directory-only pattern matches a directory itself or
+		// anything below it.
+		if rule.dirOnly && !isDir && m[1] == "" {
+			continue
+		}
+		ignored = !rule.negate
+	}
+	return ignored
+}
+
+// ignoreFile is the ignore file of a working directory root. It is parsed
+// at startup and again whenever its modification time or size changes.
+type ignoreFile struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	rules   ignoreList
+}
+
+// newIgnoreFile loads the ignore file of root, if any.
+func newIgnoreFile(root string) (*ignoreFile, error) {
+	f := &ignoreFile{path: filepath.Join(root, ignoreFileName)}
+	if err := f.reload(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// reload parses the ignore file if it changed since it was last read. A
+// missing file has no rules.
+func (f *ignoreFile) reload() error {
+	stat, err := os.Stat(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		f.rules, f.modTime, f.size = nil, time.Time{}, 0
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if stat.ModTime().Equal(f.modTime) && stat.Size() == f.size {
+		return nil
+	}
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return err
+	}
+	rules, err := parseIgnore(string(data))
+	if err != nil {
+		return fmt.Errorf("%s: %w", filepath.Dir(f.path), err)
+	}
+	f.rules, f.modTime, f.size = rules, stat.ModTime(), stat.Size()
+	return nil
+}
+
+// current returns the up to date rules. If the file cannot be read or
+// parsed anymore, the previous rules stay in effect.
+func (f *ignoreFile) current() ignoreList {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.reload(); err != nil {
+		slog.Warn("failed to reload ignore file", "path", f.path, "error", err)
+	}
+	return f.rules
+}
+
+// ignoreRules returns the rules of the root holding path and the path
+// relative to that root.
+func (a *Archive) ignoreRules(path string) (ignoreList, string) {
+	for _, root := range a.roots {
+		if !within(root, path) {
+			continue
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil, ""
+		}
+		return a.ignores[root].current(), filepath.ToSlash(rel)
+	}
+	return nil, ""
+}
+
+// ignoredEntries returns a predicate reporting whether an entry of the
+// archive at path is hidden by the ignore file of its root.
+func (a *Archive) ignoredEntries(path string) func(FileInfo) bool {
+	rules, _ := a.ignoreRules(path)
+	return func(info FileInfo) bool {
+		return rules.match(info.Name, info.Type == typeDir)
+	}
+}
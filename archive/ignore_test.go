@@ -0,0 +1,150 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIgnoreList_Match(t *testing.T) {
+	for _, tc := range []struct {
+		patterns string
+		name     string
+		isDir    bool
+		want     bool
+	}{
+		{"*.log", "foo/bar.log", false, true},
+		{"*.log", "foo/bar.txt", false, false},
+		{"/*.log", "foo/bar.log", false, false},
+		{"/*.log", "bar.log", false, true},
+		{"foo/*.log", "foo/bar.log", false, true},
+		{"foo/*.log", "x/foo/bar.log", false, false},
+		{"secret", "a/secret/key", false, true},
+		{"secret/", "a/secret/key", false, true},
+		{"secret/", "a/secret", false, false},
+		{"secret/", "a/secret/", true, true},
+		{"**/keys/*.pem", "a/b/keys/x.pem", false, true},
+		{"a/**/x", "a/b/c/x", false, true},
+		{"a/**", "a/b", false, true},
+		{"file?.[ch]", "file1.c", false, true},
+		{"file[!0-9].c", "file1.c", false, false},
+		{"*.log\n!keep.log", "keep.log", false, false},
+		{"*.log\n!keep.log", "drop.log", false, true},
+		{"# comment\n\nx", "x", false, true},
+		{"*.txt", "./foo/baar.txt", false, true},
+	} {
+		rules, err := parseIgnore(tc.patterns)
+		if err != nil {
+			t.Fatalf("parseIgnore(%q) failed: %v", tc.patterns, err)
+		}
+		if got := rules.match(tc.name, tc.isDir); got != tc.want {
+			t.Errorf("match(%q, %v) with %q = %v, want %v", tc.name, tc.isDir, tc.patterns, got, tc.want)
+		}
+	}
+}
+
+func TestParseIgnore_Invalid(t *testing.T) {
+	_, err := parseIgnore("ok\nbad[")
+	if err == nil || !strings.Contains(err.Error(), ".mcparchiveignore:2") {
+		t.Errorf("expected error for line 2, got: %v", err)
+	}
+}
+
+// newIgnoreTree creates a workdir holding test.tar.gz at the top level and in
+// a secret directory, with the given ignore file.
+func newIgnoreTree(t *testing.T, ignore string) *Archive {
+	dir := t.TempDir()
+	data, err := os.ReadFile("../testdata/test.tar.gz")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "secret"), 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	for _, name := range []string{"test.tar.gz", "secret/test.tar.gz"} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatalf("failed to write archive: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, ignoreFileName), []byte(ignore), 0o644); err != nil {
+		t.Fatalf("failed to write ignore file: %v", err)
+	}
+	a, err := New(dir)
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	return a
+}
+
+func TestIgnoreFile(t *testing.T) {
+	a := newIgnoreTree(t, "secret/\nbazz\n")
+	path := filepath.Join(a.Workdir, "test.tar.gz")
+
+	result, err := a.List(context.Background(), ListOptions{Path: path})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	for _, f := range result.Files {
+		if f.Name == "foo/bazz" {
+			t.Errorf("expected foo/bazz to be hidden, got %v", result.Files)
+		}
+	}
+	if result.TotalFiles != 2 {
+		t.Errorf("expected 2 files, got %d", result.TotalFiles)
+	}
+
+	h := captureLogs(t)
+	_, err = a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/bazz"}})
+	if err == nil || !strings.Contains(err.Error(), "hidden by server policy") {
+		t.Errorf("expected hidden entry error, got: %v", err)
+	}
+	_, err = a.List(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, "secret", "test.tar.gz")})
+	if err == nil || !strings.Contains(err.Error(), "hidden by server policy") {
+		t.Errorf("expected hidden archive error, got: %v", err)
+	}
+	warnings := h.warnings()
+	if len(warnings) != 2 || warnings[0]["reason"] != reasonIgnored || warnings[0]["entry"] != "foo/bazz" || warnings[1]["reason"] != reasonIgnored {
+		t.Errorf("expected 2 ignored audit records, got %v", warnings)
+	}
+}
+
+func TestIgnoreFile_Reload(t *testing.T) {
+	a := newIgnoreTree(t, "bazz\n")
+	path := filepath.Join(a.Workdir, "test.tar.gz")
+
+	ignore := filepath.Join(a.Workdir, ignoreFileName)
+	if err := os.WriteFile(ignore, []byte("*.txt\n"), 0o644); err != nil {
+		t.Fatalf("failed to write ignore file: %v", err)
+	}
+	// Make the change visible even on filesystems with coarse timestamps.
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(ignore, later, later); err != nil {
+		t.Fatalf("failed to touch ignore file: %v", err)
+	}
+
+	result, err := a.List(context.Background(), ListOptions{Path: path, TypeFilter: typeFile})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].Name != "foo/bazz" {
+		t.Errorf("expected only foo/bazz after reload, got %v", result.Files)
+	}
+
+	if err := os.Remove(ignore); err != nil {
+		t.Fatalf("failed to remove ignore file: %v", err)
+	}
+	result, err = a.List(context.Background(), ListOptions{Path: path, TypeFilter: typeFile})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(result.Files) != 2 {
+		t.Errorf("expected all files after removing the ignore file, got %v", result.Files)
+	}
+}
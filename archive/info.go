@@ -0,0 +1,97 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"context"
+	"maps"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// InfoOptions are the options for reading the metadata of an archive.
+type InfoOptions struct {
+	Path string `json:"path" jsonschema:"the path to the archive"`
+}
+
+// ArchiveInfoArgs are the arguments for the archive_info tool.
+type ArchiveInfoArgs InfoOptions
+
+// ArchiveInfoResult holds the result of the archive_info tool.
+type ArchiveInfoResult struct {
+	Format string `json:"format"`
+	// Entries is the number of entries, not counting tar global headers.
+	Entries int `json:"entries"`
+	// Comment is the comment of a zip archive.
+	Comment string `json:"comment,omitempty"`
+	// PAXGlobal holds the records of all PAX global headers of a tar
+	// archive, later headers overriding earlier ones.
+	PAXGlobal map[string]string `json:"pax_global,omitempty"`
+	// Compression holds the hints of a compressed stream about how it
+	// was produced, as far as the compression exposes them.
+	Compression *CompressionInfo `json:"compression,omitempty"`
+}
+
+// Info returns the format, the number of entries and the format-specific
+// metadata of an archive. It only reads the headers of the archive.
+func (a *Archive) Info(ctx context.Context, opts InfoOptions) (ArchiveInfoResult, error) {
+	c, err := a.startWalk(ctx, opts.Path)
+	if err != nil {
+		return ArchiveInfoResult{}, err
+	}
+	defer c.done()
+
+	f := c.format
+	result := ArchiveInfoResult{Format: f.name}
+	if f.container == containerZip {
+		r, err := a.openArchive(c.ctx, c.path)
+		if err != nil {
+			return ArchiveInfoResult{}, err
+		}
+		defer r.Close()
+		if err := a.checkExtension(c.path, f, r, r.size); err != nil {
+			a.audit(ctx, opts.Path, err)
+			return ArchiveInfoResult{}, err
+		}
+		zr, err := zip.NewReader(r, r.size)
+		if err != nil {
+			return ArchiveInfoResult{}, err
+		}
+		result.Entries = len(zr.File)
+		result.Comment = zr.Comment
+		return result, nil
+	}
+
+	r, err := a.openArchive(c.ctx, c.path)
+	if err != nil {
+		return ArchiveInfoResult{}, err
+	}
+	body, size := a.skipPrelude(f, r, r.size)
+	result.Compression = compressionInfo(f, body, size)
+	r.Close()
+
+	err = a.walk(c.ctx, c.path, func(e *entry) error {
+		if h, ok := e.sys.(*tar.Header); ok && h.Typeflag == tar.TypeXGlobalHeader {
+			if result.PAXGlobal == nil {
+				result.PAXGlobal = make(map[string]string)
+			}
+			maps.Copy(result.PAXGlobal, h.PAXRecords)
+			return nil
+		}
+		result.Entries++
+		return nil
+	})
+	if err != nil {
+		return ArchiveInfoResult{}, a.callError(ctx, opts.Path, err)
+	}
+	return result, nil
+}
+
+// ArchiveInfo returns the metadata of an archive.
+func (a *Archive) ArchiveInfo(ctx context.Context, req *mcp.CallToolRequest, args ArchiveInfoArgs) (*mcp.CallToolResult, ArchiveInfoResult, error) {
+	return callTool(ctx, a, req, "archive_info", args.Path, InfoOptions(args), a.Info)
+}
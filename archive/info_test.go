@@ -0,0 +1,120 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestInfo(t *testing.T) {
+	a := newTestArchive(t)
+	for archiveType, format := range map[string]string{
+		"test.cpio":    "cpio",
+		"test.cpio.gz": "cpio.gz",
+		"test.tar.gz":  "tar.gz",
+		"test.tar.bz2": "tar.bz2",
+		"test.tar.xz":  "tar.xz",
+		"test.tar.zst": "tar.zst",
+		"test.tar.sz":  "tar.sz",
+		"test.zip":     "zip",
+	} {
+		t.Run(archiveType, func(t *testing.T) {
+			result, err := a.Info(context.Background(), InfoOptions{Path: filepath.Join(a.Workdir, archiveType)})
+			if err != nil {
+				t.Fatalf("Info failed: %v", err)
+			}
+			if result.Format != format {
+				t.Errorf("expected format %s, got %s", format, result.Format)
+			}
+			if result.Entries != 3 {
+				t.Errorf("expected 3 entries, got %d", result.Entries)
+			}
+		})
+	}
+}
+
+func TestInfo_ZipComment(t *testing.T) {
+	dir := t.TempDir()
+	a, err := New(dir)
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := filepath.Join(a.Workdir, "comment.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create zip: %v", err)
+	}
+	zw := zip.NewWriter(f)
+	if _, err := zw.Create("foo/baar.txt"); err != nil {
+		t.Fatalf("failed to add zip entry: %v", err)
+	}
+	zw.SetComment("built by release.sh")
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to write zip: %v", err)
+	}
+	f.Close()
+
+	result, err := a.Info(context.Background(), InfoOptions{Path: path})
+	if err != nil {
+		t.Fatalf("Info failed: %v", err)
+	}
+	if result.Comment != "built by release.sh" {
+		t.Errorf("unexpected comment: %q", result.Comment)
+	}
+	if result.Entries != 1 {
+		t.Errorf("expected 1 entry, got %d", result.Entries)
+	}
+}
+
+func TestInfo_PAXGlobal(t *testing.T) {
+	dir := t.TempDir()
+	a, err := New(dir)
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := filepath.Join(a.Workdir, "global.tar.zst")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create tar: %v", err)
+	}
+	zw, err := zstd.NewWriter(f)
+	if err != nil {
+		t.Fatalf("failed to create zstd writer: %v", err)
+	}
+	tw := tar.NewWriter(zw)
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag:   tar.TypeXGlobalHeader,
+		PAXRecords: map[string]string{"comment": "built by ci", "mtime": "1700000000"},
+	}); err != nil {
+		t.Fatalf("failed to write global header: %v", err)
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: "foo/bazz", Mode: 0o644, Size: 5}); err != nil {
+		t.Fatalf("failed to write tar header: %v", err)
+	}
+	tw.Write([]byte("bazz\n"))
+	tw.Close()
+	zw.Close()
+	f.Close()
+
+	ctx := context.Background()
+	_, info, err := a.ArchiveInfo(ctx, &mcp.CallToolRequest{Session: &mcp.ServerSession{}}, ArchiveInfoArgs{Path: path})
+	if err != nil {
+		t.Fatalf("ArchiveInfo failed: %v", err)
+	}
+	if info.Entries != 1 {
+		t.Errorf("expected 1 entry, got %d", info.Entries)
+	}
+	if info.PAXGlobal["comment"] != "built by ci" || info.PAXGlobal["mtime"] != "1700000000" {
+		t.Errorf("unexpected PAX global records: %v", info.PAXGlobal)
+	}
+}
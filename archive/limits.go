@@ -0,0 +1,160 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// callContext derives the context for a single list or extract call,
+// applying the configured per-call timeout.
+func (a *Archive) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if a.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, a.timeout)
+}
+
+// timeoutError replaces a deadline error caused by the per-call timeout with
+// a descriptive one. Errors from the caller's own deadline are kept as is.
+func (a *Archive) timeoutError(parent context.Context, err error) error {
+	if a.timeout > 0 && errors.Is(err, context.DeadlineExceeded) && parent.Err() == nil {
+		return fmt.Errorf("timed out after %s", a.timeout)
+	}
+	return err
+}
+
+// acquire waits for a free slot for an archive operation, so that no more
+// than the configured number of operations open and decompress archives at
+// the same time. The returned function releases the slot. If ctx is done
+// first, a server busy error is returned.
+func (a *Archive) acquire(ctx context.Context) (func(), error) {
+	if a.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case a.slots <- struct{}{}:
+		return func() { <-a.slots }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("server busy: %d archive operations in progress: %w", cap(a.slots), ctx.Err())
+	}
+}
+
+// Default caps on the entries scanned per archive, see WithEntryLimits.
+const (
+	defaultMaxEntries    = 1_000_000
+	defaultMaxNameLength = 4096
+	defaultMaxPathDepth  = 256
+)
+
+// tooManyEntries is the error for archives exceeding maxEntries.
+func tooManyEntries(maxEntries int) error {
+	return fmt.Errorf("archive has more than %d entries", maxEntries)
+}
+
+// limitEntries wraps fn to fail once an archive holds more entries or
+// longer entry names than configured. Unlike the display limit of a listing
+// this bounds the number of entries scanned and thereby the memory used.
+func (a *Archive) limitEntries(fn walkFunc) walkFunc {
+	n := 0
+	return func(e *entry) error {
+		n++
+		if a.maxEntries > 0 && n > a.maxEntries {
+			return tooManyEntries(a.maxEntries)
+		}
+		if a.maxNameLength > 0 && len(e.info.Name) > a.maxNameLength {
+			return fmt.Errorf("entry name of %d bytes exceeds the maximum of %d", len(e.info.Name), a.maxNameLength)
+		}
+		if a.maxPathDepth > 0 {
+			if depth := pathDepth(e.info.Name); depth > a.maxPathDepth {
+				return fmt.Errorf("entry name of %d path segments exceeds the maximum depth of %d", depth, a.maxPathDepth)
+			}
+		}
+		return fn(e)
+	}
+}
+
+// pathDepth returns the number of non-empty path segments of name, so that
+// neither repeated nor trailing slashes count.
+func pathDepth(name string) int {
+	depth := 0
+	for i := 0; i < len(name); i++ {
+		if name[i] != '/' && (i == 0 || name[i-1] == '/') {
+			depth++
+		}
+	}
+	return depth
+}
+
+// ctxReader fails reads once its context is done, so that decompressing a
+// large entry stops when the call is canceled or times out.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// errEntryTimeout is returned for an entry whose content could not be read
+// within the entry timeout.
+var errEntryTimeout = errors.New("entry decompression timed out")
+
+// withinEntryTimeout runs fn, which reads the content of e through e.open,
+// and makes its reads fail once the entry timeout has passed, so that a
+// single pathological entry cannot stall a call until its timeout. fn runs
+// in the calling goroutine and has returned before the walk moves on to
+// the next entry and eventually closes the archive, which must not happen
+// while it still reads.
+func (a *Archive) withinEntryTimeout(e *entry, fn func() error) error {
+	if a.entryTimeout <= 0 {
+		return fn()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), a.entryTimeout)
+	defer cancel()
+	open := e.open
+	defer func() { e.open = open }()
+	e.open = func() (io.ReadCloser, error) {
+		rc, err := open()
+		if err != nil {
+			return nil, err
+		}
+		return struct {
+			io.Reader
+			io.Closer
+		}{&ctxReader{ctx: ctx, r: rc}, rc}, nil
+	}
+	err := fn()
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("%w after %s: %s", errEntryTimeout, a.entryTimeout, e.info.Name)
+	}
+	return err
+}
+
+// timedRead wraps read to enforce the entry timeout.
+func (a *Archive) timedRead(read func(*entry) (File, error)) func(*entry) (File, error) {
+	if a.entryTimeout <= 0 {
+		return read
+	}
+	return func(e *entry) (File, error) {
+		var file File
+		err := a.withinEntryTimeout(e, func() error {
+			var err error
+			file, err = read(e)
+			return err
+		})
+		if err != nil {
+			return File{}, err
+		}
+		return file, nil
+	}
+}
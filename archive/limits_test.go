@@ -0,0 +1,255 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeout(t *testing.T) {
+	a := newTestArchive(t)
+	a.timeout = time.Nanosecond
+	path := filepath.Join(a.Workdir, "test.tar.xz")
+
+	_, err := a.List(context.Background(), ListOptions{Path: path})
+	if err == nil {
+		t.Fatal("expected timeout error, but got nil")
+	}
+	if !strings.Contains(err.Error(), "timed out after 1ns") {
+		t.Fatalf("expected timeout error, got: %v", err)
+	}
+
+	_, err = a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/baar.txt"}})
+	if err == nil || !strings.Contains(err.Error(), "timed out after 1ns") {
+		t.Fatalf("expected timeout error, got: %v", err)
+	}
+}
+
+func TestTimeout_Disabled(t *testing.T) {
+	a := newTestArchive(t)
+	_, err := a.List(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, "test.tar.xz")})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+}
+
+func TestCanceledContext(t *testing.T) {
+	a := newTestArchive(t)
+	a.timeout = time.Minute
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := a.List(ctx, ListOptions{Path: filepath.Join(a.Workdir, "test.zip")})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled error, got: %v", err)
+	}
+}
+
+// writeSyntheticTar creates a gzip compressed tar holding the given files,
+// mapping names to content, in the order of their names and returns its
+// path.
+func writeSyntheticTar(t *testing.T, dir string, files map[string]string) string {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		content := files[name]
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Typeflag: tar.TypeReg, Size: int64(len(content))}); err != nil {
+			t.Fatalf("failed to write tar header: %v", err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatalf("failed to write tar content: %v", err)
+		}
+	}
+	tw.Close()
+	zw.Close()
+	path := filepath.Join(dir, "synthetic.tar.gz")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+	return path
+}
+
+func TestEntryLimits_Count(t *testing.T) {
+	a, err := New(t.TempDir(), WithEntryLimits(10, 0))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	files := make(map[string]string)
+	for i := range 11 {
+		files[fmt.Sprintf("foo/file%d", i)] = ""
+	}
+	for _, path := range []string{
+		writeSyntheticTar(t, a.Workdir, files),
+		writeSyntheticZip(t, a.Workdir, 11),
+	} {
+		_, err := a.List(context.Background(), ListOptions{Path: path})
+		if err == nil || !strings.Contains(err.Error(), "more than 10 entries") {
+			t.Errorf("expected too many entries error for %s, got: %v", filepath.Base(path), err)
+		}
+		_, err = a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/none"}})
+		if err == nil || !strings.Contains(err.Error(), "more than 10 entries") {
+			t.Errorf("expected too many entries error for %s, got: %v", filepath.Base(path), err)
+		}
+	}
+
+	a.maxEntries = 11
+	if _, err := a.List(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, "synthetic.tar.gz")}); err != nil {
+		t.Errorf("expected archive at the cap to be listed, got: %v", err)
+	}
+}
+
+func TestEntryLimits_NameLength(t *testing.T) {
+	a, err := New(t.TempDir(), WithEntryLimits(0, 255))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := writeSyntheticTar(t, a.Workdir, map[string]string{"foo/short": "", "foo/" + strings.Repeat("x", 300): ""})
+	_, err = a.List(context.Background(), ListOptions{Path: path})
+	if err == nil || !strings.Contains(err.Error(), "entry name of 304 bytes exceeds the maximum of 255") {
+		t.Errorf("expected name length error, got: %v", err)
+	}
+}
+
+func TestMaxPathDepth(t *testing.T) {
+	a, err := New(t.TempDir(), WithMaxPathDepth(100))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	// 200 segments in 400 bytes, well within the name length limit.
+	deep := strings.Repeat("d/", 199) + "f"
+	path := writeSyntheticTar(t, a.Workdir, map[string]string{"foo/short": "", deep: ""})
+	_, err = a.List(context.Background(), ListOptions{Path: path, Depth: 1})
+	if err == nil || !strings.Contains(err.Error(), "entry name of 200 path segments exceeds the maximum depth of 100") {
+		t.Errorf("expected path depth error, got: %v", err)
+	}
+	if _, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/short"}}); err == nil {
+		t.Error("expected extract to fail on the deep entry")
+	}
+
+	a.maxPathDepth = 200
+	if _, err := a.List(context.Background(), ListOptions{Path: path}); err != nil {
+		t.Errorf("expected archive at the cap to be listed, got: %v", err)
+	}
+	if depth := pathDepth("./a//b/c/"); depth != 4 {
+		t.Errorf("expected depth 4 counting the . segment, got %d", depth)
+	}
+}
+
+func TestMaxConcurrent(t *testing.T) {
+	a, err := New("../testdata", WithMaxConcurrent(1))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := filepath.Join(a.Workdir, "test.zip")
+
+	release, err := a.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = a.List(ctx, ListOptions{Path: path})
+	if err == nil || !strings.Contains(err.Error(), "server busy") {
+		t.Fatalf("expected server busy error, got: %v", err)
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/bazz"}})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		t.Fatalf("expected Extract to wait for a free slot, but it returned: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	release()
+	if err := <-done; err != nil {
+		t.Fatalf("Extract failed after the slot was released: %v", err)
+	}
+}
+
+func TestEntryTimeout(t *testing.T) {
+	dir := t.TempDir()
+	big := strings.Repeat("das Pferd isst Gurkensalat\n", 1<<11)
+	tarPath := writeSyntheticTar(t, dir, map[string]string{"big.txt": big, "fast.txt": "fast"})
+	zipPath := writeTestZip(t, dir, "synthetic.zip", "big.txt", big, "fast.txt", "fast")
+
+	for _, path := range []string{tarPath, zipPath} {
+		a, err := New(dir, WithEntryTimeout(time.Nanosecond))
+		if err != nil {
+			t.Fatalf("failed to create archive: %v", err)
+		}
+		_, err = a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"big.txt", "fast.txt"}})
+		if !errors.Is(err, errEntryTimeout) || !strings.Contains(err.Error(), "big.txt") {
+			t.Errorf("%s: expected entry timeout error, got: %v", path, err)
+		}
+		if _, err := a.Count(context.Background(), WCOptions{Path: path}); !errors.Is(err, errEntryTimeout) {
+			t.Errorf("%s: expected entry timeout error from wc, got: %v", path, err)
+		}
+
+		// Entries read in time are not affected.
+		a, err = New(dir, WithEntryTimeout(time.Minute))
+		if err != nil {
+			t.Fatalf("failed to create archive: %v", err)
+		}
+		result, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"big.txt", "fast.txt"}})
+		if err != nil || len(result.Files) != 2 || result.Files[0].Content != big || result.Files[1].Content != "fast" {
+			t.Errorf("%s: Extract failed: %v", path, err)
+		}
+	}
+}
+
+// blockingReader returns its content from a single Read that blocks for
+// delay.
+type blockingReader struct {
+	delay time.Duration
+	data  string
+	done  bool
+}
+
+func (r *blockingReader) Read(p []byte) (int, error) {
+	if r.done {
+		return 0, io.EOF
+	}
+	time.Sleep(r.delay)
+	r.done = true
+	return copy(p, r.data), nil
+}
+
+func TestEntryTimeout_BlockingRead(t *testing.T) {
+	a := newTestArchive(t)
+	a.entryTimeout = 10 * time.Millisecond
+	const delay = 100 * time.Millisecond
+	e := &entry{
+		info: FileInfo{Name: "slow.txt", Type: typeFile},
+		open: func() (io.ReadCloser, error) {
+			return io.NopCloser(&blockingReader{delay: delay, data: "slow"}), nil
+		},
+		sizeUnknown: true,
+	}
+	start := time.Now()
+	_, err := a.timedRead(a.readEntry)(e)
+	if !errors.Is(err, errEntryTimeout) {
+		t.Errorf("expected entry timeout error, got: %v", err)
+	}
+	// The timeout only takes effect once the blocked Read returns.
+	if elapsed := time.Since(start); elapsed < delay {
+		t.Errorf("expected the blocked Read to run to completion, returned after %s", elapsed)
+	}
+}
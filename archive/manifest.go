@@ -0,0 +1,186 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// packageType is a package format with a canonical manifest entry.
+type packageType struct {
+	name string
+	// suffixes are the file name suffixes of packages of this type.
+	suffixes []string
+	// format is the canonical name of the archive format of the package.
+	format string
+	// manifest is the path.Match pattern of the manifest entry.
+	manifest string
+}
+
+// packageTypes are the package formats known to Manifest.
+var packageTypes = []packageType{
+	{name: "jar", suffixes: []string{".jar", ".war", ".ear"}, format: "zip", manifest: "META-INF/MANIFEST.MF"},
+	{name: "wheel", suffixes: []string{".whl"}, format: "zip", manifest: "*.dist-info/METADATA"},
+	{name: "npm", suffixes: []string{".tgz"}, format: "tar.gz", manifest: "package/package.json"},
+}
+
+// packageTypeBySuffix returns the package type named by the suffix of p.
+func packageTypeBySuffix(p string) (packageType, bool) {
+	for _, pt := range packageTypes {
+		for _, suffix := range pt.suffixes {
+			if strings.HasSuffix(p, suffix) {
+				return pt, true
+			}
+		}
+	}
+	return packageType{}, false
+}
+
+// packageTypesFor returns the format of the package at p and the package
+// types it may be of. A package type suffix selects that type alone, while
+// a plain archive may be of any type of its format.
+func packageTypesFor(p string) (format, []packageType, bool) {
+	if pt, ok := packageTypeBySuffix(p); ok {
+		f, _ := formatByName(pt.format)
+		return f, []packageType{pt}, true
+	}
+	f, ok := formatFor(p)
+	if !ok {
+		return format{}, nil, false
+	}
+	var types []packageType
+	for _, pt := range packageTypes {
+		if pt.format == f.name {
+			types = append(types, pt)
+		}
+	}
+	return f, types, len(types) > 0
+}
+
+// jarManifest reports whether path names a Java archive and, if so,
+// whether files hold its manifest.
+func jarManifest(path string, files []FileInfo) (hasManifest, ok bool) {
+	pt, ok := packageTypeBySuffix(path)
+	if !ok || pt.name != "jar" {
+		return false, false
+	}
+	return slices.ContainsFunc(files, func(file FileInfo) bool {
+		return normalizeName(file.Name) == pt.manifest
+	}), true
+}
+
+// ManifestOptions are the options for reading the manifest of a package.
+type ManifestOptions struct {
+	Path string `json:"path" jsonschema:"the path to the package, e.g. a .jar, .whl or npm .tgz file"`
+}
+
+// ArchiveManifestArgs are the arguments for the archive_manifest tool.
+type ArchiveManifestArgs ManifestOptions
+
+// ArchiveManifestResult holds the result of the archive_manifest tool.
+type ArchiveManifestResult struct {
+	// Package is the detected package type: jar, wheel or npm.
+	Package string `json:"package"`
+	// Name is the name of the manifest entry.
+	Name    string `json:"name"`
+	Size    int64  `json:"size"`
+	Content string `json:"content"`
+}
+
+// Manifest returns the manifest of a jar, Python wheel or npm tarball, such
+// as META-INF/MANIFEST.MF, without the caller knowing its exact path. The
+// package type is detected by the suffix of the path or, for plain zip and
+// tar.gz archives, by the manifest found. If there is none, the error
+// names the paths looked for and any entries that look like a manifest.
+func (a *Archive) Manifest(ctx context.Context, opts ManifestOptions) (ArchiveManifestResult, error) {
+	var types []packageType
+	c, err := a.startCall(ctx, opts.Path, func(p string) (format, error) {
+		f, t, ok := packageTypesFor(p)
+		if !ok {
+			return format{}, fmt.Errorf("unknown package type for %s: expected a jar, wheel or npm package", opts.Path)
+		}
+		types = t
+		return f, nil
+	})
+	if err != nil {
+		return ArchiveManifestResult{}, err
+	}
+	defer c.done()
+
+	f := c.format
+	r, err := a.openArchive(c.ctx, c.path)
+	if err != nil {
+		return ArchiveManifestResult{}, err
+	}
+	defer r.Close()
+	if err := a.checkExtension(c.path, f, r, r.size); err != nil {
+		a.audit(ctx, opts.Path, err)
+		return ArchiveManifestResult{}, err
+	}
+
+	read := a.timedRead(a.readEntry)
+	var result ArchiveManifestResult
+	var similar []string
+	err = a.walkReader(c.ctx, f, r, r.size, memberName(c.path, f), func(e *entry) error {
+		if e.info.Type != typeFile {
+			return nil
+		}
+		name := normalizeName(e.info.Name)
+		for _, pt := range types {
+			if matched, _ := path.Match(pt.manifest, name); !matched {
+				if path.Base(name) == path.Base(pt.manifest) && !c.ignored(e.info) {
+					similar = append(similar, e.info.Name)
+				}
+				continue
+			}
+			if c.ignored(e.info) {
+				return &rejectedError{
+					reason: reasonIgnored,
+					entry:  e.info.Name,
+					err:    fmt.Errorf("file %s is hidden by server policy", e.info.Name),
+				}
+			}
+			file, err := read(e)
+			if err != nil {
+				return err
+			}
+			result = ArchiveManifestResult{
+				Package: pt.name,
+				Name:    file.Name,
+				Size:    file.Size,
+				Content: string(file.RawContent),
+			}
+			return errStopWalk
+		}
+		return nil
+	})
+	if err != nil {
+		return ArchiveManifestResult{}, a.callError(ctx, opts.Path, err)
+	}
+	if result.Package == "" {
+		var expected []string
+		for _, pt := range types {
+			expected = append(expected, pt.manifest)
+		}
+		msg := fmt.Sprintf("no manifest found in %s: expected %s", opts.Path, strings.Join(expected, " or "))
+		if len(similar) > 0 {
+			msg += ", candidates are " + strings.Join(similar, ", ")
+		}
+		return ArchiveManifestResult{}, errors.New(msg)
+	}
+	return result, nil
+}
+
+// ArchiveManifest returns the manifest of a package.
+func (a *Archive) ArchiveManifest(ctx context.Context, req *mcp.CallToolRequest, args ArchiveManifestArgs) (*mcp.CallToolResult, ArchiveManifestResult, error) {
+	return callTool(ctx, a, req, "archive_manifest", args.Path, ManifestOptions(args), a.Manifest)
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestZip writes a zip archive of files, in the given order of names
+// and contents, to dir.
+func writeTestZip(t *testing.T, dir, name string, files ...string) string {
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create zip: %v", err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for i := 0; i+1 < len(files); i += 2 {
+		w, err := zw.Create(files[i])
+		if err != nil {
+			t.Fatalf("failed to add zip entry: %v", err)
+		}
+		w.Write([]byte(files[i+1]))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to write zip: %v", err)
+	}
+	return path
+}
+
+func TestManifest(t *testing.T) {
+	dir := t.TempDir()
+	a, err := New(dir)
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	npm := writeSyntheticTar(t, dir, map[string]string{
+		"package/index.js":     "module.exports = 1\n",
+		"package/package.json": `{"name": "demo"}`,
+	})
+	if err := os.Rename(npm, filepath.Join(dir, "demo-1.0.0.tgz")); err != nil {
+		t.Fatalf("failed to rename tarball: %v", err)
+	}
+	writeTestZip(t, dir, "demo.jar", "demo/Main.class", "", "META-INF/MANIFEST.MF", "Main-Class: demo.Main\n")
+	writeTestZip(t, dir, "demo-1.0-py3-none-any.whl", "demo/__init__.py", "", "demo-1.0.dist-info/METADATA", "Name: demo\n")
+	// A jar that is not named as one is found by its manifest.
+	writeTestZip(t, dir, "renamed.zip", "META-INF/MANIFEST.MF", "Manifest-Version: 1.0\n")
+
+	for _, tc := range []struct {
+		file, pkg, name, content string
+	}{
+		{"demo.jar", "jar", "META-INF/MANIFEST.MF", "Main-Class: demo.Main\n"},
+		{"demo-1.0-py3-none-any.whl", "wheel", "demo-1.0.dist-info/METADATA", "Name: demo\n"},
+		{"demo-1.0.0.tgz", "npm", "package/package.json", `{"name": "demo"}`},
+		{"renamed.zip", "jar", "META-INF/MANIFEST.MF", "Manifest-Version: 1.0\n"},
+	} {
+		result, err := a.Manifest(context.Background(), ManifestOptions{Path: filepath.Join(dir, tc.file)})
+		if err != nil {
+			t.Errorf("Manifest %s failed: %v", tc.file, err)
+			continue
+		}
+		if result.Package != tc.pkg || result.Name != tc.name || result.Content != tc.content || result.Size != int64(len(tc.content)) {
+			t.Errorf("unexpected manifest of %s: %+v", tc.file, result)
+		}
+	}
+}
+
+func TestManifest_NotFound(t *testing.T) {
+	dir := t.TempDir()
+	a, err := New(dir)
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := writeTestZip(t, dir, "broken.jar", "nested/META-INF/MANIFEST.MF", "Manifest-Version: 1.0\n")
+	_, err = a.Manifest(context.Background(), ManifestOptions{Path: path})
+	if err == nil || !strings.Contains(err.Error(), "expected META-INF/MANIFEST.MF") || !strings.Contains(err.Error(), "nested/META-INF/MANIFEST.MF") {
+		t.Errorf("expected an error naming the expected and candidate paths, got %v", err)
+	}
+
+	a = newTestArchive(t)
+	if _, err := a.Manifest(context.Background(), ManifestOptions{Path: filepath.Join(a.Workdir, "test.cpio")}); err == nil {
+		t.Error("expected an error for an archive that is no package")
+	}
+}
+
+func TestList_Jar(t *testing.T) {
+	a := newTestArchive(t)
+	ctx := context.Background()
+	result, err := a.List(ctx, ListOptions{Path: filepath.Join(a.Workdir, "test.jar")})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if !containsFile(result.Files, expectedFile{"foo/baar.txt", 27}) {
+		t.Errorf("foo/baar.txt not listed: %v", result.Files)
+	}
+	if result.HasManifest == nil || !*result.HasManifest {
+		t.Errorf("expected the jar to have a manifest, got %v", result.HasManifest)
+	}
+	manifest, err := a.Manifest(ctx, ManifestOptions{Path: filepath.Join(a.Workdir, "test.jar")})
+	if err != nil {
+		t.Fatalf("Manifest failed: %v", err)
+	}
+	if !strings.Contains(manifest.Content, "Main-Class: foo.Main") {
+		t.Errorf("unexpected manifest: %q", manifest.Content)
+	}
+
+	result, err = a.List(ctx, ListOptions{Path: filepath.Join(a.Workdir, "test.zip")})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if result.HasManifest != nil {
+		t.Errorf("expected no manifest flag for a zip, got %v", *result.HasManifest)
+	}
+
+	dir := t.TempDir()
+	a, err = New(dir)
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := writeTestZip(t, dir, "app.war", "WEB-INF/web.xml", "<web-app/>")
+	result, err = a.List(ctx, ListOptions{Path: path})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if result.HasManifest == nil || *result.HasManifest {
+		t.Errorf("expected the war to have no manifest, got %v", result.HasManifest)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultMaxMatchTotal is the default cap on the total content size returned
+// by ExtractMatching.
+const defaultMaxMatchTotal = 1 << 20
+
+// MatchOptions are the options for extracting the files of an archive whose
+// content matches a regular expression.
+type MatchOptions struct {
+	Path           string `json:"path" jsonschema:"the path to the archive"`
+	Pattern        string `json:"pattern" jsonschema:"a regular expression matched against the content of each file"`
+	IncludePattern string `json:"include,omitempty" jsonschema:"an optional regular expression, or glob with pattern_syntax glob, to restrict the scanned files by name"`
+	ExcludePattern string `json:"exclude,omitempty" jsonschema:"an optional regular expression, or glob with pattern_syntax glob, to exclude files by name"`
+	PatternSyntax  string `json:"pattern_syntax,omitempty" jsonschema:"the syntax of include and exclude: regexp or glob. The content pattern is always a regular expression. If not set, it will default to regexp"`
+	Binary         bool   `json:"binary,omitempty" jsonschema:"also scan files that are not valid UTF-8. They are skipped by default"`
+	MaxTotalSize   int64  `json:"max_total_size,omitempty" jsonschema:"the maximum total size in bytes of the returned content. If not set, it will default to 1 MiB"`
+}
+
+// ExtractMatchingArgs are the arguments for the extract_matching tool.
+type ExtractMatchingArgs MatchOptions
+
+// ExtractMatchingResult holds the result of the extract_matching tool.
+type ExtractMatchingResult struct {
+	Files []File `json:"files"`
+	// Scanned is the number of files whose content was tested.
+	Scanned int `json:"scanned"`
+	// Truncated is set if scanning stopped because the next matching
+	// file would have exceeded the maximum total size.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// ExtractMatching returns the content of all regular files of an archive
+// whose content matches the pattern. Files larger than the maximum file
+// size are skipped, as are files that are not valid UTF-8 unless Binary is
+// set.
+func (a *Archive) ExtractMatching(ctx context.Context, opts MatchOptions) (ExtractMatchingResult, error) {
+	if opts.Pattern == "" {
+		return ExtractMatchingResult{}, errors.New("pattern is required")
+	}
+	re, err := regexp.Compile(opts.Pattern)
+	if err != nil {
+		return ExtractMatchingResult{}, fmt.Errorf("invalid pattern: %w", err)
+	}
+	filter, err := newFileFilter(ListOptions{IncludePattern: opts.IncludePattern, ExcludePattern: opts.ExcludePattern, PatternSyntax: opts.PatternSyntax, TypeFilter: typeFile})
+	if err != nil {
+		return ExtractMatchingResult{}, err
+	}
+	if opts.MaxTotalSize < 0 {
+		return ExtractMatchingResult{}, fmt.Errorf("invalid maximum total size %d", opts.MaxTotalSize)
+	}
+	maxTotal := opts.MaxTotalSize
+	if maxTotal == 0 {
+		maxTotal = defaultMaxMatchTotal
+	}
+
+	read := a.timedRead(a.readEntry)
+	result := ExtractMatchingResult{Files: []File{}}
+	var total int64
+	err = a.walkArchive(ctx, opts.Path, func(call *archiveCall, e *entry) error {
+		if !filter.match(e.info) || call.ignored(e.info) {
+			return nil
+		}
+		if !e.sizeUnknown && e.info.Size > a.maxSize {
+			return nil
+		}
+		file, err := read(e)
+		var rerr *rejectedError
+		if errors.As(err, &rerr) && rerr.reason == reasonTooLarge {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !opts.Binary && !utf8.Valid(file.RawContent) {
+			return nil
+		}
+		result.Scanned++
+		if !re.Match(file.RawContent) {
+			return nil
+		}
+		if total+file.Size > maxTotal {
+			result.Truncated = true
+			return errStopWalk
+		}
+		total += file.Size
+		result.Files = append(result.Files, file)
+		return nil
+	})
+	if err != nil {
+		return ExtractMatchingResult{}, err
+	}
+	a.metrics.extracted(result.Files)
+	for i := range result.Files {
+		result.Files[i].Content = string(result.Files[i].RawContent)
+		result.Files[i].RawContent = nil
+	}
+	return result, nil
+}
+
+// ExtractMatchingFiles extracts the files of an archive whose content
+// matches a regular expression.
+func (a *Archive) ExtractMatchingFiles(ctx context.Context, req *mcp.CallToolRequest, args ExtractMatchingArgs) (*mcp.CallToolResult, ExtractMatchingResult, error) {
+	return callTool(ctx, a, req, "extract_matching", args.Path, MatchOptions(args), a.ExtractMatching)
+}
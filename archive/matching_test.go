@@ -0,0 +1,123 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractMatching(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "test.tar.gz")
+
+	for _, tc := range []struct {
+		name      string
+		opts      MatchOptions
+		want      []string
+		truncated bool
+	}{
+		{name: "content", opts: MatchOptions{Pattern: "Gurken"}, want: []string{"foo/baar.txt"}},
+		{name: "all", opts: MatchOptions{Pattern: "a"}, want: []string{"foo/baar.txt", "foo/bazz"}},
+		{name: "none", opts: MatchOptions{Pattern: "Kuh"}},
+		{name: "include", opts: MatchOptions{Pattern: "a", IncludePattern: "bazz$"}, want: []string{"foo/bazz"}},
+		{name: "exclude", opts: MatchOptions{Pattern: "a", ExcludePattern: "bazz$"}, want: []string{"foo/baar.txt"}},
+		{name: "total size", opts: MatchOptions{Pattern: "a", MaxTotalSize: 30}, want: []string{"foo/baar.txt"}, truncated: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.Path = path
+			result, err := a.ExtractMatching(context.Background(), tc.opts)
+			if err != nil {
+				t.Fatalf("ExtractMatching failed: %v", err)
+			}
+			var names []string
+			for _, file := range result.Files {
+				names = append(names, file.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("expected %v, got %v", tc.want, names)
+			}
+			if result.Truncated != tc.truncated {
+				t.Errorf("expected truncated %v, got %v", tc.truncated, result.Truncated)
+			}
+		})
+	}
+
+	result, err := a.ExtractMatching(context.Background(), MatchOptions{Path: path, Pattern: "Gurken"})
+	if err != nil {
+		t.Fatalf("ExtractMatching failed: %v", err)
+	}
+	if result.Files[0].Content != "das Pferd isst Gurkensalat\n" || result.Scanned != 2 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestExtractMatching_Binary(t *testing.T) {
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := writeSyntheticTar(t, a.Workdir, map[string]string{
+		"bin":  "key\xff\xfe",
+		"text": "key",
+	})
+
+	for _, tc := range []struct {
+		binary bool
+		want   int
+	}{
+		{false, 1},
+		{true, 2},
+	} {
+		result, err := a.ExtractMatching(context.Background(), MatchOptions{Path: path, Pattern: "key", Binary: tc.binary})
+		if err != nil {
+			t.Fatalf("ExtractMatching failed: %v", err)
+		}
+		if len(result.Files) != tc.want {
+			t.Errorf("binary %v: expected %d files, got %v", tc.binary, tc.want, result.Files)
+		}
+	}
+}
+
+func TestExtractMatching_TooLarge(t *testing.T) {
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	a.maxSize = 4
+	path := writeSyntheticTar(t, a.Workdir, map[string]string{
+		"large": "key and more",
+		"small": "key",
+	})
+	result, err := a.ExtractMatching(context.Background(), MatchOptions{Path: path, Pattern: "key"})
+	if err != nil {
+		t.Fatalf("ExtractMatching failed: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].Name != "small" {
+		t.Errorf("expected only the small file, got %v", result.Files)
+	}
+}
+
+func TestExtractMatching_InvalidOptions(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "test.tar.gz")
+	for _, tc := range []struct {
+		opts MatchOptions
+		err  string
+	}{
+		{MatchOptions{Path: path}, "pattern is required"},
+		{MatchOptions{Path: path, Pattern: "("}, "invalid pattern"},
+		{MatchOptions{Path: path, Pattern: "a", IncludePattern: "("}, "invalid include pattern"},
+		{MatchOptions{Path: path, Pattern: "a", MaxTotalSize: -1}, "invalid maximum total size"},
+		{MatchOptions{Path: "relative.tar.gz", Pattern: "a"}, "not an absolute path"},
+	} {
+		_, err := a.ExtractMatching(context.Background(), tc.opts)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("expected error %q, got: %v", tc.err, err)
+		}
+	}
+}
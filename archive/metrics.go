@@ -0,0 +1,180 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// decompressBuckets are the upper bounds, in seconds, of the buckets of the
+// decompression time histogram.
+var decompressBuckets = []float64{0.001, 0.01, 0.1, 0.5, 1, 5, 10, 30}
+
+// histogram counts observations per bucket like a Prometheus histogram.
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+func (h *histogram) observe(v float64) {
+	if h.counts == nil {
+		h.counts = make([]uint64, len(decompressBuckets))
+	}
+	for i, le := range decompressBuckets {
+		if v <= le {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+// toolFormat labels the tool call counter.
+type toolFormat struct {
+	tool, format string
+}
+
+// Metrics collects the tool calls, the bytes extracted, the time spent
+// decompressing archives and the security rejections of an Archive, see
+// WithMetrics. Its Handler exposes them in the Prometheus text format. All
+// methods may be called on a nil *Metrics, which records nothing.
+type Metrics struct {
+	mu             sync.Mutex
+	calls          map[toolFormat]uint64
+	rejections     map[string]uint64
+	extractedBytes uint64
+	decompress     map[string]*histogram
+}
+
+// NewMetrics returns an empty metrics registry.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		calls:      make(map[toolFormat]uint64),
+		rejections: make(map[string]uint64),
+		decompress: make(map[string]*histogram),
+	}
+}
+
+// toolCall counts a call of tool on the archive or glob pattern at path.
+func (m *Metrics) toolCall(tool, path string) {
+	if m == nil {
+		return
+	}
+	format := "unknown"
+	if f, ok := formatFor(path); ok {
+		format = f.name
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls[toolFormat{tool, format}]++
+}
+
+// rejected counts a security rejection.
+func (m *Metrics) rejected(reason string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rejections[reason]++
+}
+
+// extracted counts the content bytes of extracted files.
+func (m *Metrics) extracted(files []File) {
+	if m == nil {
+		return
+	}
+	var n int64
+	for _, file := range files {
+		n += int64(len(file.RawContent))
+	}
+	m.addExtracted(n)
+}
+
+// addExtracted counts n extracted content bytes.
+func (m *Metrics) addExtracted(n int64) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.extractedBytes += uint64(n)
+}
+
+// decompressed records the time spent walking an archive of format.
+func (m *Metrics) decompressed(format string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h := m.decompress[format]
+	if h == nil {
+		h = &histogram{}
+		m.decompress[format] = h
+	}
+	h.observe(d.Seconds())
+}
+
+// Handler returns an HTTP handler serving the metrics in the Prometheus
+// text exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		m.write(w)
+	})
+}
+
+// labelEscaper escapes label values as required by the text format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func (m *Metrics) write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP mcp_archive_tool_calls_total Tool calls by tool and archive format.")
+	fmt.Fprintln(w, "# TYPE mcp_archive_tool_calls_total counter")
+	calls := slices.SortedFunc(maps.Keys(m.calls), func(a, b toolFormat) int {
+		return strings.Compare(a.tool+"\x00"+a.format, b.tool+"\x00"+b.format)
+	})
+	for _, k := range calls {
+		fmt.Fprintf(w, "mcp_archive_tool_calls_total{tool=\"%s\",format=\"%s\"} %d\n",
+			labelEscaper.Replace(k.tool), labelEscaper.Replace(k.format), m.calls[k])
+	}
+
+	fmt.Fprintln(w, "# HELP mcp_archive_extracted_bytes_total Content bytes of extracted files.")
+	fmt.Fprintln(w, "# TYPE mcp_archive_extracted_bytes_total counter")
+	fmt.Fprintf(w, "mcp_archive_extracted_bytes_total %d\n", m.extractedBytes)
+
+	fmt.Fprintln(w, "# HELP mcp_archive_decompress_seconds Time spent reading and decompressing archives by format.")
+	fmt.Fprintln(w, "# TYPE mcp_archive_decompress_seconds histogram")
+	for _, format := range slices.Sorted(maps.Keys(m.decompress)) {
+		h := m.decompress[format]
+		label := labelEscaper.Replace(format)
+		var cumulative uint64
+		for i, le := range decompressBuckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(w, "mcp_archive_decompress_seconds_bucket{format=\"%s\",le=\"%g\"} %d\n", label, le, cumulative)
+		}
+		fmt.Fprintf(w, "mcp_archive_decompress_seconds_bucket{format=\"%s\",le=\"+Inf\"} %d\n", label, h.count)
+		fmt.Fprintf(w, "mcp_archive_decompress_seconds_sum{format=\"%s\"} %g\n", label, h.sum)
+		fmt.Fprintf(w, "mcp_archive_decompress_seconds_count{format=\"%s\"} %d\n", label, h.count)
+	}
+
+	fmt.Fprintln(w, "# HELP mcp_archive_rejections_total Requests refused for security reasons by reason.")
+	fmt.Fprintln(w, "# TYPE mcp_archive_rejections_total counter")
+	for _, reason := range slices.Sorted(maps.Keys(m.rejections)) {
+		fmt.Fprintf(w, "mcp_archive_rejections_total{reason=\"%s\"} %d\n", labelEscaper.Replace(reason), m.rejections[reason])
+	}
+}
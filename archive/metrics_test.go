@@ -0,0 +1,80 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	m := NewMetrics()
+	a, err := New("../testdata", WithMetrics(m))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	path := filepath.Join(a.Workdir, "test.tar.gz")
+
+	m.toolCall("extract_archive_files", path)
+	if _, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/baar.txt"}}); err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if _, err := a.List(context.Background(), ListOptions{Path: "relative.tar.gz"}); err == nil {
+		t.Fatal("expected rejection of relative path")
+	}
+	m.toolCall("list_archive_files", "/tmp/archive.rar")
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		`mcp_archive_tool_calls_total{tool="extract_archive_files",format="tar.gz"} 1`,
+		`mcp_archive_tool_calls_total{tool="list_archive_files",format="unknown"} 1`,
+		`mcp_archive_extracted_bytes_total 27`,
+		`mcp_archive_decompress_seconds_count{format="tar.gz"} 1`,
+		`mcp_archive_decompress_seconds_bucket{format="tar.gz",le="+Inf"} 1`,
+		`mcp_archive_rejections_total{reason="not absolute"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics lack %q:\n%s", want, body)
+		}
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	m := NewMetrics()
+	m.decompressed("zip", 5*time.Millisecond)
+	m.decompressed("zip", time.Minute)
+
+	var b strings.Builder
+	m.write(&b)
+	for _, want := range []string{
+		`mcp_archive_decompress_seconds_bucket{format="zip",le="0.001"} 0`,
+		`mcp_archive_decompress_seconds_bucket{format="zip",le="0.01"} 1`,
+		`mcp_archive_decompress_seconds_bucket{format="zip",le="30"} 1`,
+		`mcp_archive_decompress_seconds_bucket{format="zip",le="+Inf"} 2`,
+		`mcp_archive_decompress_seconds_sum{format="zip"} 60.005`,
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("histogram lacks %q:\n%s", want, b.String())
+		}
+	}
+}
+
+func TestMetrics_Nil(t *testing.T) {
+	var m *Metrics
+	m.toolCall("list_archive_files", "/a.zip")
+	m.rejected(reasonDenied)
+	m.extracted([]File{{RawContent: []byte("x")}})
+	m.decompressed("zip", time.Second)
+}
@@ -0,0 +1,22 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !unix
+
+package archive
+
+import (
+	"errors"
+	"os"
+)
+
+// mmapFile always fails, as memory mapping is only implemented for Unix.
+func mmapFile(f *os.File, size int64) ([]byte, error) {
+	return nil, errors.New("memory mapping is not supported on this platform")
+}
+
+// munmap releases a mapping returned by mmapFile.
+func munmap(b []byte) error {
+	return nil
+}
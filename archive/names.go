@@ -0,0 +1,45 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"archive/zip"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// zipFlagUTF8 is the general purpose flag bit of a zip entry marking its
+// name as UTF-8. Without it, names are CP437 as per the zip specification.
+const zipFlagUTF8 = 0x800
+
+// decodeName returns the name of e in UTF-8. Names that are valid UTF-8 are
+// kept as they are, others are transcoded from the configured filename
+// encoding. Zip entries without the UTF-8 flag default to CP437, while
+// names of zip entries with the flag are never transcoded. If a name cannot
+// be transcoded, it is kept as well.
+func (a *Archive) decodeName(e *entry) string {
+	name := e.info.Name
+	if utf8.ValidString(name) {
+		return name
+	}
+	enc := a.filenameEncoding
+	if f, ok := e.sys.(*zip.File); ok {
+		if f.Flags&zipFlagUTF8 != 0 {
+			return name
+		}
+		if enc == nil {
+			enc = charmap.CodePage437
+		}
+	}
+	if enc == nil {
+		return name
+	}
+	decoded, err := enc.NewDecoder().String(name)
+	if err != nil {
+		return name
+	}
+	return decoded
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDecodeName_ZipCP437(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, h := range []*zip.FileHeader{
+		// Grüße.txt in CP437, written without the UTF-8 flag.
+		{Name: "foo/Gr\x81\xe1e.txt", NonUTF8: true},
+		// Flagged UTF-8 names are kept.
+		{Name: "foo/Köln.txt"},
+	} {
+		w, err := zw.CreateHeader(h)
+		if err != nil {
+			t.Fatalf("failed to create zip entry: %v", err)
+		}
+		w.Write([]byte("content\n"))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to write zip: %v", err)
+	}
+
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := filepath.Join(a.Workdir, "cp437.zip")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+
+	result, err := a.List(context.Background(), ListOptions{Path: path})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	var names []string
+	for _, file := range result.Files {
+		names = append(names, file.Name)
+	}
+	if got := strings.Join(names, ","); got != "foo/Grüße.txt,foo/Köln.txt" {
+		t.Errorf("unexpected names: %s", got)
+	}
+
+	extracted, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/Grüße.txt"}})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(extracted.Files) != 1 || extracted.Files[0].Content != "content\n" {
+		t.Errorf("unexpected extracted files: %v", extracted.Files)
+	}
+}
+
+func TestDecodeName_TarShiftJIS(t *testing.T) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	// 日本.txt in Shift_JIS.
+	name := "foo/\x93\xfa\x96\x7b.txt"
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: 3, Format: tar.FormatGNU}); err != nil {
+		t.Fatalf("failed to write tar header: %v", err)
+	}
+	tw.Write([]byte("ja\n"))
+	tw.Close()
+	gw.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sjis.tar.gz")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+
+	for _, tc := range []struct {
+		encoding string
+		want     string
+	}{
+		{"", name},
+		{"Shift_JIS", "foo/日本.txt"},
+	} {
+		a, err := New(dir, WithFilenameEncoding(tc.encoding))
+		if err != nil {
+			t.Fatalf("failed to create archive: %v", err)
+		}
+		result, err := a.List(context.Background(), ListOptions{Path: path})
+		if err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		if len(result.Files) != 1 || result.Files[0].Name != tc.want {
+			t.Errorf("encoding %q: expected %q, got %v", tc.encoding, tc.want, result.Files)
+		}
+	}
+}
+
+func TestNew_InvalidFilenameEncoding(t *testing.T) {
+	_, err := New("../testdata", WithFilenameEncoding("no-such-charset"))
+	if err == nil || !strings.Contains(err.Error(), "invalid filename encoding") {
+		t.Errorf("expected invalid filename encoding error, got: %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// nestedSeparator separates the archive path from the names of the nested
+// archives inside it, as in outer.zip!inner.tar.gz.
+const nestedSeparator = "!"
+
+// maxNestingDepth is the maximum number of nested archives in a path.
+const maxNestingDepth = 3
+
+// defaultMaxNestedSize is the default cap on the total bytes of the nested
+// archives copied to scratch files for a single path.
+const defaultMaxNestedSize = 64 << 20
+
+// splitNested splits path into the path of the archive file and the names
+// of the archives nested in it, outermost first. A path naming an existing
+// file is never split, even if it contains the separator.
+func splitNested(path string) (string, []string, error) {
+	if !strings.Contains(path, nestedSeparator) {
+		return path, nil, nil
+	}
+	if _, err := os.Lstat(path); err == nil {
+		return path, nil, nil
+	}
+	parts := strings.Split(path, nestedSeparator)
+	if slices.Contains(parts, "") {
+		return "", nil, fmt.Errorf("invalid nested archive path %s: empty name", path)
+	}
+	return parts[0], parts[1:], nil
+}
+
+// nestedWalker returns a walker for the archive reached by opening each of
+// the nested archives in turn, starting with the archive at path. Nested
+// archives are copied to scratch files, at most maxNestedSize bytes for all
+// levels together, and their format is detected by their names. Without
+// nested archives, the archive at path itself is walked. The returned
+// cleanup removes the scratch files once the walker is no longer used.
+func (a *Archive) nestedWalker(ctx context.Context, path string, nested []string, ignored func(FileInfo) bool) (walker, func(), error) {
+	walk := a.pathWalker(ctx, path)
+	if len(nested) > maxNestingDepth {
+		return nil, nil, fmt.Errorf("archives nested %d levels deep, more than the maximum of %d", len(nested), maxNestingDepth)
+	}
+	var cleanups []func()
+	cleanup := func() {
+		for _, c := range cleanups {
+			c()
+		}
+	}
+	remaining := a.maxNestedSize
+	for _, name := range nested {
+		f, ok := formatFor(name)
+		if !ok {
+			cleanup()
+			return nil, nil, fmt.Errorf("unsupported archive format for nested archive %s", name)
+		}
+		tmp, remove, err := a.createTemp("nested-*")
+		if err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+		cleanups = append(cleanups, remove)
+		size, err := readNested(walk, name, ignored, remaining, tmp)
+		if err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+		remaining -= size
+		walk = func(fn walkFunc) error {
+			return a.walkReader(ctx, f, tmp, size, memberName(name, f), fn)
+		}
+	}
+	return walk, cleanup, nil
+}
+
+// readNested copies the content of the nested archive name, the first entry
+// of that name visited by walk, to w and returns its size, refusing
+// archives larger than maxSize.
+func readNested(walk walker, name string, ignored func(FileInfo) bool, maxSize int64, w io.Writer) (int64, error) {
+	var size int64
+	found := false
+	err := walk(func(e *entry) error {
+		if normalizeName(e.info.Name) != normalizeName(name) || e.info.Type != typeFile {
+			return nil
+		}
+		if ignored(e.info) {
+			return &rejectedError{
+				reason: reasonIgnored,
+				entry:  e.info.Name,
+				err:    fmt.Errorf("file %s is hidden by server policy", e.info.Name),
+			}
+		}
+		found = true
+		tooLarge := &rejectedError{
+			reason: reasonTooLarge,
+			entry:  e.info.Name,
+			err:    fmt.Errorf("nested archive %s is too large: more than %d bytes in total", e.info.Name, maxSize),
+		}
+		if err := checkEntrySize(e); err != nil {
+			return err
+		}
+		if !e.sizeUnknown && e.info.Size > maxSize {
+			return tooLarge
+		}
+		rc, err := e.open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		if size, err = io.Copy(w, io.LimitReader(rc, maxSize+1)); err != nil {
+			return fmt.Errorf("could not read nested archive %s: %w", e.info.Name, err)
+		}
+		if size > maxSize {
+			return tooLarge
+		}
+		return errStopWalk
+	})
+	if err != nil && !errors.Is(err, errStopWalk) {
+		return 0, err
+	}
+	if !found {
+		return 0, fmt.Errorf("nested archive %s not found", name)
+	}
+	return size, nil
+}
+
+// nestedFile makes the last of the nested names the file to extract if it
+// is not an archive and opts selects no files otherwise, as in
+// outer.zip!inner.tar.gz!foo/baar.txt. It returns the remaining nested
+// archives.
+func nestedFile(opts *ExtractOptions, nested []string) []string {
+	if len(nested) == 0 || len(opts.Files) > 0 || opts.Index != nil {
+		return nested
+	}
+	last := nested[len(nested)-1]
+	if _, ok := formatFor(last); ok {
+		return nested
+	}
+	opts.Files = []string{last}
+	return nested[:len(nested)-1]
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestList_Nested(t *testing.T) {
+	a := newTestArchive(t)
+	result, err := a.List(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, "outer.zip!test.tar.gz")})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	for _, expected := range []expectedFile{{"foo/", 0}, {"foo/baar.txt", 27}, {"foo/bazz", 5}} {
+		if !containsFile(result.Files, expected) {
+			t.Errorf("expected file %s with size %d not found in %v", expected.name, expected.size, result.Files)
+		}
+	}
+}
+
+func TestExtract_Nested(t *testing.T) {
+	a := newTestArchive(t)
+	for _, opts := range []ExtractOptions{
+		{Path: filepath.Join(a.Workdir, "outer.zip!test.tar.gz!foo/baar.txt")},
+		{Path: filepath.Join(a.Workdir, "outer.zip!test.tar.gz"), Files: []string{"foo/baar.txt"}},
+	} {
+		result, err := a.Extract(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("Extract %s failed: %v", opts.Path, err)
+		}
+		if len(result.Files) != 1 || result.Files[0].Content != "das Pferd isst Gurkensalat\n" {
+			t.Errorf("unexpected files extracted from %s: %v", opts.Path, result.Files)
+		}
+	}
+}
+
+func TestNested_TwoLevels(t *testing.T) {
+	dir := t.TempDir()
+	a, err := New(dir)
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	inner, err := os.ReadFile("../testdata/outer.zip")
+	if err != nil {
+		t.Fatalf("failed to read outer.zip: %v", err)
+	}
+	f, err := os.Create(filepath.Join(a.Workdir, "double.zip"))
+	if err != nil {
+		t.Fatalf("failed to create zip: %v", err)
+	}
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("outer.zip")
+	if err != nil {
+		t.Fatalf("failed to add zip entry: %v", err)
+	}
+	w.Write(inner)
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to write zip: %v", err)
+	}
+	f.Close()
+
+	result, err := a.Extract(context.Background(), ExtractOptions{Path: filepath.Join(a.Workdir, "double.zip!outer.zip!test.tar.gz!foo/bazz")})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].Content != "bazz\n" {
+		t.Errorf("unexpected files extracted: %v", result.Files)
+	}
+}
+
+func TestNested_Errors(t *testing.T) {
+	a := newTestArchive(t)
+	for path, expected := range map[string]string{
+		"outer.zip!missing.tar.gz":                "not found",
+		"outer.zip!test.tar.gz!a.zip!b.zip!c.zip": "nested 4 levels deep",
+		"outer.zip!!test.tar.gz":                  "empty name",
+		"outer.zip!test.txt":                      "unsupported archive format",
+	} {
+		_, err := a.List(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, path)})
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("List %s: expected error containing %q, got %v", path, expected, err)
+		}
+	}
+
+	small, err := New("../testdata", WithMaxNestedSize(100))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	_, err = small.List(context.Background(), ListOptions{Path: filepath.Join(small.Workdir, "outer.zip!test.tar.gz")})
+	if err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Errorf("expected error for a nested archive over the size cap, got %v", err)
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import "time"
+
+// Option configures an Archive created by New.
+type Option func(*Archive)
+
+// WithDeny rejects any path below one of the given path prefixes, even if it
+// lies inside a working directory root.
+func WithDeny(paths ...string) Option {
+	return func(a *Archive) {
+		a.deny = append(a.deny, paths...)
+	}
+}
+
+// WithTimeout limits the duration of a single list or extract call. A zero
+// duration disables the limit, which is the default.
+func WithTimeout(d time.Duration) Option {
+	return func(a *Archive) {
+		a.timeout = d
+	}
+}
+
+// WithEntryTimeout limits the time spent reading the content of a single
+// entry, protecting against entries that are pathologically slow to
+// decompress. Reading such an entry fails with an "entry decompression timed
+// out" error. The timeout is checked between reads: a single read blocked
+// in a decompressor is not interrupted, and the entry fails once it
+// returns. A zero duration disables the limit, which is the default.
+func WithEntryTimeout(d time.Duration) Option {
+	return func(a *Archive) {
+		a.entryTimeout = d
+	}
+}
+
+// WithWritable enables write-capable tools. Archives are read-only by
+// default.
+func WithWritable(writable bool) Option {
+	return func(a *Archive) {
+		a.writable = writable
+	}
+}
+
+// WithDecoderLimits bounds the memory, in bytes, that the xz and zstd
+// decompressors may allocate for their dictionary or window, and the number
+// of goroutines the zstd decompressor may use. A zero maxMemory disables the
+// memory limit. The defaults are 128 MiB and a single goroutine.
+func WithDecoderLimits(maxMemory uint64, concurrency int) Option {
+	return func(a *Archive) {
+		a.decoderLimits = decoderLimits{
+			maxMemory:   maxMemory,
+			concurrency: max(concurrency, 1),
+		}
+	}
+}
+
+// WithEntryLimits caps the number of entries scanned per archive and the
+// length of entry names in bytes. Listing or extracting an archive exceeding
+// either cap fails. A zero value disables the respective cap. The defaults
+// are 1,000,000 entries and 4096 bytes.
+func WithEntryLimits(maxEntries, maxNameLength int) Option {
+	return func(a *Archive) {
+		a.maxEntries = maxEntries
+		a.maxNameLength = maxNameLength
+	}
+}
+
+// WithMaxPathDepth caps the number of path segments of entry names, which
+// bounds the directory structures built from them. Unlike the depth of a
+// listing, it applies to every scan: listing or extracting an archive with a
+// deeper entry fails. Zero disables the cap. The default is 256 segments.
+func WithMaxPathDepth(n int) Option {
+	return func(a *Archive) {
+		a.maxPathDepth = n
+	}
+}
+
+// WithMaxConcurrent limits the number of archive operations running at the
+// same time across all sessions. Further calls wait for a free slot until
+// their context is done. Zero, the default, disables the limit.
+func WithMaxConcurrent(n int) Option {
+	return func(a *Archive) {
+		a.maxConcurrent = n
+	}
+}
+
+// WithAutoUnwrap removes extra compression layers, detected by their magic
+// bytes, from compressed tar and cpio archives, as found in accidentally
+// double compressed files such as archive.tar.gz.gz. At most three layers
+// are removed to refuse chains of decompression bombs. It is disabled by
+// default.
+func WithAutoUnwrap(enabled bool) Option {
+	return func(a *Archive) {
+		a.autoUnwrap = enabled
+	}
+}
+
+// WithSymlinkPolicy sets how symlink entries inside archives are handled:
+// SymlinksReport, the default, lists them with their target, SymlinksSkip
+// leaves them out of listings and extractions, and SymlinksReject refuses
+// archives containing any.
+func WithSymlinkPolicy(policy string) Option {
+	return func(a *Archive) {
+		a.symlinkPolicy = policy
+	}
+}
+
+// WithPermissionFormat sets the format of the permissions reported for
+// archive entries, PermissionsSymbolic, the default, or PermissionsOctal.
+func WithPermissionFormat(format string) Option {
+	return func(a *Archive) {
+		a.permissionFormat = format
+	}
+}
+
+// WithMetrics records tool calls, extracted bytes, decompression times and
+// security rejections in m. Metrics are not collected by default.
+func WithMetrics(m *Metrics) Option {
+	return func(a *Archive) {
+		a.metrics = m
+	}
+}
+
+// WithFilenameEncoding sets the IANA name of the character set, such as
+// IBM437 or Shift_JIS, of entry names that are not valid UTF-8. Such names
+// are transcoded to UTF-8 for listing and for matching on extraction. Names
+// of zip entries lacking the UTF-8 flag are read as CP437 by default.
+func WithFilenameEncoding(charset string) Option {
+	return func(a *Archive) {
+		a.filenameCharset = charset
+	}
+}
+
+// WithMaxResponseBytes caps the estimated size in bytes of an extraction
+// result. Larger results fail with a ResponseTooLargeError telling how many
+// files fit, rather than exceeding the limits of the MCP transport. Zero
+// disables the cap. The default is 8 MiB.
+func WithMaxResponseBytes(n int64) Option {
+	return func(a *Archive) {
+		a.maxResponseBytes = n
+	}
+}
+
+// WithMaxNestedSize caps the total size in bytes of the nested archives
+// copied to scratch files for a path like outer.zip!inner.tar.gz. The
+// default is 64 MiB.
+func WithMaxNestedSize(n int64) Option {
+	return func(a *Archive) {
+		a.maxNestedSize = n
+	}
+}
+
+// WithFormats enables only the archive formats with the given canonical
+// names, such as "zip" or "tar.gz". Archives of other formats fail with
+// ErrFormatDisabled. By default all supported formats are enabled.
+func WithFormats(names ...string) Option {
+	return func(a *Archive) {
+		a.formatNames = append(a.formatNames, names...)
+	}
+}
+
+// WithTempDir sets the directory for scratch files, such as the copies of
+// nested archives. New fails unless it is a writable directory. By default
+// the system temp directory is used.
+func WithTempDir(dir string) Option {
+	return func(a *Archive) {
+		a.tempDir = dir
+	}
+}
+
+// WithMmap maps zip archives of at least 1 MiB into memory instead of
+// reading them with a system call per access, which speeds up random
+// access to the entries of large zips. Archives that cannot be mapped are
+// read as usual. An archive truncated while it is mapped crashes the
+// process, so only enable it for archives that are not modified in place.
+func WithMmap(enabled bool) Option {
+	return func(a *Archive) {
+		a.mmap = enabled
+	}
+}
+
+// WithParallelThreshold sets the number of entries from which the central
+// directory of zip archives is decoded and listings are filtered by a
+// goroutine per CPU instead of sequentially. Below it, as for the common
+// small archive, the goroutine setup costs more than it saves. Zero always
+// runs in parallel. The default is 10,000 entries.
+func WithParallelThreshold(n int) Option {
+	return func(a *Archive) {
+		a.parallelThreshold = n
+	}
+}
+
+// WithReadBufferSize buffers the reads of compressed and plain tar and cpio
+// streams with n bytes, both from the archive file and from the
+// decompressor output, which speeds up sequential reads of large archives.
+// Zip archives are read at random offsets and are not affected. A size of
+// 0, the default, leaves the reads unbuffered.
+func WithReadBufferSize(n int) Option {
+	return func(a *Archive) {
+		a.readBufferSize = n
+	}
+}
+
+// WithTolerant skips junk bytes, such as a byte order mark or the remains of
+// an HTTP transfer, found before the magic of an archive within its first
+// KiB. By default such archives fail to open.
+func WithTolerant(enabled bool) Option {
+	return func(a *Archive) {
+		a.tolerant = enabled
+	}
+}
+
+// WithStrictExtension refuses archives whose content does not start with
+// the magic of the format claimed by their extension, such as an
+// executable named .zip, before decoding them. It is off by default.
+func WithStrictExtension(enabled bool) Option {
+	return func(a *Archive) {
+		a.strictExtension = enabled
+	}
+}
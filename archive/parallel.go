@@ -0,0 +1,48 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"runtime"
+	"sync"
+)
+
+// defaultParallelThreshold is the default number of items below which
+// forEachChunk runs sequentially, see WithParallelThreshold. With four
+// CPUs, listing a 3-entry zip sequentially takes about a fifth less time
+// and 12 fewer allocations than with a goroutine per CPU, see
+// BenchmarkList_SmallArchive. Running in parallel only pays off for
+// thousands of entries, see BenchmarkZipList.
+const defaultParallelThreshold = 10000
+
+// forEachChunk calls fn for consecutive index ranges [start, end) covering
+// n items. Inputs of at least threshold items are split across GOMAXPROCS
+// workers; fn must only write to per-index state so that the output order
+// is preserved. The first error returned by fn is returned.
+func forEachChunk(n, threshold int, fn func(start, end int) error) error {
+	workers := runtime.GOMAXPROCS(0)
+	if n < threshold || workers == 1 {
+		return fn(0, n)
+	}
+
+	chunk := (n + workers - 1) / workers
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for start := 0; start < n; start += chunk {
+		end := min(start+chunk, n)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(start, end); err != nil {
+				once.Do(func() { firstErr = err })
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeSyntheticZip creates a zip with n empty entries spread over a few
+// directories and returns its path.
+func writeSyntheticZip(t testing.TB, dir string, n int) string {
+	path := filepath.Join(dir, "synthetic.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create zip: %v", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("dir%d/file%07d.txt", i%16, i)
+		if i%3 == 0 {
+			name = fmt.Sprintf("dir%d/file%07d.log", i%16, i)
+		}
+		if _, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store}); err != nil {
+			t.Fatalf("failed to add zip entry: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to write zip: %v", err)
+	}
+	return path
+}
+
+func TestForEachChunk_Order(t *testing.T) {
+	out := make([]int, 1000)
+	err := forEachChunk(len(out), 10, func(start, end int) error {
+		for i := start; i < end; i++ {
+			out[i] = i * 2
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("forEachChunk failed: %v", err)
+	}
+	for i, v := range out {
+		if v != i*2 {
+			t.Fatalf("unexpected value %d at index %d", v, i)
+		}
+	}
+}
+
+func TestZipList_Parallel(t *testing.T) {
+	dir := t.TempDir()
+	a, err := New(dir, WithParallelThreshold(100))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := writeSyntheticZip(t, a.Workdir, 3000)
+
+	result, err := a.List(context.Background(), ListOptions{
+		Path:           path,
+		Limit:          3000,
+		IncludePattern: `\.txt$`,
+	})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if result.TotalFiles != 3000 {
+		t.Errorf("expected 3000 files, got %d", result.TotalFiles)
+	}
+	if result.FilteredFiles != 2000 {
+		t.Errorf("expected 2000 filtered files, got %d", result.FilteredFiles)
+	}
+	for i := 1; i < len(result.Files); i++ {
+		if result.Files[i-1].DataOffset >= result.Files[i].DataOffset {
+			t.Fatalf("files out of archive order at index %d", i)
+		}
+	}
+}
+
+func BenchmarkZipList(b *testing.B) {
+	benchmarkListThreshold(b, 200000, defaultParallelThreshold)
+}
+
+// BenchmarkList_SmallArchive compares both paths on a 3-entry zip, which
+// the default threshold keeps sequential.
+func BenchmarkList_SmallArchive(b *testing.B) {
+	benchmarkListThreshold(b, 3, 0)
+}
+
+// benchmarkListThreshold lists and filters a zip of n entries sequentially
+// and with the given parallel threshold.
+func benchmarkListThreshold(b *testing.B, n, parallel int) {
+	dir := b.TempDir()
+	path := writeSyntheticZip(b, dir, n)
+	opts := ListOptions{Path: path, IncludePattern: `file0[0-9]+\.txt$`, ExcludePattern: `^dir1/`}
+
+	for _, bc := range []struct {
+		name      string
+		threshold int
+	}{
+		{"sequential", 1 << 30},
+		{"parallel", parallel},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			a, err := New(dir, WithParallelThreshold(bc.threshold))
+			if err != nil {
+				b.Fatalf("failed to create archive: %v", err)
+			}
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := a.List(context.Background(), opts); err != nil {
+					b.Fatalf("List failed: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// expandRoots splits workdir into its list elements and expands the glob
+// patterns among them into absolute directory paths.
+func expandRoots(workdir string) ([]string, error) {
+	var roots []string
+	for _, pattern := range filepath.SplitList(workdir) {
+		absPattern, err := filepath.Abs(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get absolute path for workdir: %w", err)
+		}
+		if !strings.ContainsAny(absPattern, "*?[") {
+			root, err := checkRoot(absPattern)
+			if err != nil {
+				return nil, err
+			}
+			roots = append(roots, root)
+			continue
+		}
+		matches, err := filepath.Glob(absPattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid workdir pattern %s: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("workdir pattern %s matches no directories", pattern)
+		}
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && info.IsDir() {
+				root, err := checkRoot(match)
+				if err != nil {
+					return nil, err
+				}
+				roots = append(roots, root)
+			}
+		}
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("no working directory in %q", workdir)
+	}
+	return roots, nil
+}
+
+// expandDeny returns the absolute form of the deny paths in a new slice.
+// For a deny path that exists, its form with symlinks resolved is added as
+// well, as confine also compares resolved paths, which would otherwise slip
+// past a deny path given through a symlink.
+func expandDeny(paths []string) ([]string, error) {
+	deny := make([]string, 0, len(paths))
+	for _, path := range paths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get absolute path for deny path: %w", err)
+		}
+		deny = append(deny, absPath)
+		if resolved, err := filepath.EvalSymlinks(absPath); err == nil && resolved != absPath {
+			deny = append(deny, resolved)
+		}
+	}
+	return deny, nil
+}
+
+// checkRoot verifies that the working directory root exists and is a
+// directory that can confine paths, and returns it with its symlinks
+// resolved, as securePath compares resolved paths against the roots. A root
+// that is a symlink to a directory elsewhere is logged, since it exposes
+// that directory. The filesystem root is refused, as it confines nothing.
+func checkRoot(root string) (string, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("working directory %s does not exist", root)
+		}
+		return "", fmt.Errorf("invalid working directory: %w", err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("working directory %s is not a directory", root)
+	}
+	resolved, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve working directory %s: %w", root, err)
+	}
+	if filepath.Dir(resolved) == resolved {
+		return "", fmt.Errorf("working directory %s resolves to the filesystem root %s, which cannot be confined", root, resolved)
+	}
+	if !within(root, resolved) {
+		slog.Warn("working directory is a symlink to a directory elsewhere", "workdir", root, "target", resolved)
+	}
+	return resolved, nil
+}
+
+// within reports whether path is root or lies below it.
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func withinAny(roots []string, path string) bool {
+	for _, root := range roots {
+		if within(root, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// securePath confines path, which must exist, to the working directory
+// roots after resolving all symlinks, and returns the resolved path. The
+// resolved path may lie in any root, so symlinks between roots are
+// followed.
+func (a *Archive) securePath(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		return "", &rejectedError{
+			reason: reasonNotAbsolute,
+			err:    fmt.Errorf("path is not an absolute path: %s", path),
+		}
+	}
+	absPath := filepath.Clean(path)
+	evalPath, err := filepath.EvalSymlinks(absPath)
+	if err != nil {
+		return "", &rejectedError{
+			reason: reasonUnresolvable,
+			err:    fmt.Errorf("failed to evaluate symlinks: %w", err),
+		}
+	}
+	if err := a.confine(path, absPath, evalPath); err != nil {
+		return "", err
+	}
+	return evalPath, nil
+}
+
+// secureArchivePath confines path like securePath and additionally
+// refuses an empty path and a directory with a message that tells the
+// caller what to pass instead.
+func (a *Archive) secureArchivePath(path string) (string, error) {
+	if path == "" {
+		return "", errors.New("path is required: pass the absolute path of an archive")
+	}
+	evalPath, err := a.securePath(path)
+	if err != nil {
+		return "", err
+	}
+	if fi, err := os.Stat(evalPath); err == nil && fi.IsDir() {
+		return "", fmt.Errorf("path is a directory, not an archive: %s", path)
+	}
+	return evalPath, nil
+}
+
+// secureWritePath confines path, the target of a write that need not exist
+// yet, to the working directory roots. Only its parent directory has to
+// exist and is resolved. An existing target is resolved as a whole, so that
+// a symlink in its place cannot redirect the write.
+func (a *Archive) secureWritePath(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		return "", &rejectedError{
+			reason: reasonNotAbsolute,
+			err:    fmt.Errorf("path is not an absolute path: %s", path),
+		}
+	}
+	absPath := filepath.Clean(path)
+	if _, err := os.Lstat(absPath); err == nil {
+		return a.securePath(path)
+	}
+	evalParent, err := filepath.EvalSymlinks(filepath.Dir(absPath))
+	if err != nil {
+		return "", &rejectedError{
+			reason: reasonUnresolvable,
+			err:    fmt.Errorf("failed to evaluate symlinks of parent directory: %w", err),
+		}
+	}
+	evalPath := filepath.Join(evalParent, filepath.Base(absPath))
+	if err := a.confine(path, absPath, evalPath); err != nil {
+		return "", err
+	}
+	return evalPath, nil
+}
+
+// confine checks that evalPath, the resolved form of the cleaned absPath,
+// lies within a working directory root and is neither denied nor ignored.
+// The original path is used in error messages.
+func (a *Archive) confine(path, absPath, evalPath string) error {
+	if !withinAny(a.roots, evalPath) {
+		reason := reasonTraversal
+		if withinAny(a.roots, absPath) {
+			reason = reasonSymlinkEscape
+		}
+		return &rejectedError{
+			reason: reason,
+			err:    fmt.Errorf("path %s is outside of the working directory", path),
+		}
+	}
+	if withinAny(a.deny, absPath) || withinAny(a.deny, evalPath) {
+		return &rejectedError{
+			reason: reasonDenied,
+			err:    fmt.Errorf("path %s is denied by server policy", path),
+		}
+	}
+	if rules, rel := a.ignoreRules(evalPath); rules.match(rel, false) {
+		return &rejectedError{
+			reason: reasonIgnored,
+			err:    fmt.Errorf("path %s is hidden by server policy", path),
+		}
+	}
+	return nil
+}
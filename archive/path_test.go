@@ -0,0 +1,298 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newUploadsTree creates uploads/pub and uploads/secret directories, each
+// containing a file named test.zip, and returns the resolved uploads path.
+func newUploadsTree(t *testing.T) string {
+	tmp, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to resolve temp dir: %v", err)
+	}
+	uploads := filepath.Join(tmp, "uploads")
+	for _, dir := range []string{"pub", "secret"} {
+		if err := os.MkdirAll(filepath.Join(uploads, dir), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(uploads, dir, "test.zip"), nil, 0o644); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(uploads, "top.zip"), nil, 0o644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	return uploads
+}
+
+func TestNew_GlobWorkdir(t *testing.T) {
+	uploads := newUploadsTree(t)
+	a, err := New(filepath.Join(uploads, "*"))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if len(a.roots) != 2 {
+		t.Fatalf("expected 2 roots, got %v", a.roots)
+	}
+	for _, dir := range []string{"pub", "secret"} {
+		if _, err := a.securePath(filepath.Join(uploads, dir, "test.zip")); err != nil {
+			t.Errorf("securePath failed for %s: %v", dir, err)
+		}
+	}
+	if _, err := a.securePath(filepath.Join(uploads, "top.zip")); err == nil {
+		t.Error("expected error for path outside of the expanded roots, but got nil")
+	}
+}
+
+func TestNew_MultipleWorkdirs(t *testing.T) {
+	uploads := newUploadsTree(t)
+	workdir := strings.Join([]string{
+		filepath.Join(uploads, "pub"),
+		filepath.Join(uploads, "secret"),
+	}, string(os.PathListSeparator))
+	a, err := New(workdir)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if a.Workdir != filepath.Join(uploads, "pub") {
+		t.Errorf("unexpected workdir: %s", a.Workdir)
+	}
+	if _, err := a.securePath(filepath.Join(uploads, "secret", "test.zip")); err != nil {
+		t.Errorf("securePath failed: %v", err)
+	}
+}
+
+func TestNew_GlobNoMatch(t *testing.T) {
+	uploads := newUploadsTree(t)
+	_, err := New(filepath.Join(uploads, "nothing-*"))
+	if err == nil {
+		t.Fatal("expected error for pattern without matches, but got nil")
+	}
+	if !strings.Contains(err.Error(), "matches no directories") {
+		t.Fatalf("expected no match error, got: %v", err)
+	}
+}
+
+func TestSecurePath_DenyPrecedence(t *testing.T) {
+	uploads := newUploadsTree(t)
+	a, err := New(filepath.Join(uploads, "*"), WithDeny(filepath.Join(uploads, "secret")))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if _, err := a.securePath(filepath.Join(uploads, "pub", "test.zip")); err != nil {
+		t.Errorf("securePath failed: %v", err)
+	}
+	_, err = a.securePath(filepath.Join(uploads, "secret", "test.zip"))
+	if err == nil {
+		t.Fatal("expected error for denied path, but got nil")
+	}
+	if !strings.Contains(err.Error(), "denied by server policy") {
+		t.Fatalf("expected deny error, got: %v", err)
+	}
+}
+
+func TestSecurePath_DenySymlink(t *testing.T) {
+	uploads := newUploadsTree(t)
+	a, err := New(filepath.Join(uploads, "*"), WithDeny(filepath.Join(uploads, "secret")))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	symlink := filepath.Join(uploads, "pub", "link.zip")
+	if err := os.Symlink("../secret/test.zip", symlink); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	if _, err := a.securePath(symlink); err == nil {
+		t.Fatal("expected error for symlink into denied path, but got nil")
+	}
+}
+
+func TestSecurePath_DenyThroughSymlink(t *testing.T) {
+	uploads := newUploadsTree(t)
+	link := filepath.Join(filepath.Dir(uploads), "link")
+	if err := os.Symlink(uploads, link); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	deny := []string{filepath.Join(link, "secret")}
+	a, err := New(link, WithDeny(deny...))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if deny[0] != filepath.Join(link, "secret") {
+		t.Errorf("New modified the deny paths passed to WithDeny: %v", deny)
+	}
+	for _, path := range []string{
+		filepath.Join(link, "secret", "test.zip"),
+		filepath.Join(uploads, "secret", "test.zip"),
+	} {
+		if _, err := a.securePath(path); err == nil || !strings.Contains(err.Error(), "denied by server policy") {
+			t.Errorf("expected %s to be denied, got: %v", path, err)
+		}
+	}
+	if _, err := a.securePath(filepath.Join(uploads, "pub", "test.zip")); err != nil {
+		t.Errorf("securePath failed: %v", err)
+	}
+}
+
+func TestSecurePath_SiblingPrefix(t *testing.T) {
+	uploads := newUploadsTree(t)
+	a, err := New(filepath.Join(uploads, "pub"))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	sibling := filepath.Join(uploads, "pub2")
+	if err := os.Mkdir(sibling, 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(sibling, "test.zip"), nil, 0o644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	if _, err := a.securePath(filepath.Join(sibling, "test.zip")); err == nil {
+		t.Fatal("expected error for sibling directory sharing the workdir prefix, but got nil")
+	}
+}
+
+func TestSecureWritePath(t *testing.T) {
+	uploads := newUploadsTree(t)
+	a, err := New(filepath.Join(uploads, "pub"), WithDeny(filepath.Join(uploads, "pub", "denied")))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	pub := filepath.Join(uploads, "pub")
+	if err := os.Symlink("../secret", filepath.Join(pub, "escape")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	if err := os.Symlink("../secret/new.zip", filepath.Join(pub, "dangling.zip")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	// A target that does not exist yet is accepted by secureWritePath
+	// only, as securePath requires it to exist.
+	target := filepath.Join(pub, "out.zip")
+	if _, err := a.securePath(target); err == nil {
+		t.Error("expected securePath to reject a nonexistent path")
+	}
+	got, err := a.secureWritePath(target)
+	if err != nil {
+		t.Fatalf("secureWritePath failed: %v", err)
+	}
+	if got != target {
+		t.Errorf("expected %s, got %s", target, got)
+	}
+	if got, err := a.secureWritePath(filepath.Join(pub, "test.zip")); err != nil || got != filepath.Join(pub, "test.zip") {
+		t.Errorf("secureWritePath of existing file returned %s, %v", got, err)
+	}
+
+	for _, tc := range []struct {
+		path   string
+		reason string
+	}{
+		{"out.zip", reasonNotAbsolute},
+		{filepath.Join(pub, "missing", "out.zip"), reasonUnresolvable},
+		{filepath.Join(pub, "..", "secret", "out.zip"), reasonTraversal},
+		{filepath.Join(pub, "escape", "out.zip"), reasonSymlinkEscape},
+		{filepath.Join(pub, "dangling.zip"), reasonUnresolvable},
+		{filepath.Join(pub, "denied"), reasonDenied},
+	} {
+		_, err := a.secureWritePath(tc.path)
+		var rerr *rejectedError
+		if !errors.As(err, &rerr) || rerr.reason != tc.reason {
+			t.Errorf("%s: expected rejection for %q, got: %v", tc.path, tc.reason, err)
+		}
+	}
+}
+
+func TestSecurePath_SymlinkAcrossRoots(t *testing.T) {
+	uploads := newUploadsTree(t)
+	pub := filepath.Join(uploads, "pub")
+	secret := filepath.Join(uploads, "secret")
+	other := filepath.Join(filepath.Dir(uploads), "other")
+	if err := os.Mkdir(other, 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(other, "test.zip"), nil, 0o644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	a, err := New(strings.Join([]string{pub, secret}, string(os.PathListSeparator)))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	// A symlink in one root resolving into another root is accepted.
+	intoRoot := filepath.Join(pub, "secret.zip")
+	if err := os.Symlink("../secret/test.zip", intoRoot); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	got, err := a.securePath(intoRoot)
+	if err != nil {
+		t.Fatalf("securePath failed for symlink into another root: %v", err)
+	}
+	if want := filepath.Join(secret, "test.zip"); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	// A symlink resolving outside of all roots is rejected.
+	outside := filepath.Join(pub, "other.zip")
+	if err := os.Symlink("../../other/test.zip", outside); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	_, err = a.securePath(outside)
+	var rerr *rejectedError
+	if !errors.As(err, &rerr) || rerr.reason != reasonSymlinkEscape {
+		t.Errorf("expected symlink escape rejection, got: %v", err)
+	}
+}
+
+func TestNew_SymlinkWorkdir(t *testing.T) {
+	uploads := newUploadsTree(t)
+	link := filepath.Join(filepath.Dir(uploads), "workdir")
+	if err := os.Symlink(filepath.Join(uploads, "pub"), link); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	logs := captureLogs(t)
+	a, err := New(link)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if want := filepath.Join(uploads, "pub"); a.Workdir != want {
+		t.Errorf("Workdir = %s, want the resolved %s", a.Workdir, want)
+	}
+	warnings := logs.warnings()
+	if len(warnings) != 1 || warnings[0]["workdir"] != link {
+		t.Errorf("expected a warning about the symlinked workdir, got %v", warnings)
+	}
+
+	// Paths through the symlink are confined to its target.
+	if _, err := a.securePath(filepath.Join(link, "test.zip")); err != nil {
+		t.Errorf("securePath through the symlinked workdir failed: %v", err)
+	}
+	if _, err := a.securePath(filepath.Join(uploads, "secret", "test.zip")); err == nil {
+		t.Error("expected path outside of the symlink target to be rejected")
+	}
+}
+
+func TestNew_InvalidWorkdir(t *testing.T) {
+	uploads := newUploadsTree(t)
+	for _, tc := range []struct {
+		name, workdir, want string
+	}{
+		{"missing", filepath.Join(uploads, "missing"), "does not exist"},
+		{"file", filepath.Join(uploads, "top.zip"), "is not a directory"},
+		{"filesystem root", "/", "cannot be confined"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New(tc.workdir)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("New(%s) returned %v, want error containing %q", tc.workdir, err, tc.want)
+			}
+		})
+	}
+}
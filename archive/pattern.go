@@ -0,0 +1,48 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// The syntaxes of include and exclude patterns.
+const (
+	syntaxRegexp = "regexp"
+	syntaxGlob   = "glob"
+)
+
+// compileNamePattern compiles an include or exclude pattern of the given
+// syntax into a predicate on entry names. An empty syntax selects regexp.
+// Globs use path.Match syntax, where * and ? do not match a slash: a glob
+// without a slash is matched against the base name, so that *.log matches
+// logs/app.log, and other globs against the whole name.
+func compileNamePattern(pattern, syntax string) (func(string) bool, error) {
+	switch syntax {
+	case "", syntaxRegexp:
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString, nil
+	case syntaxGlob:
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, err
+		}
+		baseOnly := !strings.Contains(pattern, "/")
+		return func(name string) bool {
+			name = strings.TrimSuffix(normalizeName(name), "/")
+			if baseOnly {
+				name = path.Base(name)
+			}
+			matched, _ := path.Match(pattern, name)
+			return matched
+		}, nil
+	}
+	return nil, fmt.Errorf("invalid pattern syntax %q: must be %q or %q", syntax, syntaxRegexp, syntaxGlob)
+}
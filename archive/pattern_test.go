@@ -0,0 +1,96 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestCompileNamePattern(t *testing.T) {
+	for _, tc := range []struct {
+		pattern, syntax, name string
+		want                  bool
+	}{
+		{".log", "", "foo/catalog.txt", true},
+		{"*.log", "glob", "foo/catalog.txt", false},
+		{"*.log", "glob", "foo/app.log", true},
+		{"*.log", "glob", "app.log", true},
+		{"foo/*", "glob", "foo/bazz", true},
+		{"foo/*", "glob", "foo/sub/bazz", false},
+		{"foo/*", "glob", "./foo/bazz", true},
+		{"foo", "glob", "foo/", true},
+		{"ba?r.txt", "glob", "foo/baar.txt", true},
+		{"[a-b]*", "glob", "foo/bazz", true},
+		{"ba", "regexp", "foo/bazz", true},
+	} {
+		match, err := compileNamePattern(tc.pattern, tc.syntax)
+		if err != nil {
+			t.Fatalf("compileNamePattern(%q, %q) failed: %v", tc.pattern, tc.syntax, err)
+		}
+		if got := match(tc.name); got != tc.want {
+			t.Errorf("pattern %q with syntax %q on %s: expected %v, got %v", tc.pattern, tc.syntax, tc.name, tc.want, got)
+		}
+	}
+
+	for _, tc := range []struct{ pattern, syntax string }{
+		{"[", "glob"},
+		{"(", "regexp"},
+		{"*", "shell"},
+	} {
+		if _, err := compileNamePattern(tc.pattern, tc.syntax); err == nil {
+			t.Errorf("expected pattern %q with syntax %q to fail", tc.pattern, tc.syntax)
+		}
+	}
+}
+
+func TestList_PatternSyntaxGlob(t *testing.T) {
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := writeSyntheticTar(t, a.Workdir, map[string]string{
+		"logs/app.log":    "a",
+		"logs/catalog":    "b",
+		"logs/old/db.log": "c",
+		"README":          "d",
+	})
+	for _, tc := range []struct {
+		include, exclude string
+		want             []string
+	}{
+		{"*.log", "", []string{"logs/app.log", "logs/old/db.log"}},
+		{"logs/*", "", []string{"logs/app.log", "logs/catalog"}},
+		{"", "*.log", []string{"README", "logs/catalog"}},
+	} {
+		result, err := a.List(context.Background(), ListOptions{
+			Path:           path,
+			IncludePattern: tc.include,
+			ExcludePattern: tc.exclude,
+			PatternSyntax:  "glob",
+			TypeFilter:     typeFile,
+		})
+		if err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		var names []string
+		for _, file := range result.Files {
+			names = append(names, file.Name)
+		}
+		slices.Sort(names)
+		if !slices.Equal(names, tc.want) {
+			t.Errorf("include %q exclude %q: expected %v, got %v", tc.include, tc.exclude, tc.want, names)
+		}
+	}
+
+	if _, err := a.List(context.Background(), ListOptions{Path: path, PatternSyntax: "shell"}); err == nil {
+		t.Error("expected an invalid pattern syntax to fail")
+	}
+	_, err = a.List(context.Background(), ListOptions{Path: path, IncludePattern: "[", PatternSyntax: "glob"})
+	if err == nil {
+		t.Error("expected an invalid glob to fail")
+	}
+}
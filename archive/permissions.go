@@ -0,0 +1,44 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+// Formats of FileInfo.Permissions, see WithPermissionFormat.
+const (
+	// PermissionsSymbolic formats permissions like ls, e.g. -rw-r--r--.
+	PermissionsSymbolic = "symbolic"
+	// PermissionsOctal formats the permission bits, including the setuid,
+	// setgid and sticky bits, as four octal digits, e.g. 0644.
+	PermissionsOctal = "octal"
+)
+
+// formatPermissions formats mode for FileInfo.Permissions. All formats go
+// through here, so that entries of every archive format look the same.
+func formatPermissions(mode fs.FileMode, format string) string {
+	if format != PermissionsOctal {
+		return mode.String()
+	}
+	perm := uint32(mode.Perm())
+	if mode&fs.ModeSetuid != 0 {
+		perm |= 0o4000
+	}
+	if mode&fs.ModeSetgid != 0 {
+		perm |= 0o2000
+	}
+	if mode&fs.ModeSticky != 0 {
+		perm |= 0o1000
+	}
+	return fmt.Sprintf("%04o", perm)
+}
+
+// validPermissionFormat reports whether format is a known permission
+// format.
+func validPermissionFormat(format string) bool {
+	return format == PermissionsSymbolic || format == PermissionsOctal
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatPermissions(t *testing.T) {
+	tests := []struct {
+		mode     fs.FileMode
+		symbolic string
+		octal    string
+	}{
+		{0o644, "-rw-r--r--", "0644"},
+		{fs.ModeDir | 0o755, "drwxr-xr-x", "0755"},
+		{fs.ModeSymlink | 0o777, "Lrwxrwxrwx", "0777"},
+		{fs.ModeSetuid | 0o755, "urwxr-xr-x", "4755"},
+		{fs.ModeDir | fs.ModeSticky | 0o777, "dtrwxrwxrwx", "1777"},
+		{fs.ModeSetgid | 0o750, "grwxr-x---", "2750"},
+	}
+	for _, tt := range tests {
+		if got := formatPermissions(tt.mode, PermissionsSymbolic); got != tt.symbolic {
+			t.Errorf("formatPermissions(%v, symbolic) = %q, want %q", tt.mode, got, tt.symbolic)
+		}
+		if got := formatPermissions(tt.mode, PermissionsOctal); got != tt.octal {
+			t.Errorf("formatPermissions(%v, octal) = %q, want %q", tt.mode, got, tt.octal)
+		}
+	}
+}
+
+func TestList_PermissionFormat(t *testing.T) {
+	for _, tt := range []struct {
+		format    string
+		dir, file string
+	}{
+		{PermissionsSymbolic, "drwxr-xr-x", "-rw-r--r--"},
+		{PermissionsOctal, "0755", "0644"},
+	} {
+		a, err := New("../testdata", WithPermissionFormat(tt.format))
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		for _, f := range formats {
+			result, err := a.List(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, "test."+f.name)})
+			if err != nil {
+				t.Fatalf("List of %s failed: %v", f.name, err)
+			}
+			for _, file := range result.Files {
+				want := tt.file
+				if file.Type == typeDir {
+					want = tt.dir
+				}
+				if file.Permissions != want {
+					t.Errorf("%s: %s in %s has permissions %q, want %q", tt.format, file.Name, f.name, file.Permissions, want)
+				}
+			}
+		}
+	}
+}
+
+func TestNew_InvalidPermissionFormat(t *testing.T) {
+	_, err := New("../testdata", WithPermissionFormat("decimal"))
+	if err == nil || !strings.Contains(err.Error(), "invalid permission format") {
+		t.Errorf("expected invalid permission format error, got: %v", err)
+	}
+}
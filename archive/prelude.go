@@ -0,0 +1,64 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"bytes"
+	"io"
+	"path"
+)
+
+// preludeScanSize is the number of leading bytes searched for the magic of
+// an archive with WithTolerant.
+const preludeScanSize = 1024
+
+// formatMagic holds the magic numbers an archive starts with, by the last
+// suffix of the format name.
+var formatMagic = map[string][][]byte{
+	".gz":   {{0x1f, 0x8b, 0x08}},
+	".bz2":  {[]byte("BZh")},
+	".xz":   {{0xfd, '7', 'z', 'X', 'Z', 0x00}},
+	".zst":  {{0x28, 0xb5, 0x2f, 0xfd}},
+	".sz":   {{0xff, 0x06, 0x00, 0x00, 's', 'N', 'a', 'P', 'p', 'Y'}},
+	".zip":  {[]byte("PK\x03\x04"), []byte("PK\x05\x06"), []byte("PK\x07\x08")},
+	".cpio": {[]byte("070701"), []byte("070702"), []byte("070707"), {0xc7, 0x71}, {0x71, 0xc7}},
+}
+
+// preludeSize returns the number of junk bytes, such as a byte order mark,
+// preceding the magic of the archive of format f read from r. It is 0 if
+// the archive starts with its magic or none is found within the first
+// preludeScanSize bytes.
+func preludeSize(f format, r io.ReaderAt, size int64) int64 {
+	magics := formatMagic[path.Ext("."+f.name)]
+	buf := make([]byte, min(size, preludeScanSize+16))
+	n, _ := r.ReadAt(buf, 0)
+	buf = buf[:n]
+	for offset := 0; offset < len(buf) && offset <= preludeScanSize; offset++ {
+		for _, magic := range magics {
+			if !bytes.HasPrefix(buf[offset:], magic) {
+				continue
+			}
+			// The bzip2 magic is short enough to occur by chance.
+			if magic[0] == 'B' && !isBzip2Header(buf[offset:]) {
+				continue
+			}
+			return int64(offset)
+		}
+	}
+	return 0
+}
+
+// skipPrelude returns r and size without the junk bytes preceding the
+// magic of the archive if WithTolerant is set.
+func (a *Archive) skipPrelude(f format, r io.ReaderAt, size int64) (io.ReaderAt, int64) {
+	if !a.tolerant {
+		return r, size
+	}
+	offset := preludeSize(f, r, size)
+	if offset == 0 {
+		return r, size
+	}
+	return io.NewSectionReader(r, offset, size-offset), size - offset
+}
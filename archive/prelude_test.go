@@ -0,0 +1,89 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeWithPrelude copies the test archive name to dir with junk prepended.
+func writeWithPrelude(t *testing.T, dir, name string, junk []byte) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("../testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", name, err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, append(junk, data...), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestTolerant(t *testing.T) {
+	junk := []byte("\xef\xbb\xbf\r\n1a2\r\n")
+	for _, name := range []string{"test.tar.gz", "test.tar.bz2", "test.tar.xz", "test.tar.zst", "test.cpio", "test.zip"} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := writeWithPrelude(t, dir, name, junk)
+
+			strict, err := New(dir)
+			if err != nil {
+				t.Fatalf("failed to create archive: %v", err)
+			}
+			if name != "test.zip" {
+				// The zip reader locates the central directory from the
+				// end and copes with leading data on its own.
+				if _, err := strict.List(context.Background(), ListOptions{Path: path}); err == nil {
+					t.Error("expected the archive to fail without tolerant")
+				}
+			}
+
+			tolerant, err := New(dir, WithTolerant(true))
+			if err != nil {
+				t.Fatalf("failed to create archive: %v", err)
+			}
+			result, err := tolerant.List(context.Background(), ListOptions{Path: path})
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			if result.TotalFiles != 3 {
+				t.Errorf("expected 3 files, got %d", result.TotalFiles)
+			}
+			extracted, err := tolerant.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/bazz"}})
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+			if len(extracted.Files) != 1 || extracted.Files[0].Content != "bazz\n" {
+				t.Errorf("unexpected extracted files: %+v", extracted.Files)
+			}
+		})
+	}
+}
+
+func TestPreludeSize(t *testing.T) {
+	gz, _ := formatFor("x.tar.gz")
+	bz2, _ := formatFor("x.tar.bz2")
+	for _, tc := range []struct {
+		f    format
+		data []byte
+		want int64
+	}{
+		{gz, []byte("\x1f\x8b\x08rest"), 0},
+		{gz, []byte("junk\x1f\x8b\x08rest"), 4},
+		{gz, []byte("no magic at all"), 0},
+		{gz, append(bytes.Repeat([]byte{0}, preludeScanSize+1), 0x1f, 0x8b, 0x08), 0},
+		// A stray BZh is skipped unless a valid header follows.
+		{bz2, []byte("BZhxxxBZh91AY&SY"), 6},
+	} {
+		if got := preludeSize(tc.f, bytes.NewReader(tc.data), int64(len(tc.data))); got != tc.want {
+			t.Errorf("preludeSize(%s, %q) = %d, want %d", tc.f.name, tc.data, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"archive/zip"
+	"context"
+	"io"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// zipFlagEncrypted is the general purpose flag bit of an encrypted zip
+// entry.
+const zipFlagEncrypted = 0x1
+
+// ProbeOptions are the options for cheaply probing an archive.
+type ProbeOptions struct {
+	Path string `json:"path" jsonschema:"the path to the archive"`
+}
+
+// ProbeArchiveArgs are the arguments for the probe_archive tool.
+type ProbeArchiveArgs ProbeOptions
+
+// ProbeArchiveResult holds the result of the probe_archive tool.
+type ProbeArchiveResult struct {
+	// Format is the canonical name of the format claimed by the file
+	// name, such as "tar.gz", or empty if it is not supported.
+	Format string `json:"format,omitempty"`
+	// Detected is the outermost format found by the magic bytes the file
+	// starts with, such as "gz", "zip" or "tar", or empty if none matches.
+	Detected string `json:"detected,omitempty"`
+	// Supported reports whether the other tools can read the archive.
+	Supported bool `json:"supported"`
+	// Encrypted is set if any entry of a zip archive is encrypted.
+	Encrypted bool `json:"encrypted"`
+	// Solid is set for compressed streams, whose entries can only be
+	// reached by decompressing all entries before them.
+	Solid bool `json:"solid"`
+	// Entries is the number of entries if the format stores it in an
+	// index, as zip does, and -1 if counting requires a full scan.
+	Entries int `json:"entries"`
+}
+
+// detectMagic returns the outermost format found by the magic bytes head
+// starts with, without the leading dot of detectFormat.
+func detectMagic(head []byte) string {
+	if suffix := detectFormat(head); suffix != "" {
+		return strings.TrimPrefix(suffix, ".")
+	}
+	if len(head) >= tarBlockSize && isTarHeader(head[:tarBlockSize]) {
+		return "tar"
+	}
+	return ""
+}
+
+// Probe returns the format of an archive and whether it is encrypted or
+// solid, reading only its first bytes and, for zip archives, the central
+// directory. It also works for files the other tools do not support, so
+// that callers learn what they are dealing with before the heavier calls.
+func (a *Archive) Probe(ctx context.Context, opts ProbeOptions) (ProbeArchiveResult, error) {
+	// Files of any format are probed, so no format is looked up.
+	c, err := a.startCall(ctx, opts.Path, nil)
+	if err != nil {
+		return ProbeArchiveResult{}, err
+	}
+	defer c.done()
+
+	r, err := a.openArchive(c.ctx, c.path)
+	if err != nil {
+		return ProbeArchiveResult{}, err
+	}
+	defer r.Close()
+
+	result := ProbeArchiveResult{Entries: -1}
+	var body io.ReaderAt = r
+	size := r.size
+	f, ok := formatFor(c.path)
+	if ok {
+		result.Format = f.name
+		result.Supported = a.formatEnabled(f)
+		result.Solid = f.decompress != nil
+		body, size = a.skipPrelude(f, r, r.size)
+	}
+	head := make([]byte, min(size, tarBlockSize))
+	n, _ := body.ReadAt(head, 0)
+	result.Detected = detectMagic(head[:n])
+
+	if result.Detected == "zip" {
+		zr, err := zip.NewReader(body, size)
+		if err != nil {
+			// A damaged central directory leaves the count unknown.
+			return result, nil
+		}
+		result.Entries = len(zr.File)
+		for _, file := range zr.File {
+			if file.Flags&zipFlagEncrypted != 0 {
+				result.Encrypted = true
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// ProbeArchive cheaply returns the format of an archive and whether it is
+// encrypted or solid.
+func (a *Archive) ProbeArchive(ctx context.Context, req *mcp.CallToolRequest, args ProbeArchiveArgs) (*mcp.CallToolResult, ProbeArchiveResult, error) {
+	return callTool(ctx, a, req, "probe_archive", args.Path, ProbeOptions(args), a.Probe)
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProbe(t *testing.T) {
+	a := newTestArchive(t)
+	for _, tc := range []struct {
+		name string
+		want ProbeArchiveResult
+	}{
+		{"test.zip", ProbeArchiveResult{Format: "zip", Detected: "zip", Supported: true, Entries: 3}},
+		{"test.tar.gz", ProbeArchiveResult{Format: "tar.gz", Detected: "gz", Supported: true, Solid: true, Entries: -1}},
+		{"test.tar.xz", ProbeArchiveResult{Format: "tar.xz", Detected: "xz", Supported: true, Solid: true, Entries: -1}},
+		{"test.cpio", ProbeArchiveResult{Format: "cpio", Detected: "cpio", Supported: true, Entries: -1}},
+		{"Makefile", ProbeArchiveResult{Entries: -1}},
+	} {
+		got, err := a.Probe(context.Background(), ProbeOptions{Path: filepath.Join(a.Workdir, tc.name)})
+		if err != nil {
+			t.Fatalf("Probe %s failed: %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Errorf("%s: expected %+v, got %+v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestProbe_Encrypted(t *testing.T) {
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	// A zip renamed to .bin is still detected by its magic bytes.
+	path := filepath.Join(a.Workdir, "secret.bin")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create zip: %v", err)
+	}
+	zw := zip.NewWriter(f)
+	for i, name := range []string{"plain.txt", "secret.txt"} {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store, Flags: uint16(i & zipFlagEncrypted)})
+		if err != nil {
+			t.Fatalf("failed to add zip entry: %v", err)
+		}
+		w.Write([]byte("opaque"))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to write zip: %v", err)
+	}
+	f.Close()
+
+	got, err := a.Probe(context.Background(), ProbeOptions{Path: path})
+	if err != nil {
+		t.Fatalf("Probe failed: %v", err)
+	}
+	want := ProbeArchiveResult{Detected: "zip", Encrypted: true, Entries: 2}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
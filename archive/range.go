@@ -0,0 +1,102 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// checkRange validates the byte range of opts before the archive is opened.
+func (a *Archive) checkRange(opts ExtractOptions) error {
+	if len(opts.Files) != 1 && opts.Index == nil {
+		return errors.New("a byte range can only be extracted from a single file")
+	}
+	if opts.RangeStart < 0 || (opts.RangeEnd != 0 && opts.RangeEnd <= opts.RangeStart) {
+		return fmt.Errorf("invalid byte range [%d, %d)", opts.RangeStart, opts.RangeEnd)
+	}
+	if opts.RangeEnd != 0 && opts.RangeEnd-opts.RangeStart > a.maxSize {
+		return fmt.Errorf("byte range [%d, %d) is too large to extract: more than %d bytes", opts.RangeStart, opts.RangeEnd, a.maxSize)
+	}
+	return nil
+}
+
+// readRange reads the bytes [start, end) of an entry, or up to its end if
+// end is 0. Only the size of the range is limited by the maximum size. The
+// bytes before start are decompressed and discarded, as none of the formats
+// allows seeking within a compressed entry.
+func (a *Archive) readRange(e *entry, start, end int64) (File, error) {
+	if err := checkEntrySize(e); err != nil {
+		return File{}, err
+	}
+	if e.info.Type == typeDir || e.info.Type == typeSymlink {
+		return File{}, fmt.Errorf("cannot extract a byte range of %s, which is a %s", e.info.Name, e.info.Type)
+	}
+	if err := checkContent(e.info); err != nil {
+		return File{}, err
+	}
+	if !e.sizeUnknown {
+		if end == 0 {
+			end = e.info.Size
+		}
+		if start >= e.info.Size || end > e.info.Size {
+			return File{}, fmt.Errorf("byte range [%d, %d) out of bounds for %s of %d bytes", start, end, e.info.Name, e.info.Size)
+		}
+		if end-start > a.maxSize {
+			return File{}, &rejectedError{
+				reason: reasonTooLarge,
+				entry:  e.info.Name,
+				err:    fmt.Errorf("byte range [%d, %d) of %s is too large to extract: %d bytes", start, end, e.info.Name, end-start),
+			}
+		}
+	}
+
+	rc, err := e.open()
+	if err != nil {
+		return File{}, err
+	}
+	defer rc.Close()
+
+	if _, err := io.CopyN(io.Discard, rc, start); err != nil {
+		if err == io.EOF {
+			return File{}, fmt.Errorf("byte range start %d out of bounds for %s", start, e.info.Name)
+		}
+		return File{}, fmt.Errorf("could not read file %s from archive: %w", e.info.Name, err)
+	}
+
+	var buf []byte
+	if end == 0 {
+		// Only entries of unknown size get here.
+		buf, err = io.ReadAll(io.LimitReader(rc, a.maxSize+1))
+		if err != nil {
+			return File{}, fmt.Errorf("could not read file %s from archive: %w", e.info.Name, err)
+		}
+		if int64(len(buf)) > a.maxSize {
+			return File{}, &rejectedError{
+				reason: reasonTooLarge,
+				entry:  e.info.Name,
+				err:    fmt.Errorf("byte range from %d of %s is too large to extract: more than %d bytes", start, e.info.Name, a.maxSize),
+			}
+		}
+	} else {
+		buf = make([]byte, end-start)
+		if _, err := io.ReadFull(rc, buf); err != nil {
+			if err == io.ErrUnexpectedEOF || err == io.EOF {
+				return File{}, fmt.Errorf("byte range [%d, %d) out of bounds for %s", start, end, e.info.Name)
+			}
+			return File{}, fmt.Errorf("could not read file %s from archive: %w", e.info.Name, err)
+		}
+	}
+
+	return File{
+		Name:        e.info.Name,
+		Size:        e.info.Size,
+		Permissions: e.info.Permissions,
+		Type:        e.info.Type,
+		Offset:      start,
+		RawContent:  buf,
+	}, nil
+}
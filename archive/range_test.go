@@ -0,0 +1,99 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtract_Range(t *testing.T) {
+	a := newTestArchive(t)
+	// The ranges are smaller than the file, which itself is too large.
+	a.maxSize = 16
+	for _, archiveType := range []string{"test.cpio", "test.tar.gz", "test.tar.zst", "test.zip"} {
+		path := filepath.Join(a.Workdir, archiveType)
+		for _, tc := range []struct {
+			start, end int64
+			want       string
+		}{
+			{4, 9, "Pferd"},
+			{0, 3, "das"},
+			{15, 0, "Gurkensalat\n"},
+			{26, 27, "\n"},
+		} {
+			result, err := a.Extract(context.Background(), ExtractOptions{
+				Path:       path,
+				Files:      []string{"foo/baar.txt"},
+				RangeStart: tc.start,
+				RangeEnd:   tc.end,
+			})
+			if err != nil {
+				t.Fatalf("%s: Extract of [%d, %d) failed: %v", archiveType, tc.start, tc.end, err)
+			}
+			if len(result.Files) != 1 {
+				t.Fatalf("%s: expected 1 file, got %d", archiveType, len(result.Files))
+			}
+			f := result.Files[0]
+			if f.Content != tc.want || f.Offset != tc.start || f.Size != 27 {
+				t.Errorf("%s: [%d, %d) = %q at %d of %d, want %q", archiveType, tc.start, tc.end, f.Content, f.Offset, f.Size, tc.want)
+			}
+		}
+	}
+}
+
+func TestExtract_RangeIndex(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "test.tar.gz")
+	files, err := a.list(context.Background(), path)
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	for index, f := range files {
+		if f.Name != "foo/bazz" {
+			continue
+		}
+		result, err := a.Extract(context.Background(), ExtractOptions{Path: path, Index: &index, RangeStart: 1, RangeEnd: 3})
+		if err != nil {
+			t.Fatalf("Extract failed: %v", err)
+		}
+		if result.Files[0].Content != "az" {
+			t.Errorf("unexpected content %q", result.Files[0].Content)
+		}
+	}
+}
+
+func TestExtract_RangeErrors(t *testing.T) {
+	a := newTestArchive(t)
+	a.maxSize = 16
+	for _, tc := range []struct {
+		files      []string
+		start, end int64
+		err        string
+	}{
+		{[]string{"foo/baar.txt"}, 20, 30, "out of bounds"},
+		{[]string{"foo/baar.txt"}, 27, 0, "out of bounds"},
+		{[]string{"foo/baar.txt"}, 0, 20, "too large"},
+		{[]string{"foo/baar.txt"}, 5, 5, "invalid byte range"},
+		{[]string{"foo/baar.txt"}, -1, 5, "invalid byte range"},
+		{[]string{"foo/baar.txt"}, 0, 0, "too large"},
+		{[]string{"foo/baar.txt"}, 1, 0, "too large"},
+		{[]string{"foo/baar.txt", "foo/bazz"}, 0, 2, "single file"},
+	} {
+		for _, archiveType := range []string{"test.tar.gz", "test.zip"} {
+			_, err := a.Extract(context.Background(), ExtractOptions{
+				Path:       filepath.Join(a.Workdir, archiveType),
+				Files:      tc.files,
+				RangeStart: tc.start,
+				RangeEnd:   tc.end,
+			})
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s: expected error %q for [%d, %d), got: %v", archiveType, tc.err, tc.start, tc.end, err)
+			}
+		}
+	}
+}
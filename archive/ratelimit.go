@@ -0,0 +1,92 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/time/rate"
+)
+
+// RateLimiter limits the rate of tool calls per MCP session, and of HTTP
+// requests per client address, with a token bucket for each.
+type RateLimiter struct {
+	limit rate.Limit
+	burst int
+
+	mu        sync.Mutex
+	sessions  map[string]*rate.Limiter
+	lastSweep time.Time
+}
+
+// NewRateLimiter returns a RateLimiter allowing perSecond tool calls per
+// second and session, with bursts of up to burst calls.
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		limit:    rate.Limit(perSecond),
+		burst:    max(burst, 1),
+		sessions: make(map[string]*rate.Limiter),
+	}
+}
+
+// allow reports whether the session or client, identified by key, may make
+// another call now.
+func (l *RateLimiter) allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Sessions whose bucket has refilled are indistinguishable from new
+	// ones, so they are dropped to keep the map bounded.
+	if now.Sub(l.lastSweep) > time.Minute {
+		for id, lim := range l.sessions {
+			if lim.TokensAt(now) >= float64(l.burst) {
+				delete(l.sessions, id)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	lim, ok := l.sessions[key]
+	if !ok {
+		lim = rate.NewLimiter(l.limit, l.burst)
+		l.sessions[key] = lim
+	}
+	return lim.AllowN(now, 1)
+}
+
+// Middleware is an mcp.Middleware rejecting tool calls of sessions that
+// exceed their rate.
+func (l *RateLimiter) Middleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if method == "tools/call" && !l.allow(req.GetSession().ID(), time.Now()) {
+			return nil, fmt.Errorf("rate limit exceeded: at most %g tool calls per second with bursts of %d", float64(l.limit), l.burst)
+		}
+		return next(ctx, method, req)
+	}
+}
+
+// HTTPMiddleware wraps an HTTP handler, rejecting requests of client
+// addresses that exceed their rate with 429 Too Many Requests. Clients are
+// keyed by the host of the remote address, so they share no bucket with
+// MCP sessions.
+func (l *RateLimiter) HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		if !l.allow("addr:"+host, time.Now()) {
+			http.Error(w, fmt.Sprintf("rate limit exceeded: at most %g requests per second with bursts of %d", float64(l.limit), l.burst), http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
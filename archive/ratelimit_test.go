@@ -0,0 +1,94 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestRateLimiter_PerSession(t *testing.T) {
+	l := NewRateLimiter(1, 2)
+	now := time.Now()
+
+	if !l.allow("a", now) || !l.allow("a", now) {
+		t.Fatal("expected burst of 2 calls to be allowed")
+	}
+	if l.allow("a", now) {
+		t.Fatal("expected third call to be rejected")
+	}
+	if !l.allow("b", now) {
+		t.Fatal("expected other session not to be limited")
+	}
+	if !l.allow("a", now.Add(time.Second)) {
+		t.Fatal("expected call to be allowed after refill")
+	}
+}
+
+func TestRateLimiter_Sweep(t *testing.T) {
+	l := NewRateLimiter(1, 1)
+	now := time.Now()
+	l.allow("a", now)
+	l.allow("b", now.Add(2*time.Minute))
+	if _, ok := l.sessions["a"]; ok {
+		t.Error("expected idle session to be dropped")
+	}
+}
+
+func TestRateLimiter_Middleware(t *testing.T) {
+	l := NewRateLimiter(1, 1)
+	calls := 0
+	next := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		calls++
+		return &mcp.CallToolResult{}, nil
+	}
+	handler := l.Middleware(next)
+	req := &mcp.CallToolRequest{Session: &mcp.ServerSession{}}
+
+	if _, err := handler(context.Background(), "tools/call", req); err != nil {
+		t.Fatalf("first call failed: %v", err)
+	}
+	_, err := handler(context.Background(), "tools/call", req)
+	if err == nil || !strings.Contains(err.Error(), "rate limit exceeded") {
+		t.Fatalf("expected rate limit error, got: %v", err)
+	}
+	if _, err := handler(context.Background(), "tools/list", req); err != nil {
+		t.Fatalf("expected other methods not to be limited, got: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls to reach the handler, got %d", calls)
+	}
+}
+
+func TestRateLimiter_HTTPMiddleware(t *testing.T) {
+	l := NewRateLimiter(1, 1)
+	handler := l.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	serve := func(remoteAddr string) int {
+		req := httptest.NewRequest("GET", "/list.ndjson", nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := serve("192.0.2.1:1234"); code != http.StatusOK {
+		t.Fatalf("first request failed with %d", code)
+	}
+	// The port differs for every connection, so it is not part of the key.
+	if code := serve("192.0.2.1:5678"); code != http.StatusTooManyRequests {
+		t.Fatalf("expected second request to be rejected, got %d", code)
+	}
+	if code := serve("192.0.2.2:1234"); code != http.StatusOK {
+		t.Fatalf("expected other client not to be limited, got %d", code)
+	}
+}
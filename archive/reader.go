@@ -0,0 +1,100 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// formatByName returns the format with the given canonical name, e.g.
+// "tar.gz".
+func formatByName(name string) (format, bool) {
+	for _, f := range formats {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return format{}, false
+}
+
+// noneIgnored is the ignore predicate for archives outside the working
+// directory roots.
+func noneIgnored(FileInfo) bool { return false }
+
+// ListReader lists the entries of an archive read from r, for embedding the
+// library where the archive is not a local file, e.g. an object in remote
+// storage. The format is a canonical format name such as "tar.gz" or
+// "zip". The depth, type, include, exclude, deduplicate and top N options
+// apply, while Path and Limit are ignored and all matching entries are
+// returned.
+//
+// There is no path confinement and no ignore file applies, as r is provided
+// by the caller. The entry limits, decoder limits and timeout still do.
+func (a *Archive) ListReader(ctx context.Context, r io.ReaderAt, size int64, formatName string, opts ListOptions) ([]FileInfo, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	f, ok := formatByName(formatName)
+	if !ok {
+		return nil, fmt.Errorf("unsupported archive format %s", formatName)
+	}
+
+	callCtx, cancel := a.callContext(ctx)
+	defer cancel()
+	release, err := a.acquire(callCtx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var files []FileInfo
+	err = a.walkReader(callCtx, f, r, size, "", func(e *entry) error {
+		e.info.DataOffset = dataOffset(e)
+		files = append(files, e.info)
+		return nil
+	})
+	if err != nil {
+		return nil, a.timeoutError(ctx, err)
+	}
+	l, err := a.filterListing(files, opts)
+	if err != nil {
+		return nil, err
+	}
+	return largest(l.filtered, opts.TopN), nil
+}
+
+// ExtractReader extracts files from an archive read from r, like Extract
+// does for an archive at a path. The format is a canonical format name and
+// Path is ignored. As with ListReader, there is no path confinement.
+func (a *Archive) ExtractReader(ctx context.Context, r io.ReaderAt, size int64, formatName string, opts ExtractOptions) (ExtractArchiveFilesResult, error) {
+	x, err := a.newExtraction(opts)
+	if err != nil {
+		return ExtractArchiveFilesResult{}, err
+	}
+	f, ok := formatByName(formatName)
+	if !ok {
+		return ExtractArchiveFilesResult{}, fmt.Errorf("unsupported archive format %s", formatName)
+	}
+
+	callCtx, cancel := a.callContext(ctx)
+	defer cancel()
+	release, err := a.acquire(callCtx)
+	if err != nil {
+		return ExtractArchiveFilesResult{}, err
+	}
+	defer release()
+
+	walk := func(fn walkFunc) error {
+		return a.walkReader(callCtx, f, r, size, "", fn)
+	}
+	files, err := x.run(walk, noneIgnored)
+	if err != nil {
+		return ExtractArchiveFilesResult{}, a.timeoutError(ctx, err)
+	}
+	a.metrics.extracted(files)
+	return x.finish(files)
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestListReader(t *testing.T) {
+	a := newTestArchive(t)
+	for _, f := range formats {
+		t.Run(f.name, func(t *testing.T) {
+			data, err := os.ReadFile("../testdata/test." + f.name)
+			if err != nil {
+				t.Fatalf("failed to read fixture: %v", err)
+			}
+			files, err := a.ListReader(context.Background(), bytes.NewReader(data), int64(len(data)), f.name, ListOptions{TypeFilter: typeFile})
+			if err != nil {
+				t.Fatalf("ListReader failed: %v", err)
+			}
+			if len(files) != 2 {
+				t.Errorf("expected 2 files, got %v", files)
+			}
+		})
+	}
+}
+
+func TestListReader_UnknownFormat(t *testing.T) {
+	a := newTestArchive(t)
+	_, err := a.ListReader(context.Background(), bytes.NewReader(nil), 0, "rar", ListOptions{})
+	if err == nil || !strings.Contains(err.Error(), "unsupported archive format rar") {
+		t.Errorf("expected unsupported format error, got: %v", err)
+	}
+}
+
+func TestExtractReader(t *testing.T) {
+	a := newTestArchive(t)
+	file, err := os.Open("../testdata/test.zip")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		t.Fatalf("failed to stat fixture: %v", err)
+	}
+
+	result, err := a.ExtractReader(context.Background(), file, stat.Size(), "zip", ExtractOptions{Files: []string{"foo/baar.txt"}})
+	if err != nil {
+		t.Fatalf("ExtractReader failed: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].Content != "das Pferd isst Gurkensalat\n" {
+		t.Errorf("unexpected extracted files: %v", result.Files)
+	}
+}
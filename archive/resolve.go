@@ -0,0 +1,65 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"errors"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ResolveOptions are the options for resolving a path.
+type ResolveOptions struct {
+	Path string `json:"path" jsonschema:"the path to resolve"`
+}
+
+// ResolvePathArgs are the arguments for the resolve_path tool.
+type ResolvePathArgs ResolveOptions
+
+// Statuses reported in ResolvePathResult.Status.
+const (
+	statusOK       = "ok"
+	statusRejected = "rejected"
+)
+
+// ResolvePathResult holds the result of the resolve_path tool.
+type ResolvePathResult struct {
+	// Status is "ok" or "rejected".
+	Status string `json:"status"`
+	// Path is the resolved absolute path. It is only set if the path was
+	// accepted, so that paths outside the working directory never leak.
+	Path string `json:"path,omitempty"`
+	// Reason tells why the path was rejected, e.g. "path traversal" or
+	// "symlink escape".
+	Reason string `json:"reason,omitempty"`
+	// Format is the archive format of an accepted path, if supported.
+	Format string `json:"format,omitempty"`
+}
+
+// Resolve runs a path through the same confinement as the other tools and
+// reports the outcome without opening the file.
+func (a *Archive) Resolve(ctx context.Context, opts ResolveOptions) (ResolvePathResult, error) {
+	path, err := a.secureArchivePath(opts.Path)
+	if err != nil {
+		var rerr *rejectedError
+		if !errors.As(err, &rerr) {
+			return ResolvePathResult{}, err
+		}
+		a.audit(ctx, opts.Path, err)
+		return ResolvePathResult{Status: statusRejected, Reason: rerr.reason}, nil
+	}
+	result := ResolvePathResult{Status: statusOK, Path: path}
+	if f, ok := formatFor(path); ok {
+		result.Format = f.name
+	}
+	return result, nil
+}
+
+// ResolvePath resolves a path as the other tools do, for debugging rejected
+// paths.
+func (a *Archive) ResolvePath(ctx context.Context, req *mcp.CallToolRequest, args ResolvePathArgs) (*mcp.CallToolResult, ResolvePathResult, error) {
+	return callTool(ctx, a, req, "resolve_path", args.Path, ResolveOptions(args), a.Resolve)
+}
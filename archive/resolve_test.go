@@ -0,0 +1,65 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	tmp, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to resolve temp dir: %v", err)
+	}
+	workdir := filepath.Join(tmp, "work")
+	if err := os.Mkdir(workdir, 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	for _, name := range []string{"test.zip", "notes.txt", filepath.Join("..", "secret.zip")} {
+		if err := os.WriteFile(filepath.Join(workdir, name), nil, 0o644); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+	}
+	outside := filepath.Join(tmp, "secret.zip")
+	link := filepath.Join(tmp, "link.zip")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	workLink := filepath.Join(workdir, "escape.zip")
+	if err := os.Symlink(outside, workLink); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	a, err := New(workdir)
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		path string
+		want ResolvePathResult
+	}{
+		{"ok", filepath.Join(a.Workdir, "foo/../test.zip"), ResolvePathResult{Status: "ok", Path: filepath.Join(a.Workdir, "test.zip"), Format: "zip"}},
+		{"unsupported", filepath.Join(a.Workdir, "notes.txt"), ResolvePathResult{Status: "ok", Path: filepath.Join(a.Workdir, "notes.txt")}},
+		{"relative", "test.zip", ResolvePathResult{Status: "rejected", Reason: reasonNotAbsolute}},
+		{"missing", filepath.Join(a.Workdir, "missing.zip"), ResolvePathResult{Status: "rejected", Reason: reasonUnresolvable}},
+		{"traversal", filepath.Join(a.Workdir, "../secret.zip"), ResolvePathResult{Status: "rejected", Reason: reasonTraversal}},
+		{"symlink", workLink, ResolvePathResult{Status: "rejected", Reason: reasonSymlinkEscape}},
+		{"outside symlink", link, ResolvePathResult{Status: "rejected", Reason: reasonTraversal}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := a.Resolve(context.Background(), ResolveOptions{Path: tc.path})
+			if err != nil {
+				t.Fatalf("Resolve failed: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("Resolve(%s) = %+v, want %+v", tc.path, got, tc.want)
+			}
+		})
+	}
+}
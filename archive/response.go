@@ -0,0 +1,139 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"cmp"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// defaultMaxResponseBytes is the default cap on the estimated size of an
+// extraction result.
+const defaultMaxResponseBytes = 8 << 20
+
+// fileOverhead approximates the bytes of the JSON encoding of a File besides
+// its name, link target and content.
+const fileOverhead = 100
+
+// ResponseTooLargeError is returned instead of an extraction result whose
+// estimated size exceeds the maximum response size, see
+// WithMaxResponseBytes. Fit tells how many of the requested files would
+// have fit, so that they can be requested in smaller batches.
+type ResponseTooLargeError struct {
+	// Size is the estimated size of the response in bytes.
+	Size int64
+	// Max is the maximum response size in bytes.
+	Max int64
+	// Files is the number of extracted files and Fit the number of them,
+	// in order, that fit into the maximum response size.
+	Files int
+	Fit   int
+	// Bundled is set if the files were bundled as zip.
+	Bundled bool
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	msg := fmt.Sprintf("response of about %d bytes exceeds the maximum of %d bytes: the first %d of %d files fit; request fewer files at a time", e.Size, e.Max, e.Fit, e.Files)
+	if !e.Bundled {
+		msg += " or set bundle_as_zip to compress them"
+	}
+	return msg
+}
+
+// The modes of ExtractOptions.FitMode.
+const (
+	fitError         = "error"
+	fitSmallestFirst = "smallest-first"
+)
+
+// metadataSize estimates the bytes taken by file in a response besides its
+// content.
+func metadataSize(file File) int64 {
+	return fileOverhead + int64(len(file.Name)+len(file.LinkTarget))
+}
+
+// checkResponseSize fails with a ResponseTooLargeError if the files would
+// take more than max bytes in the response. Their content is still raw at
+// this point. If bundled, the content is instead carried by a zip bundle of
+// bundleSize bytes, which is accounted for at its base64 encoded size, and
+// the files that fit are estimated by their uncompressed base64 encoded
+// size. A max of zero disables the check.
+func checkResponseSize(files []File, bundled bool, bundleSize, max int64) error {
+	if max <= 0 {
+		return nil
+	}
+	var size, cumulative int64
+	fit := 0
+	for _, file := range files {
+		content := int64(len(file.RawContent))
+		if bundled {
+			content = int64(base64.StdEncoding.EncodedLen(len(file.RawContent)))
+		}
+		cumulative += metadataSize(file) + content
+		if cumulative <= max {
+			fit++
+		}
+		size += metadataSize(file)
+		if !bundled {
+			size += content
+		}
+	}
+	if bundled {
+		size += int64(base64.StdEncoding.EncodedLen(int(bundleSize)))
+	}
+	if size <= max {
+		return nil
+	}
+	return &ResponseTooLargeError{Size: size, Max: max, Files: len(files), Fit: fit, Bundled: bundled}
+}
+
+// fitsSmallest reports whether err is a ResponseTooLargeError that the
+// smallest-first fit mode applies to.
+func (x *extraction) fitsSmallest(err error) bool {
+	var tooLarge *ResponseTooLargeError
+	return x.opts.FitMode == fitSmallestFirst && errors.As(err, &tooLarge)
+}
+
+// fitSmallest keeps the smallest of files that fit into the maximum
+// response size together, in their original order, and records the others
+// as skipped. Bundled files are estimated at their uncompressed base64
+// encoded size, which their compressed size does not exceed much.
+func (x *extraction) fitSmallest(files []File, bundled bool) []File {
+	size := func(file File) int64 {
+		if bundled {
+			return metadataSize(file) + int64(base64.StdEncoding.EncodedLen(len(file.RawContent)))
+		}
+		return metadataSize(file) + int64(len(file.RawContent))
+	}
+	order := make([]int, len(files))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(i, j int) int {
+		return cmp.Compare(size(files[i]), size(files[j]))
+	})
+	keep := make([]bool, len(files))
+	var total int64
+	for _, i := range order {
+		if total+size(files[i]) > x.maxResponseBytes {
+			break
+		}
+		total += size(files[i])
+		keep[i] = true
+	}
+	var kept []File
+	for i, file := range files {
+		if keep[i] {
+			kept = append(kept, file)
+			continue
+		}
+		err := fmt.Errorf("%s does not fit into the maximum response size of %d bytes", file.Name, x.maxResponseBytes)
+		x.skipped = append(x.skipped, SkippedFile{Name: file.Name, Reason: err.Error(), err: err})
+	}
+	return kept
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExtract_MaxResponseBytes(t *testing.T) {
+	a, err := New(t.TempDir(), WithMaxResponseBytes(1000))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := writeSyntheticTar(t, a.Workdir, map[string]string{
+		"a.txt": strings.Repeat("a", 300),
+		"b.txt": strings.Repeat("b", 300),
+		"c.txt": strings.Repeat("c", 300),
+	})
+	ctx := context.Background()
+
+	if _, err := a.Extract(ctx, ExtractOptions{Path: path, Files: []string{"a.txt", "b.txt"}}); err != nil {
+		t.Fatalf("Extract within the limit failed: %v", err)
+	}
+
+	_, err = a.Extract(ctx, ExtractOptions{Path: path, Files: []string{"c.txt", "a.txt", "b.txt"}})
+	var rerr *ResponseTooLargeError
+	if !errors.As(err, &rerr) {
+		t.Fatalf("expected ResponseTooLargeError, got: %v", err)
+	}
+	if rerr.Files != 3 || rerr.Fit != 2 || rerr.Max != 1000 || rerr.Size <= 1000 || rerr.Bundled {
+		t.Errorf("unexpected error details: %+v", rerr)
+	}
+	if !strings.Contains(err.Error(), "the first 2 of 3 files fit") || !strings.Contains(err.Error(), "bundle_as_zip") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+
+	// The repetitive content compresses well.
+	result, err := a.Extract(ctx, ExtractOptions{Path: path, Files: []string{"a.txt", "b.txt", "c.txt"}, BundleAsZip: true})
+	if err != nil {
+		t.Fatalf("Extract as bundle failed: %v", err)
+	}
+	if len(result.Files) != 3 {
+		t.Errorf("expected 3 bundled files, got %d", len(result.Files))
+	}
+
+	// In-process callers of raw content are not limited.
+	if _, err := a.Extract(ctx, ExtractOptions{Path: path, Files: []string{"a.txt", "b.txt", "c.txt"}, Raw: true}); err != nil {
+		t.Errorf("raw Extract failed: %v", err)
+	}
+}
+
+func TestCheckResponseSize_Bundled(t *testing.T) {
+	files := []File{{Name: "a", RawContent: make([]byte, 300)}, {Name: "b", RawContent: make([]byte, 300)}}
+	err := checkResponseSize(files, true, 900, 1000)
+	var rerr *ResponseTooLargeError
+	if !errors.As(err, &rerr) {
+		t.Fatalf("expected ResponseTooLargeError, got: %v", err)
+	}
+	// 2*(100+1) bytes of metadata and 1200 bytes of base64.
+	if rerr.Size != 1402 || rerr.Fit != 1 || !rerr.Bundled {
+		t.Errorf("unexpected error details: %+v", rerr)
+	}
+	if strings.Contains(err.Error(), "bundle_as_zip") {
+		t.Errorf("bundled error suggests bundling: %v", err)
+	}
+	if err := checkResponseSize(files, true, 900, 0); err != nil {
+		t.Errorf("disabled check failed: %v", err)
+	}
+}
+
+func TestExtract_FitModeSmallestFirst(t *testing.T) {
+	a, err := New(t.TempDir(), WithMaxResponseBytes(1000))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	// Random content does not compress, so bundling does not help.
+	random := func(seed int64, n int) string {
+		b := make([]byte, n)
+		rand.New(rand.NewSource(seed)).Read(b)
+		return string(b)
+	}
+	path := writeSyntheticTar(t, a.Workdir, map[string]string{
+		"a.bin": random(1, 600),
+		"b.bin": random(2, 100),
+		"c.bin": random(3, 200),
+		"d.bin": random(4, 500),
+	})
+	ctx := context.Background()
+	files := []string{"a.bin", "b.bin", "c.bin", "d.bin"}
+
+	_, err = a.Extract(ctx, ExtractOptions{Path: path, Files: files, FitMode: "error"})
+	var rerr *ResponseTooLargeError
+	if !errors.As(err, &rerr) {
+		t.Fatalf("expected ResponseTooLargeError, got: %v", err)
+	}
+
+	for _, bundled := range []bool{false, true} {
+		result, err := a.Extract(ctx, ExtractOptions{Path: path, Files: files, FitMode: "smallest-first", BundleAsZip: bundled})
+		if err != nil {
+			t.Fatalf("Extract with bundled %v failed: %v", bundled, err)
+		}
+		var names, skipped []string
+		for _, f := range result.Files {
+			names = append(names, f.Name)
+		}
+		for _, s := range result.Skipped {
+			skipped = append(skipped, s.Name)
+			if !strings.Contains(s.Reason, "maximum response size of 1000 bytes") {
+				t.Errorf("unexpected reason for %s: %s", s.Name, s.Reason)
+			}
+		}
+		if !slices.Equal(names, []string{"b.bin", "c.bin"}) || !slices.Equal(skipped, []string{"a.bin", "d.bin"}) {
+			t.Errorf("bundled %v: expected b.bin and c.bin to fit and a.bin and d.bin skipped, got %v and %v", bundled, names, skipped)
+		}
+	}
+
+	if _, err := a.Extract(ctx, ExtractOptions{Path: path, Files: files, FitMode: "largest-first"}); err == nil {
+		t.Error("expected an invalid fit mode to fail")
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+)
+
+// selfTestArchives holds a tiny sample archive, selftest/sample.<format>,
+// for each supported format.
+//
+//go:embed selftest
+var selfTestArchives embed.FS
+
+// SelfTest lists the embedded sample archive of each enabled format to
+// confirm that all decoders work. It is meant for readiness probes.
+func (a *Archive) SelfTest(ctx context.Context) error {
+	for _, f := range formats {
+		if !a.formatEnabled(f) {
+			continue
+		}
+		data, err := selfTestArchives.ReadFile("selftest/sample." + f.name)
+		if err != nil {
+			return fmt.Errorf("no sample archive for %s: %w", f.name, err)
+		}
+		entries := 0
+		err = a.walkFormat(ctx, f, bytes.NewReader(data), int64(len(data)), "sample", func(*entry) error {
+			entries++
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list sample %s archive: %w", f.name, err)
+		}
+		if entries == 0 {
+			return fmt.Errorf("no entries in sample %s archive", f.name)
+		}
+	}
+	return nil
+}
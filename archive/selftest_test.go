@@ -0,0 +1,27 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSelfTest(t *testing.T) {
+	a := newTestArchive(t)
+	if err := a.SelfTest(context.Background()); err != nil {
+		t.Fatalf("SelfTest failed: %v", err)
+	}
+}
+
+func TestSelfTest_DecoderFailure(t *testing.T) {
+	a := newTestArchive(t)
+	a.decoderLimits.maxMemory = 1 << 10
+	err := a.SelfTest(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "sample cpio.xz archive") {
+		t.Fatalf("expected the xz sample to fail, got: %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"errors"
+)
+
+// SkippedFile is a requested file that could not be extracted with
+// ExtractOptions.BestEffort or was left out by ExtractOptions.FitMode.
+type SkippedFile struct {
+	Name string `json:"name"`
+	// Reason is the error that prevented the extraction or the reason
+	// the file was left out.
+	Reason string `json:"reason"`
+	// err is the error behind Reason, kept for auditing.
+	err error
+}
+
+// errSkipEntry is returned by the read function of a best effort
+// extraction for an entry that was recorded as skipped.
+var errSkipEntry = errors.New("skip entry")
+
+// skipping wraps read to record the entries it fails on in x.skipped
+// instead of failing the extraction. Cancellation and the call timeout
+// still end it.
+func (x *extraction) skipping(read func(*entry) (File, error)) func(*entry) (File, error) {
+	return func(e *entry) (File, error) {
+		file, err := read(e)
+		if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return file, err
+		}
+		x.skipped = append(x.skipped, SkippedFile{Name: e.info.Name, Reason: err.Error(), err: err})
+		return File{}, errSkipEntry
+	}
+}
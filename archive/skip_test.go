@@ -0,0 +1,50 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestExtract_BestEffort(t *testing.T) {
+	dir := t.TempDir()
+	a, err := New(dir)
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	a.maxSize = 20
+	path := writeSyntheticTar(t, dir, map[string]string{
+		"small.txt": "small\n",
+		"big.txt":   strings.Repeat("big\n", 10),
+	})
+	ctx := context.Background()
+
+	if _, err := a.Extract(ctx, ExtractOptions{Path: path, Files: []string{"small.txt", "big.txt"}}); err == nil {
+		t.Error("expected a strict extraction of an oversized file to fail")
+	}
+
+	result, err := a.Extract(ctx, ExtractOptions{Path: path, Files: []string{"small.txt", "big.txt"}, BestEffort: true})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].Name != "small.txt" || result.Files[0].Content != "small\n" {
+		t.Errorf("unexpected files: %+v", result.Files)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].Name != "big.txt" || !strings.Contains(result.Skipped[0].Reason, "too large") {
+		t.Errorf("unexpected skipped files: %+v", result.Skipped)
+	}
+
+	index := 0
+	result, err = a.Extract(ctx, ExtractOptions{Path: path, Index: &index, BestEffort: true})
+	if err != nil {
+		t.Fatalf("Extract by index failed: %v", err)
+	}
+	// Entries are written in sorted order, so big.txt comes first.
+	if len(result.Files) != 0 || len(result.Skipped) != 1 || result.Skipped[0].Name != "big.txt" {
+		t.Errorf("expected big.txt at index 0 to be skipped, got %+v", result)
+	}
+}
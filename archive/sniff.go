@@ -0,0 +1,88 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// tarBlockSize is the size of a tar header block.
+const tarBlockSize = 512
+
+// isTarHeader reports whether block, the first block of a decompressed
+// stream, starts a tar archive. Besides headers with the ustar magic at
+// offset 257, old V7 headers are recognized by their checksum. An empty
+// stream or a zero block is an empty tar archive.
+func isTarHeader(block []byte) bool {
+	if len(block) == 0 {
+		return true
+	}
+	if len(block) < tarBlockSize {
+		return false
+	}
+	if bytes.Equal(block, make([]byte, tarBlockSize)) {
+		return true
+	}
+	if bytes.HasPrefix(block[257:], []byte("ustar")) {
+		return true
+	}
+	field := strings.Trim(string(block[148:156]), " \x00")
+	checksum, err := strconv.ParseInt(field, 8, 64)
+	if err != nil {
+		return false
+	}
+	var sum int64
+	for i, b := range block {
+		if i >= 148 && i < 156 {
+			b = ' '
+		}
+		sum += int64(b)
+	}
+	return sum == checksum
+}
+
+// memberName is the name of the content of a single compressed file at
+// path: its base name without the format suffix.
+func memberName(p string, f format) string {
+	base := filepath.Base(strings.TrimSuffix(p, splitSuffix))
+	return strings.TrimSuffix(base, "."+f.name)
+}
+
+// singleEntry returns the only entry of a single compressed file, whose
+// content is read from content. If the outermost and only compression
+// layer is gzip, the original name and size stored by gzip are used.
+// Otherwise the entry is named name and its size is unknown until read.
+func singleEntry(content io.Reader, gz *gzip.Reader, r io.ReaderAt, size int64, name string) *entry {
+	info := FileInfo{Name: name, Type: typeFile}
+	if gz != nil {
+		if gz.Name != "" {
+			info.Name = path.Base(gz.Name)
+		}
+		// The trailer of a gzip stream ends with the size of the
+		// uncompressed data modulo 2^32.
+		var trailer [4]byte
+		if _, err := r.ReadAt(trailer[:], size-4); err == nil {
+			info.Size = int64(binary.LittleEndian.Uint32(trailer[:]))
+		}
+	}
+	if info.Name == "" || info.Name == "." || info.Name == "/" {
+		info.Name = "data"
+	}
+	return &entry{
+		info: info,
+		open: func() (io.ReadCloser, error) {
+			return io.NopCloser(content), nil
+		},
+		mode:        0o644,
+		sizeUnknown: true,
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeGzip writes content gzip compressed to name in dir, storing
+// storedName in the gzip header, and returns the path.
+func writeGzip(t *testing.T, dir, name, storedName string, content []byte) string {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Name = storedName
+	zw.Write(content)
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to write gzip: %v", err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestSniff_TarNamedGz(t *testing.T) {
+	a := newTestArchive(t)
+	result, err := a.Extract(context.Background(), ExtractOptions{Path: filepath.Join(a.Workdir, "test.gz"), Files: []string{"foo/baar.txt"}})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].Content != "das Pferd isst Gurkensalat\n" {
+		t.Errorf("Extract returned %+v, want foo/baar.txt", result.Files)
+	}
+}
+
+func TestSniff_SingleFile(t *testing.T) {
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	content := []byte("das Pferd isst Gurkensalat\n")
+	fixture, err := os.ReadFile("../testdata/single.gz")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(a.Workdir, "single.gz"), fixture, 0o644); err != nil {
+		t.Fatalf("failed to copy fixture: %v", err)
+	}
+	for _, tc := range []struct {
+		name     string
+		path     string
+		wantName string
+		wantSize int64
+	}{
+		{name: "stored name", path: writeGzip(t, a.Workdir, "a.gz", "baar.txt", content), wantName: "baar.txt", wantSize: int64(len(content))},
+		{name: "file name", path: writeGzip(t, a.Workdir, "notes.txt.gz", "", content), wantName: "notes.txt", wantSize: int64(len(content))},
+		{name: "tar.gz without tar", path: writeGzip(t, a.Workdir, "plain.tar.gz", "", content), wantName: "plain", wantSize: int64(len(content))},
+		{name: "fixture", path: filepath.Join(a.Workdir, "single.gz"), wantName: "baar.txt", wantSize: int64(len(content))},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			list, err := a.List(context.Background(), ListOptions{Path: tc.path})
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			if len(list.Files) != 1 || list.Files[0].Name != tc.wantName || list.Files[0].Size != tc.wantSize {
+				t.Fatalf("List returned %+v, want only %s of size %d", list.Files, tc.wantName, tc.wantSize)
+			}
+			result, err := a.Extract(context.Background(), ExtractOptions{Path: tc.path, Files: []string{tc.wantName}})
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+			if len(result.Files) != 1 || result.Files[0].Content != string(content) {
+				t.Errorf("Extract returned %+v, want content %q", result.Files, content)
+			}
+		})
+	}
+}
+
+func TestIsTarHeader(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	tw.WriteHeader(&tar.Header{Name: "foo", Mode: 0o644, Format: tar.FormatUSTAR})
+	tw.Close()
+	ustar := buf.Bytes()[:tarBlockSize]
+
+	// A V7 header has no magic but a valid checksum.
+	v7 := bytes.Clone(ustar)
+	copy(v7[257:265], make([]byte, 8))
+	var sum int
+	for i, b := range v7 {
+		if i >= 148 && i < 156 {
+			b = ' '
+		}
+		sum += int(b)
+	}
+	copy(v7[148:156], fmt.Sprintf("%06o\x00 ", sum))
+
+	text := bytes.Repeat([]byte("das Pferd isst Gurkensalat\n"), 20)
+	for _, tc := range []struct {
+		name  string
+		block []byte
+		want  bool
+	}{
+		{"ustar", ustar, true},
+		{"v7", v7, true},
+		{"empty", nil, true},
+		{"zero block", make([]byte, tarBlockSize), true},
+		{"short", text[:100], false},
+		{"text", text[:tarBlockSize], false},
+	} {
+		if got := isTarHeader(tc.block); got != tc.want {
+			t.Errorf("isTarHeader(%s) = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// splitSuffix is the extension of the first volume of an archive split into
+// numbered parts, e.g. archive.zip.001, archive.zip.002 and so on, as
+// written by split(1) or 7-Zip. Concatenating the parts yields the original
+// archive, so any supported format may be split this way.
+//
+// Spanned zip archives (archive.z01, archive.z02, ..., archive.zip) store
+// offsets relative to each volume and are not supported.
+const splitSuffix = ".001"
+
+// maxSplitParts is the largest number of parts with a three digit suffix.
+const maxSplitParts = 999
+
+// splitParts returns the volumes of the archive at path in order. An archive
+// that is not split consists of a single part.
+func splitParts(path string) ([]string, error) {
+	if !strings.HasSuffix(path, splitSuffix) {
+		return []string{path}, nil
+	}
+	base := strings.TrimSuffix(path, splitSuffix)
+	parts := []string{path}
+	for i := 2; i <= maxSplitParts; i++ {
+		part := fmt.Sprintf("%s.%03d", base, i)
+		if _, err := os.Lstat(part); errors.Is(err, fs.ErrNotExist) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
+// openArchive opens the archive at path, which must already have been
+// confined by securePath, joining the volumes of split archives. Every
+// further volume is confined as well.
+func (a *Archive) openArchive(ctx context.Context, path string) (*multiReaderAt, error) {
+	parts, err := splitParts(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find archive volumes: %w", err)
+	}
+	for _, part := range parts[1:] {
+		if _, err := a.securePath(part); err != nil {
+			a.audit(ctx, part, err)
+			return nil, err
+		}
+	}
+
+	f, ok := formatFor(path)
+	isZip := ok && f.container == containerZip
+	m := &multiReaderAt{}
+	for _, part := range parts {
+		file, err := os.Open(part)
+		if err != nil {
+			m.Close()
+			return nil, fmt.Errorf("failed to open archive: %w", err)
+		}
+		stat, err := file.Stat()
+		if err != nil {
+			file.Close()
+			m.Close()
+			return nil, fmt.Errorf("failed to stat archive: %w", err)
+		}
+		var mapped []byte
+		if a.mmap && isZip && stat.Size() >= mmapMinSize {
+			// Fall back to reading the file if it cannot be mapped.
+			mapped, _ = mmapFile(file, stat.Size())
+		}
+		m.files = append(m.files, file)
+		m.mapped = append(m.mapped, mapped)
+		m.offsets = append(m.offsets, m.size)
+		m.size += stat.Size()
+	}
+	return m, nil
+}
+
+// mmapMinSize is the minimum size of a zip volume mapped into memory with
+// WithMmap. Smaller files gain little from it.
+const mmapMinSize = 1 << 20
+
+// multiReaderAt is an io.ReaderAt over the concatenation of several files.
+// Reads may run concurrently with Close, which waits for them to finish
+// before unmapping the files, as reading a released mapping crashes the
+// process. Reads after Close fail.
+type multiReaderAt struct {
+	// mu is held for reading by ReadAt and for writing by Close.
+	mu     sync.RWMutex
+	closed bool
+	files  []*os.File
+	// mapped holds the memory mapping of each file, or nil if it is read
+	// with ReadAt.
+	mapped [][]byte
+	// offsets holds the start of each file within the concatenation.
+	offsets []int64
+	size    int64
+}
+
+func (m *multiReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	if off >= m.size {
+		return 0, io.EOF
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return 0, os.ErrClosed
+	}
+	// Start with the last file beginning at or before off.
+	i := sort.Search(len(m.offsets), func(i int) bool { return m.offsets[i] > off }) - 1
+	n := 0
+	for ; i < len(m.files) && n < len(p); i++ {
+		pos := off + int64(n) - m.offsets[i]
+		if i < len(m.mapped) && m.mapped[i] != nil {
+			n += copy(p[n:], m.mapped[i][pos:])
+			continue
+		}
+		k, err := m.files[i].ReadAt(p[n:], pos)
+		n += k
+		if err != nil && err != io.EOF {
+			return n, err
+		}
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Close closes all files.
+func (m *multiReaderAt) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	var errs []error
+	for _, b := range m.mapped {
+		if b != nil {
+			errs = append(errs, munmap(b))
+		}
+	}
+	for _, file := range m.files {
+		errs = append(errs, file.Close())
+	}
+	return errors.Join(errs...)
+}
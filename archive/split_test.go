@@ -0,0 +1,191 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestSplitZip(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "split.zip.001")
+
+	info, err := a.Info(context.Background(), InfoOptions{Path: path})
+	if err != nil {
+		t.Fatalf("Info failed: %v", err)
+	}
+	if info.Format != "zip" || info.Entries != 3 {
+		t.Errorf("expected zip with 3 entries, got %s with %d", info.Format, info.Entries)
+	}
+
+	result, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/baar.txt"}})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].Content != "das Pferd isst Gurkensalat\n" {
+		t.Errorf("unexpected extracted files: %v", result.Files)
+	}
+}
+
+func TestSplitZip_Hash(t *testing.T) {
+	a := newTestArchive(t)
+	split, err := a.List(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, "split.zip.001"), IncludeArchiveHash: true})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	whole, err := a.List(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, "test.zip"), IncludeArchiveHash: true})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if split.ArchiveSHA256 != whole.ArchiveSHA256 {
+		t.Errorf("expected the hash of the joined volumes %s, got %s", whole.ArchiveSHA256, split.ArchiveSHA256)
+	}
+}
+
+func TestSplitZip_VolumeOutsideWorkdir(t *testing.T) {
+	tmp, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to resolve temp dir: %v", err)
+	}
+	workdir := filepath.Join(tmp, "work")
+	if err := os.Mkdir(workdir, 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	for i, name := range []string{"split.zip.001", "split.zip.002"} {
+		data, err := os.ReadFile(filepath.Join("../testdata", name))
+		if err != nil {
+			t.Fatalf("failed to read fixture: %v", err)
+		}
+		dir := workdir
+		if i > 0 {
+			dir = tmp
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatalf("failed to write volume: %v", err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(tmp, "split.zip.002"), filepath.Join(workdir, "split.zip.002")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	a, err := New(workdir)
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	h := captureLogs(t)
+	_, err = a.List(context.Background(), ListOptions{Path: filepath.Join(workdir, "split.zip.001")})
+	if err == nil {
+		t.Fatal("expected error for volume outside the workdir, but got nil")
+	}
+	warnings := h.warnings()
+	if len(warnings) != 1 || warnings[0]["reason"] != reasonSymlinkEscape {
+		t.Errorf("expected 1 symlink escape audit record, got %v", warnings)
+	}
+}
+
+func TestMultiReaderAt(t *testing.T) {
+	dir := t.TempDir()
+	var files []*os.File
+	for i, content := range []string{"abc", "", "defg", "h"} {
+		path := filepath.Join(dir, string(rune('a'+i)))
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatalf("failed to open file: %v", err)
+		}
+		files = append(files, file)
+	}
+	m := &multiReaderAt{files: files, offsets: []int64{0, 3, 3, 7}, size: 8}
+	defer m.Close()
+
+	for _, tc := range []struct {
+		off  int64
+		n    int
+		want string
+		err  error
+	}{
+		{off: 0, n: 8, want: "abcdefgh"},
+		{off: 2, n: 3, want: "cde"},
+		{off: 3, n: 4, want: "defg"},
+		{off: 6, n: 4, want: "gh", err: io.EOF},
+		{off: 8, n: 1, want: "", err: io.EOF},
+	} {
+		buf := make([]byte, tc.n)
+		n, err := m.ReadAt(buf, tc.off)
+		if string(buf[:n]) != tc.want || err != tc.err {
+			t.Errorf("ReadAt(%d, %d) = %q, %v, want %q, %v", tc.n, tc.off, buf[:n], err, tc.want, tc.err)
+		}
+	}
+}
+
+func TestOpenArchive_Mmap(t *testing.T) {
+	dir := t.TempDir()
+	path, names := writeMmapBenchZip(t, dir, 2000, 1<<10)
+	a, err := New(dir, WithMmap(true))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	r, err := a.openArchive(context.Background(), path)
+	if err != nil {
+		t.Fatalf("openArchive failed: %v", err)
+	}
+	if runtime.GOOS == "linux" && r.mapped[0] == nil {
+		t.Error("expected the zip to be mapped")
+	}
+	r.Close()
+
+	result, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: names[:3]})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(result.Files) != 3 || result.Files[2].Content != string(benchContent(1<<10)) {
+		t.Errorf("unexpected files extracted from the mapped zip: %d", len(result.Files))
+	}
+
+	// Small archives are read as usual.
+	small := writeTestZip(t, dir, "small.zip", "foo/bazz", "bazz\n")
+	r, err = a.openArchive(context.Background(), small)
+	if err != nil {
+		t.Fatalf("openArchive failed: %v", err)
+	}
+	defer r.Close()
+	if r.mapped[0] != nil {
+		t.Error("expected the small zip not to be mapped")
+	}
+}
+
+func TestOpenArchive_MmapEntryTimeout(t *testing.T) {
+	dir := t.TempDir()
+	path, names := writeMmapBenchZip(t, dir, 2000, 1<<10)
+	a, err := New(dir, WithMmap(true), WithEntryTimeout(time.Nanosecond))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	for range 20 {
+		_, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: names[:3]})
+		if !errors.Is(err, errEntryTimeout) {
+			t.Fatalf("expected entry timeout error, got: %v", err)
+		}
+	}
+
+	// A mapped archive refuses reads once closed.
+	r, err := a.openArchive(context.Background(), path)
+	if err != nil {
+		t.Fatalf("openArchive failed: %v", err)
+	}
+	r.Close()
+	if _, err := r.ReadAt(make([]byte, 10), 0); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("expected a read after Close to fail, got: %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// StatOptions are the options for looking up a single entry of an archive.
+type StatOptions struct {
+	Path string `json:"path" jsonschema:"the path to the archive"`
+	File string `json:"file" jsonschema:"the name of the entry to look up"`
+}
+
+// StatArchiveFileArgs are the arguments for the stat_archive_file tool.
+type StatArchiveFileArgs StatOptions
+
+// StatArchiveFileResult holds the result of the stat_archive_file tool.
+type StatArchiveFileResult struct {
+	Found bool `json:"found"`
+	// File is the entry if it was found. Of an entry occurring several
+	// times, the last occurrence is returned, matching tar extraction
+	// semantics.
+	File *FileInfo `json:"file,omitempty"`
+	// Implied is set if the entry is a directory that has no entry of its
+	// own but is implied by the names of other entries.
+	Implied bool `json:"implied,omitempty"`
+}
+
+// trimDirSlash strips the trailing slash of directory names, so that they
+// match with or without it.
+func trimDirSlash(name string) string {
+	return strings.TrimSuffix(normalizeName(name), "/")
+}
+
+// Stat returns the metadata of a single entry of an archive. Only the
+// headers are read, so unlike Extract it is not bounded by the maximum file
+// size. An entry that is hidden by an ignore file is refused.
+func (a *Archive) Stat(ctx context.Context, opts StatOptions) (StatArchiveFileResult, error) {
+	if opts.File == "" {
+		return StatArchiveFileResult{}, errors.New("file is required")
+	}
+	name := trimDirSlash(opts.File)
+	var result StatArchiveFileResult
+	err := a.walkArchive(ctx, opts.Path, func(call *archiveCall, e *entry) error {
+		entryName := trimDirSlash(e.info.Name)
+		if entryName != name {
+			if !result.Found && strings.HasPrefix(entryName, name+"/") && !call.ignored(e.info) {
+				result.Implied = true
+			}
+			return nil
+		}
+		if call.ignored(e.info) {
+			return &rejectedError{
+				reason: reasonIgnored,
+				entry:  e.info.Name,
+				err:    fmt.Errorf("file %s is hidden by server policy", e.info.Name),
+			}
+		}
+		info := e.info
+		info.DataOffset = dataOffset(e)
+		result.Found = true
+		result.File = &info
+		result.Implied = false
+		return nil
+	})
+	if err != nil {
+		return StatArchiveFileResult{}, err
+	}
+	if result.Implied {
+		result.Found = true
+		result.File = &FileInfo{
+			Name:        name + "/",
+			Permissions: formatPermissions(fs.ModeDir|0o755, a.permissionFormat),
+			Type:        typeDir,
+		}
+	}
+	return result, nil
+}
+
+// StatArchiveFile looks up a single entry of an archive without reading its
+// content.
+func (a *Archive) StatArchiveFile(ctx context.Context, req *mcp.CallToolRequest, args StatArchiveFileArgs) (*mcp.CallToolResult, StatArchiveFileResult, error) {
+	return callTool(ctx, a, req, "stat_archive_file", args.Path, StatOptions(args), a.Stat)
+}
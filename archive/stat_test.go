@@ -0,0 +1,65 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestStat(t *testing.T) {
+	a := newTestArchive(t)
+	// Stat reads no content, so the maximum file size does not apply.
+	a.maxSize = 1
+	for _, archiveType := range []string{"test.cpio", "test.tar.gz", "test.zip"} {
+		t.Run(archiveType, func(t *testing.T) {
+			path := filepath.Join(a.Workdir, archiveType)
+			result, err := a.Stat(context.Background(), StatOptions{Path: path, File: "./foo/baar.txt"})
+			if err != nil {
+				t.Fatalf("Stat failed: %v", err)
+			}
+			if !result.Found || result.File.Name != "foo/baar.txt" || result.File.Size != 27 || result.File.Type != typeFile || result.Implied {
+				t.Errorf("unexpected result for foo/baar.txt: %+v, %+v", result, result.File)
+			}
+
+			for _, dir := range []string{"foo", "foo/"} {
+				result, err = a.Stat(context.Background(), StatOptions{Path: path, File: dir})
+				if err != nil {
+					t.Fatalf("Stat failed: %v", err)
+				}
+				if !result.Found || result.File.Type != typeDir || result.Implied {
+					t.Errorf("unexpected result for %s: %+v, %+v", dir, result, result.File)
+				}
+			}
+
+			result, err = a.Stat(context.Background(), StatOptions{Path: path, File: "foo/missing"})
+			if err != nil {
+				t.Fatalf("Stat failed: %v", err)
+			}
+			if result.Found || result.File != nil {
+				t.Errorf("expected foo/missing not to be found, got %+v", result)
+			}
+		})
+	}
+}
+
+func TestStat_ImpliedDir(t *testing.T) {
+	a := newTestArchive(t)
+	result, err := a.Stat(context.Background(), StatOptions{Path: filepath.Join(a.Workdir, "nodirs.zip"), File: "foo/sub"})
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if !result.Found || !result.Implied || result.File.Name != "foo/sub/" || result.File.Type != typeDir {
+		t.Errorf("expected implied directory foo/sub/, got %+v, %+v", result, result.File)
+	}
+}
+
+func TestStat_FileRequired(t *testing.T) {
+	a := newTestArchive(t)
+	if _, err := a.Stat(context.Background(), StatOptions{Path: filepath.Join(a.Workdir, "test.zip")}); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
@@ -0,0 +1,353 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ListStream writes the entries of an archive that pass the depth, type,
+// include and exclude filters of opts to w as newline-delimited JSON, one
+// FileInfo per line, without buffering the listing. A Limit of 0 writes all
+// entries. Deduplicate, top N and the directories implied for the "dir"
+// type filter require the complete listing and are not supported.
+func (a *Archive) ListStream(ctx context.Context, opts ListOptions, w io.Writer) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	if opts.Deduplicate {
+		return errors.New("deduplicate is not supported for streamed listings")
+	}
+	if opts.TopN > 0 {
+		return errors.New("top_n is not supported for streamed listings")
+	}
+	filter, err := newFileFilter(opts)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	written := 0
+	return a.walkArchive(ctx, opts.Path, func(call *archiveCall, e *entry) error {
+		if !withinDepth(e.info.Name, opts.Depth) || !filter.match(e.info) || call.ignored(e.info) {
+			return nil
+		}
+		info := e.info
+		info.DataOffset = dataOffset(e)
+		if !opts.IncludeXattrs {
+			info.Xattrs = nil
+		}
+		if err := enc.Encode(info); err != nil {
+			return err
+		}
+		written++
+		if opts.Limit > 0 && written >= opts.Limit {
+			return errStopWalk
+		}
+		return nil
+	})
+}
+
+// ExtractTo streams the content of the entry name of the archive at path to
+// w and returns the number of bytes written. Unlike Extract, the content is
+// not buffered and the maximum file size does not apply, so it suits large
+// entries. Only the call timeout limits the copy. Of an entry occurring
+// several times, the first occurrence is written. Nested archives are
+// addressed like in ListOptions.Path.
+func (a *Archive) ExtractTo(ctx context.Context, path, name string, w io.Writer) (int64, error) {
+	return a.openEntry(ctx, path, name, func(_ *entry, r io.Reader) (int64, error) {
+		return io.Copy(w, r)
+	})
+}
+
+// openEntry finds the entry name of the archive at path like ExtractTo and
+// passes it to fn together with a reader of its content, which is bound to
+// the call timeout. fn returns the number of bytes it wrote, which are
+// counted as extracted. Errors of fn other than a rangeError are wrapped as
+// failures to extract the entry.
+func (a *Archive) openEntry(ctx context.Context, path, name string, fn func(e *entry, r io.Reader) (int64, error)) (int64, error) {
+	outer, nested, err := splitNested(path)
+	if err != nil {
+		return 0, err
+	}
+	c, err := a.startWalk(ctx, outer)
+	if err != nil {
+		return 0, err
+	}
+	defer c.done()
+	walk, cleanup, err := a.nestedWalker(c.ctx, c.path, nested, c.ignored)
+	if err != nil {
+		return 0, a.callError(ctx, path, err)
+	}
+	defer cleanup()
+	var written int64
+	found := false
+	err = walk(func(e *entry) error {
+		if normalizeName(e.info.Name) != normalizeName(name) {
+			return nil
+		}
+		if c.ignored(e.info) {
+			return &rejectedError{
+				reason: reasonIgnored,
+				entry:  e.info.Name,
+				err:    fmt.Errorf("file %s is hidden by server policy", e.info.Name),
+			}
+		}
+		if e.info.Type == typeDir || e.info.Type == typeSymlink {
+			return fmt.Errorf("cannot extract the content of %s, which is a %s", e.info.Name, e.info.Type)
+		}
+		if err := checkContent(e.info); err != nil {
+			return err
+		}
+		if err := checkEntrySize(e); err != nil {
+			return err
+		}
+		found = true
+		rc, err := e.open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		written, err = fn(e, &ctxReader{ctx: c.ctx, r: rc})
+		var rerr *rangeError
+		if errors.As(err, &rerr) {
+			return err
+		}
+		if err != nil {
+			return fmt.Errorf("could not extract file %s from archive: %w", e.info.Name, err)
+		}
+		return errStopWalk
+	})
+	if err != nil {
+		return written, a.callError(ctx, path, err)
+	}
+	if !found {
+		return 0, &notFoundError{name: name}
+	}
+	a.metrics.addExtracted(written)
+	return written, nil
+}
+
+// notFoundError is returned by openEntry if the archive has no entry of the
+// requested name.
+type notFoundError struct {
+	name string
+}
+
+func (e *notFoundError) Error() string {
+	return fmt.Sprintf("file %s not found in archive", e.name)
+}
+
+// rangeError reports a byte range that does not overlap an entry of Size
+// bytes.
+type rangeError struct {
+	Size int64
+}
+
+func (e *rangeError) Error() string {
+	return fmt.Sprintf("requested range not satisfiable for an entry of %d bytes", e.Size)
+}
+
+// parseRange parses the value of a Range header for an entry of size bytes.
+// It returns the bytes [start, end) to send and whether they are a part of
+// the entry. The whole entry is sent for an empty or malformed header, a
+// header in another unit and multiple ranges, all of which servers may
+// ignore. A range that does not overlap the entry fails with a rangeError.
+func parseRange(header string, size int64) (start, end int64, partial bool, err error) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return 0, size, false, nil
+	}
+	first, last, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return 0, size, false, nil
+	}
+	if first == "" {
+		// A suffix range selects the last bytes.
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return 0, size, false, nil
+		}
+		if n == 0 || size == 0 {
+			return 0, 0, false, &rangeError{Size: size}
+		}
+		return max(0, size-n), size, true, nil
+	}
+	start, err = strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return 0, size, false, nil
+	}
+	end = size
+	if last != "" {
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < start {
+			return 0, size, false, nil
+		}
+		end = min(size, n+1)
+	}
+	if start >= size {
+		return 0, 0, false, &rangeError{Size: size}
+	}
+	return start, end, true, nil
+}
+
+// EntryHandler returns an HTTP handler serving the content of the entry
+// given by the file query parameter of the archive given by the path query
+// parameter, which are confined like the paths of the tools. A single byte
+// range of a Range header is honored with a partial response, and a range
+// beyond the end of the entry fails with status 416. Entries of unknown
+// size are always sent as a whole. As with ExtractTo, the maximum file size
+// does not apply.
+func (a *Archive) EntryHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		query := r.URL.Query()
+		path, name := query.Get("path"), query.Get("file")
+		if path == "" || name == "" {
+			http.Error(w, "the path and file query parameters are required", http.StatusBadRequest)
+			return
+		}
+
+		// started is set once the status was sent, after which errors can
+		// only abort the response.
+		started := false
+		_, err := a.openEntry(r.Context(), path, name, func(e *entry, rc io.Reader) (int64, error) {
+			h := w.Header()
+			h.Set("Content-Type", "application/octet-stream")
+			if e.sizeUnknown {
+				started = true
+				w.WriteHeader(http.StatusOK)
+				if r.Method == http.MethodHead {
+					return 0, nil
+				}
+				return io.Copy(w, rc)
+			}
+			size := e.info.Size
+			start, end, partial, err := parseRange(r.Header.Get("Range"), size)
+			if err != nil {
+				return 0, err
+			}
+			h.Set("Accept-Ranges", "bytes")
+			h.Set("Content-Length", strconv.FormatInt(end-start, 10))
+			started = true
+			if partial {
+				h.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end-1, size))
+				w.WriteHeader(http.StatusPartialContent)
+			} else {
+				w.WriteHeader(http.StatusOK)
+			}
+			if r.Method == http.MethodHead {
+				return 0, nil
+			}
+			// None of the formats allows seeking within an entry.
+			if _, err := io.CopyN(io.Discard, rc, start); err != nil {
+				return 0, err
+			}
+			return io.CopyN(w, rc, end-start)
+		})
+		if err == nil || started {
+			return
+		}
+		var rerr *rangeError
+		var rejected *rejectedError
+		var notFound *notFoundError
+		switch {
+		case errors.As(err, &rerr):
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", rerr.Size))
+			http.Error(w, err.Error(), http.StatusRequestedRangeNotSatisfiable)
+		case errors.As(err, &rejected):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.As(err, &notFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	})
+}
+
+// ListStreamHandler returns an HTTP handler streaming the listing of the
+// archive given by the path query parameter as newline-delimited JSON. The
+// depth, limit, include, exclude, pattern_syntax, type and include_xattrs
+// query parameters correspond to the fields of ListOptions.
+//
+// Errors before the first entry result in an error status. Errors later on
+// can no longer change the status and are reported as a final line holding
+// an object with a single "error" field.
+func (a *Archive) ListStreamHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		opts, err := listOptionsFromQuery(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		tw := &trackingWriter{w: w}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		err = a.ListStream(r.Context(), opts, tw)
+		if err == nil {
+			return
+		}
+		if tw.written {
+			json.NewEncoder(w).Encode(struct {
+				Error string `json:"error"`
+			}{err.Error()})
+			return
+		}
+		status := http.StatusBadRequest
+		var rejected *rejectedError
+		if errors.As(err, &rejected) {
+			status = http.StatusForbidden
+		}
+		http.Error(w, err.Error(), status)
+	})
+}
+
+// listOptionsFromQuery parses the query parameters of ListStreamHandler.
+func listOptionsFromQuery(query url.Values) (ListOptions, error) {
+	opts := ListOptions{
+		Path:           query.Get("path"),
+		IncludePattern: query.Get("include"),
+		ExcludePattern: query.Get("exclude"),
+		PatternSyntax:  query.Get("pattern_syntax"),
+		TypeFilter:     query.Get("type"),
+	}
+	var err error
+	for name, dst := range map[string]*int{"depth": &opts.Depth, "limit": &opts.Limit} {
+		if v := query.Get(name); v != "" {
+			if *dst, err = strconv.Atoi(v); err != nil {
+				return ListOptions{}, fmt.Errorf("invalid %s: %w", name, err)
+			}
+		}
+	}
+	if v := query.Get("include_xattrs"); v != "" {
+		if opts.IncludeXattrs, err = strconv.ParseBool(v); err != nil {
+			return ListOptions{}, fmt.Errorf("invalid include_xattrs: %w", err)
+		}
+	}
+	return opts, nil
+}
+
+// trackingWriter records whether anything was written to w.
+type trackingWriter struct {
+	w       io.Writer
+	written bool
+}
+
+func (t *trackingWriter) Write(p []byte) (int, error) {
+	t.written = true
+	return t.w.Write(p)
+}
@@ -0,0 +1,198 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestListStream(t *testing.T) {
+	a := newTestArchive(t)
+	var buf bytes.Buffer
+	err := a.ListStream(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, "test.tar.gz"), TypeFilter: typeFile}, &buf)
+	if err != nil {
+		t.Fatalf("ListStream failed: %v", err)
+	}
+	var names []string
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var info FileInfo
+		if err := json.Unmarshal(scanner.Bytes(), &info); err != nil {
+			t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+		}
+		names = append(names, info.Name)
+	}
+	if got := strings.Join(names, ","); got != "foo/baar.txt,foo/bazz" {
+		t.Errorf("unexpected streamed files: %s", got)
+	}
+}
+
+func TestListStream_Limit(t *testing.T) {
+	a := newTestArchive(t)
+	var buf bytes.Buffer
+	err := a.ListStream(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, "test.zip"), Limit: 2}, &buf)
+	if err != nil {
+		t.Fatalf("ListStream failed: %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 2 {
+		t.Errorf("expected 2 lines, got %d", lines)
+	}
+}
+
+func TestListStreamHandler(t *testing.T) {
+	a := newTestArchive(t)
+	srv := httptest.NewServer(a.ListStreamHandler())
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		name   string
+		query  url.Values
+		status int
+		lines  int
+	}{
+		{"ok", url.Values{"path": {filepath.Join(a.Workdir, "test.cpio")}}, http.StatusOK, 3},
+		{"depth", url.Values{"path": {filepath.Join(a.Workdir, "test.cpio")}, "depth": {"1"}}, http.StatusOK, 1},
+		{"invalid depth", url.Values{"path": {filepath.Join(a.Workdir, "test.cpio")}, "depth": {"x"}}, http.StatusBadRequest, 0},
+		{"traversal", url.Values{"path": {filepath.Join(a.Workdir, "../archive/archive.go")}}, http.StatusForbidden, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := http.Get(srv.URL + "?" + tc.query.Encode())
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tc.status {
+				t.Fatalf("expected status %d, got %d", tc.status, resp.StatusCode)
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+			if ct := resp.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+				t.Errorf("unexpected content type %s", ct)
+			}
+			lines := 0
+			scanner := bufio.NewScanner(resp.Body)
+			for scanner.Scan() {
+				lines++
+			}
+			if lines != tc.lines {
+				t.Errorf("expected %d lines, got %d", tc.lines, lines)
+			}
+		})
+	}
+}
+
+func TestExtractTo(t *testing.T) {
+	a := newTestArchive(t)
+	for _, archiveType := range []string{"test.cpio", "test.tar.gz", "test.zip"} {
+		var buf bytes.Buffer
+		n, err := a.ExtractTo(context.Background(), filepath.Join(a.Workdir, archiveType), "./foo/baar.txt", &buf)
+		if err != nil {
+			t.Fatalf("ExtractTo %s failed: %v", archiveType, err)
+		}
+		if n != 27 || buf.String() != "das Pferd isst Gurkensalat\n" {
+			t.Errorf("unexpected content of %s: %d bytes %q", archiveType, n, buf.String())
+		}
+	}
+
+	path := filepath.Join(a.Workdir, "test.tar.gz")
+	if _, err := a.ExtractTo(context.Background(), path, "foo/missing", io.Discard); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected an error for a missing file, got %v", err)
+	}
+	if _, err := a.ExtractTo(context.Background(), path, "foo/", io.Discard); err == nil {
+		t.Error("expected an error for a directory")
+	}
+}
+
+func TestExtractTo_Large(t *testing.T) {
+	dir := t.TempDir()
+	a, err := New(dir)
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	content := strings.Repeat("0123456789abcdef", 64<<10)
+	path := writeSyntheticTar(t, dir, map[string]string{"large.bin": content})
+
+	// Extract refuses the file, which exceeds the maximum file size.
+	if _, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"large.bin"}}); err == nil {
+		t.Fatal("expected Extract to refuse the large file")
+	}
+	n, err := a.ExtractTo(context.Background(), path, "large.bin", io.Discard)
+	if err != nil {
+		t.Fatalf("ExtractTo failed: %v", err)
+	}
+	if n != int64(len(content)) {
+		t.Errorf("expected %d bytes written, got %d", len(content), n)
+	}
+}
+
+func TestEntryHandler(t *testing.T) {
+	a := newTestArchive(t)
+	srv := httptest.NewServer(a.EntryHandler())
+	defer srv.Close()
+
+	// foo/baar.txt holds "das Pferd isst Gurkensalat\n", 27 bytes.
+	query := url.Values{"path": {filepath.Join(a.Workdir, "test.tar.gz")}, "file": {"foo/baar.txt"}}
+	for _, tc := range []struct {
+		name         string
+		query        url.Values
+		rangeHeader  string
+		status       int
+		body         string
+		contentRange string
+	}{
+		{"whole", query, "", http.StatusOK, "das Pferd isst Gurkensalat\n", ""},
+		{"range", query, "bytes=4-8", http.StatusPartialContent, "Pferd", "bytes 4-8/27"},
+		{"open range", query, "bytes=15-", http.StatusPartialContent, "Gurkensalat\n", "bytes 15-26/27"},
+		{"suffix", query, "bytes=-4", http.StatusPartialContent, "lat\n", "bytes 23-26/27"},
+		{"end beyond size", query, "bytes=20-100", http.StatusPartialContent, "nsalat\n", "bytes 20-26/27"},
+		{"multiple ranges", query, "bytes=0-2,4-8", http.StatusOK, "das Pferd isst Gurkensalat\n", ""},
+		{"beyond size", query, "bytes=27-", http.StatusRequestedRangeNotSatisfiable, "", "bytes */27"},
+		{"missing file", url.Values{"path": query["path"], "file": {"foo/missing"}}, "", http.StatusNotFound, "", ""},
+		{"missing parameter", url.Values{"path": query["path"]}, "", http.StatusBadRequest, "", ""},
+		{"traversal", url.Values{"path": {filepath.Join(a.Workdir, "../archive/archive.go")}, "file": {"foo"}}, "", http.StatusForbidden, "", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, srv.URL+"?"+tc.query.Encode(), nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			if tc.rangeHeader != "" {
+				req.Header.Set("Range", tc.rangeHeader)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tc.status {
+				t.Fatalf("expected status %d, got %d", tc.status, resp.StatusCode)
+			}
+			if got := resp.Header.Get("Content-Range"); got != tc.contentRange {
+				t.Errorf("expected Content-Range %q, got %q", tc.contentRange, got)
+			}
+			if tc.body == "" {
+				return
+			}
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("failed to read body: %v", err)
+			}
+			if string(body) != tc.body {
+				t.Errorf("expected body %q, got %q", tc.body, body)
+			}
+		})
+	}
+}
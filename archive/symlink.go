@@ -0,0 +1,70 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"fmt"
+	"io"
+)
+
+// Symlink policies, see WithSymlinkPolicy.
+const (
+	// SymlinksReport lists and extracts symlink entries with their
+	// target.
+	SymlinksReport = "report"
+	// SymlinksSkip leaves symlink entries out as if the archive did not
+	// contain them.
+	SymlinksSkip = "skip"
+	// SymlinksReject refuses archives containing any symlink entry.
+	SymlinksReject = "reject"
+)
+
+func validSymlinkPolicy(policy string) bool {
+	return policy == SymlinksReport || policy == SymlinksSkip || policy == SymlinksReject
+}
+
+// setLinkTarget sets FileInfo.LinkTarget of a symlink entry. Zip archives
+// store the target as the content of the entry, which is read then. A
+// target longer than maxCpioLinkTarget is left unset.
+func setLinkTarget(e *entry) error {
+	if e.info.Type != typeSymlink {
+		return nil
+	}
+	if e.linkTarget == "" && !e.sizeUnknown && e.info.Size > 0 && e.info.Size <= maxCpioLinkTarget {
+		rc, err := e.open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		target, err := io.ReadAll(io.LimitReader(rc, maxCpioLinkTarget))
+		if err != nil {
+			return fmt.Errorf("could not read the target of symlink %s: %w", e.info.Name, err)
+		}
+		e.linkTarget = string(target)
+	}
+	e.info.LinkTarget = e.linkTarget
+	return nil
+}
+
+// applySymlinkPolicy wraps fn to skip or refuse symlink entries as
+// configured with WithSymlinkPolicy.
+func (a *Archive) applySymlinkPolicy(fn walkFunc) walkFunc {
+	if a.symlinkPolicy == SymlinksReport {
+		return fn
+	}
+	return func(e *entry) error {
+		if e.info.Type != typeSymlink {
+			return fn(e)
+		}
+		if a.symlinkPolicy == SymlinksSkip {
+			return nil
+		}
+		return &rejectedError{
+			reason: reasonSymlink,
+			entry:  e.info.Name,
+			err:    fmt.Errorf("archive contains symlink %s, which the server refuses", e.info.Name),
+		}
+	}
+}
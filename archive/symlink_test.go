@@ -0,0 +1,123 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// listedNames returns the names of files and the link target of each
+// symlink among them.
+func listedNames(files []FileInfo) (names []string, targets map[string]string) {
+	targets = make(map[string]string)
+	for _, f := range files {
+		names = append(names, f.Name)
+		if f.Type == typeSymlink {
+			targets[f.Name] = f.LinkTarget
+		}
+	}
+	return names, targets
+}
+
+func TestSymlinkPolicy_Report(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "symlink.tar.gz")
+	result, err := a.List(context.Background(), ListOptions{Path: path})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	names, targets := listedNames(result.Files)
+	if len(names) != 3 || targets["foo/link"] != "baar.txt" {
+		t.Errorf("expected foo/link listed with its target, got %v %v", names, targets)
+	}
+}
+
+func TestSymlinkPolicy_Skip(t *testing.T) {
+	a, err := New("../testdata", WithSymlinkPolicy(SymlinksSkip))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := filepath.Join(a.Workdir, "symlink.tar.gz")
+	result, err := a.List(context.Background(), ListOptions{Path: path})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	names, targets := listedNames(result.Files)
+	if len(names) != 2 || len(targets) != 0 {
+		t.Errorf("expected foo/link to be left out, got %v", names)
+	}
+	extracted, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/link", "foo/baar.txt"}})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(extracted.Files) != 1 || extracted.Files[0].Name != "foo/baar.txt" {
+		t.Errorf("expected only foo/baar.txt to be extracted, got %+v", extracted.Files)
+	}
+}
+
+func TestSymlinkPolicy_Reject(t *testing.T) {
+	a, err := New("../testdata", WithSymlinkPolicy(SymlinksReject))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	h := captureLogs(t)
+	_, err = a.List(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, "symlink.tar.gz")})
+	var rerr *rejectedError
+	if !errors.As(err, &rerr) || rerr.reason != reasonSymlink || rerr.entry != "foo/link" {
+		t.Fatalf("expected the symlink to be rejected, got %v", err)
+	}
+	_, err = a.Extract(context.Background(), ExtractOptions{Path: filepath.Join(a.Workdir, "symlink.tar.gz"), Files: []string{"foo/baar.txt"}})
+	if !errors.As(err, &rerr) || rerr.reason != reasonSymlink {
+		t.Fatalf("expected the extraction to be rejected, got %v", err)
+	}
+	if warnings := h.warnings(); len(warnings) != 1 || warnings[0]["reason"] != reasonSymlink {
+		t.Errorf("expected an audit record for the rejected extraction, got %v", warnings)
+	}
+	if _, err := a.List(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, "test.tar.gz")}); err != nil {
+		t.Errorf("expected an archive without symlinks to be listed, got %v", err)
+	}
+
+	if _, err := New("../testdata", WithSymlinkPolicy("follow")); err == nil {
+		t.Error("expected an invalid symlink policy to fail")
+	}
+}
+
+func TestSymlinkPolicy_ZipTarget(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	h := &zip.FileHeader{Name: "foo/link"}
+	h.SetMode(fs.ModeSymlink | 0o777)
+	w, err := zw.CreateHeader(h)
+	if err != nil {
+		t.Fatalf("failed to create zip entry: %v", err)
+	}
+	w.Write([]byte("baar.txt"))
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to write zip: %v", err)
+	}
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := filepath.Join(a.Workdir, "symlink.zip")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+
+	result, err := a.List(context.Background(), ListOptions{Path: path})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if _, targets := listedNames(result.Files); targets["foo/link"] != "baar.txt" {
+		t.Errorf("expected the zip symlink target to be listed, got %v", targets)
+	}
+}
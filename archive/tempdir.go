@@ -0,0 +1,43 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"fmt"
+	"os"
+)
+
+// checkTempDir verifies that dir is an existing directory in which files
+// can be created.
+func checkTempDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("invalid temp directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("invalid temp directory: %s is not a directory", dir)
+	}
+	f, err := os.CreateTemp(dir, ".mcp-archive-check-*")
+	if err != nil {
+		return fmt.Errorf("temp directory %s is not writable: %w", dir, err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// createTemp creates a scratch file named after pattern, as for
+// os.CreateTemp, in the temp directory set by WithTempDir or else the
+// system one. The file is only accessible to the owner. The returned
+// cleanup closes and removes it.
+func (a *Archive) createTemp(pattern string) (*os.File, func(), error) {
+	f, err := os.CreateTemp(a.tempDir, "mcp-archive-"+pattern)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create scratch file: %w", err)
+	}
+	return f, func() {
+		f.Close()
+		os.Remove(f.Name())
+	}, nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithTempDir_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	for _, tmp := range []string{filepath.Join(dir, "missing"), file} {
+		if _, err := New(dir, WithTempDir(tmp)); err == nil {
+			t.Errorf("expected an error for temp directory %s", tmp)
+		}
+	}
+}
+
+func TestCreateTemp(t *testing.T) {
+	tmp := t.TempDir()
+	a, err := New(t.TempDir(), WithTempDir(tmp))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	f, cleanup, err := a.createTemp("test-*")
+	if err != nil {
+		t.Fatalf("createTemp failed: %v", err)
+	}
+	if filepath.Dir(f.Name()) != tmp {
+		t.Errorf("scratch file %s not created in %s", f.Name(), tmp)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatalf("failed to stat scratch file: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("expected permissions 0600, got %o", perm)
+	}
+	cleanup()
+	if _, err := os.Stat(f.Name()); !os.IsNotExist(err) {
+		t.Errorf("scratch file not removed: %v", err)
+	}
+}
+
+func TestNested_TempDirCleanedUp(t *testing.T) {
+	tmp := t.TempDir()
+	a, err := New("../testdata", WithTempDir(tmp))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	if _, err := a.List(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, "outer.zip!test.tar.gz")}); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if _, err := a.List(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, "outer.zip!missing.tar.gz")}); err == nil {
+		t.Fatal("expected an error for a missing nested archive")
+	}
+	entries, err := os.ReadDir(tmp)
+	if err != nil {
+		t.Fatalf("failed to read temp directory: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("scratch files left behind: %v", entries)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// truncatedError is returned by readEntry if the archive ends before the
+// content of an entry. It carries the content read up to that point.
+type truncatedError struct {
+	// file holds the content that could be read.
+	file File
+	// size is the size the entry header declares, or -1 if unknown.
+	size int64
+}
+
+func (e *truncatedError) Error() string {
+	return fmt.Sprintf("could not read file %s from archive: %v", e.file.Name, io.ErrUnexpectedEOF)
+}
+
+func (e *truncatedError) Unwrap() error {
+	return io.ErrUnexpectedEOF
+}
+
+// truncatedFile returns the truncatedError for the entry e of which only
+// buf could be read, declared to be size bytes or -1 if unknown.
+func truncatedFile(e *entry, buf []byte, size int64) error {
+	return &truncatedError{
+		file: File{
+			Name:        e.info.Name,
+			Size:        int64(len(buf)),
+			Permissions: e.info.Permissions,
+			Type:        e.info.Type,
+			RawContent:  buf,
+			mode:        e.mode,
+		},
+		size: size,
+	}
+}
+
+// note describes the missing content for File.Note.
+func (e *truncatedError) note() string {
+	if e.size < 0 {
+		return fmt.Sprintf("the archive is truncated: only the first %d bytes of the file could be read", len(e.file.RawContent))
+	}
+	return fmt.Sprintf("the archive is truncated: only %d of %d bytes of the file could be read", len(e.file.RawContent), e.size)
+}
+
+// toleratingTruncation wraps read to return the partial content of an entry
+// cut short by the end of the archive, marked as truncated, instead of
+// failing. It records that the archive ended, see truncatedWalk.
+func (x *extraction) toleratingTruncation(read func(*entry) (File, error)) func(*entry) (File, error) {
+	return func(e *entry) (File, error) {
+		file, err := read(e)
+		var terr *truncatedError
+		if !errors.As(err, &terr) {
+			return file, err
+		}
+		x.truncated = true
+		file = terr.file
+		file.Truncated = true
+		file.Note = terr.note()
+		return file, nil
+	}
+}
+
+// truncatedWalk wraps walk to end successfully if the archive ends early
+// after the partial content of a truncated entry was returned, as the next
+// header cannot be read then.
+func (x *extraction) truncatedWalk(walk walker) walker {
+	return func(fn walkFunc) error {
+		err := walk(fn)
+		if x.truncated && errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		return err
+	}
+}
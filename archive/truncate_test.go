@@ -0,0 +1,80 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTruncatedTar writes a tar.gz archive whose tar stream ends 100 bytes
+// into the content of big.txt, the last of its entries. The gzip stream
+// itself is intact.
+func writeTruncatedTar(t *testing.T, dir string) string {
+	var tarBuf bytes.Buffer
+	tw := tar.NewWriter(&tarBuf)
+	for _, f := range []struct{ name, content string }{
+		{"small.txt", "complete\n"},
+		{"big.txt", strings.Repeat("a", 1000)},
+	} {
+		if err := tw.WriteHeader(&tar.Header{Name: f.name, Mode: 0o644, Typeflag: tar.TypeReg, Size: int64(len(f.content))}); err != nil {
+			t.Fatalf("failed to write tar header: %v", err)
+		}
+		tw.Write([]byte(f.content))
+	}
+	tw.Close()
+	// The two entries take a header and a content block each.
+	data := tarBuf.Bytes()[:3*tarBlockSize+100]
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(data)
+	zw.Close()
+	path := filepath.Join(dir, "truncated.tar.gz")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+	return path
+}
+
+func TestExtract_TolerateTruncation(t *testing.T) {
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	path := writeTruncatedTar(t, a.Workdir)
+	files := []string{"small.txt", "big.txt"}
+
+	_, err = a.Extract(context.Background(), ExtractOptions{Path: path, Files: files})
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected an unexpected EOF error by default, got %v", err)
+	}
+
+	result, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: files, TolerateTruncation: true})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(result.Files) != 2 {
+		t.Fatalf("expected 2 files, got %+v", result.Files)
+	}
+	small, big := result.Files[0], result.Files[1]
+	if small.Truncated || small.Content != "complete\n" {
+		t.Errorf("expected small.txt to be complete, got %+v", small)
+	}
+	if !big.Truncated || big.Content != strings.Repeat("a", 100) || big.Size != 100 {
+		t.Errorf("expected the first 100 bytes of big.txt marked as truncated, got %+v", big)
+	}
+	if want := "only 100 of 1000 bytes"; !strings.Contains(big.Note, want) {
+		t.Errorf("expected note containing %q, got %q", want, big.Note)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+)
+
+// maxAutoUnwrap caps the number of extra compression layers removed by
+// auto-unwrap, so that chains of nested decompression bombs are refused.
+const maxAutoUnwrap = 3
+
+// compressionSuffixes are the suffixes of extra compression layers accepted
+// on top of the name of a compressed stream format, as in
+// archive.tar.gz.gz.
+var compressionSuffixes = []struct {
+	suffix     string
+	decompress decompressor
+}{
+	{".gz", gunzip},
+	{".bz2", bunzip2},
+	{".xz", unxz},
+	{".zst", unzstd},
+	{".sz", unsnappy},
+}
+
+// nestedFormatFor returns the format for a path with extra compression
+// suffixes. The outermost layer is decompressed according to its suffix,
+// the inner ones are detected by unwrap.
+func nestedFormatFor(path string) (format, bool) {
+	var suffixes []string
+	var outer decompressor
+	for {
+		trimmed := false
+		for _, c := range compressionSuffixes {
+			if strings.HasSuffix(path, c.suffix) {
+				path = strings.TrimSuffix(path, c.suffix)
+				suffixes = append([]string{c.suffix}, suffixes...)
+				if outer == nil {
+					outer = c.decompress
+				}
+				trimmed = true
+				break
+			}
+		}
+		if !trimmed || len(suffixes) > maxAutoUnwrap {
+			return format{}, false
+		}
+		if f, ok := exactFormatFor(path); ok && f.container != containerSniffed {
+			if f.decompress == nil {
+				return format{}, false
+			}
+			f.base = f.name
+			f.name += strings.Join(suffixes, "")
+			f.decompress = outer
+			return f, true
+		}
+	}
+}
+
+// compressionMagic identifies compressed streams by their first bytes.
+var compressionMagic = []struct {
+	magic      []byte
+	decompress decompressor
+}{
+	{[]byte{0x1f, 0x8b, 0x08}, gunzip},
+	{[]byte{0xfd, '7', 'z', 'X', 'Z', 0x00}, unxz},
+	{[]byte{0x28, 0xb5, 0x2f, 0xfd}, unzstd},
+	// The stream identifier chunk of the Snappy framing format.
+	{[]byte{0xff, 0x06, 0x00, 0x00, 's', 'N', 'a', 'P', 'p', 'Y'}, unsnappy},
+	{[]byte("BZh"), bunzip2},
+}
+
+// sniffCompression returns the decompressor for the stream buffered in br,
+// or nil if it is not compressed.
+func sniffCompression(br *bufio.Reader) decompressor {
+	header, _ := br.Peek(10)
+	for _, m := range compressionMagic {
+		if !bytes.HasPrefix(header, m.magic) {
+			continue
+		}
+		if m.magic[0] == 'B' && !isBzip2Header(header) {
+			continue
+		}
+		return m.decompress
+	}
+	return nil
+}
+
+// isBzip2Header checks that the block size digit following the bzip2 magic
+// is followed by the magic of the first block or of the end of an empty
+// stream.
+func isBzip2Header(header []byte) bool {
+	if len(header) < 6 {
+		return false
+	}
+	block := header[4:6]
+	return header[3] >= '1' && header[3] <= '9' &&
+		(bytes.Equal(block, []byte{0x31, 0x41}) || bytes.Equal(block, []byte{0x17, 0x72}))
+}
+
+// errNestedCompression is returned if a decompressed stream is compressed
+// again more often than auto-unwrap removes.
+var errNestedCompression = errors.New("archive is compressed more than once")
+
+// unwrap removes up to layers extra compression layers from stream, which
+// has already been decompressed once. The returned closers must be closed
+// once the stream is no longer needed.
+func unwrap(stream io.Reader, layers int, limits decoderLimits) (io.Reader, []io.Closer, error) {
+	var closers []io.Closer
+	for i := 0; ; i++ {
+		br := bufio.NewReader(stream)
+		d := sniffCompression(br)
+		if d == nil {
+			return br, closers, nil
+		}
+		if i >= layers {
+			return nil, closers, errNestedCompression
+		}
+		dr, err := d(br, limits)
+		if err != nil {
+			return nil, closers, err
+		}
+		closers = append(closers, dr)
+		stream = dr
+	}
+}
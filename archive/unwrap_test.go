@@ -0,0 +1,131 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormatFor_Nested(t *testing.T) {
+	for path, want := range map[string]string{
+		"a.tar.gz.gz":  "tar.gz.gz",
+		"a.tar.gz.bz2": "tar.gz.bz2",
+		"a.cpio.xz.gz": "cpio.xz.gz",
+		// Anything else ending in .gz is read as a single compressed
+		// file.
+		"a.zip.gz":             "gz",
+		"a.txt.gz":             "gz",
+		"a.tar.gz.gz.gz.gz.gz": "gz",
+		"a.zip.bz2":            "",
+	} {
+		f, ok := formatFor(path)
+		if ok != (want != "") || f.name != want {
+			t.Errorf("formatFor(%s) = %q, %v, want %q", path, f.name, ok, want)
+		}
+	}
+}
+
+func TestAutoUnwrap(t *testing.T) {
+	a, err := New("../testdata", WithAutoUnwrap(true))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	for _, name := range []string{"nested.tar.gz.gz", "nested.tar.gz.bz2"} {
+		path := filepath.Join(a.Workdir, name)
+		result, err := a.Extract(context.Background(), ExtractOptions{Path: path, Files: []string{"foo/baar.txt"}})
+		if err != nil {
+			t.Fatalf("Extract failed for %s: %v", name, err)
+		}
+		if len(result.Files) != 1 || result.Files[0].Content != "das Pferd isst Gurkensalat\n" {
+			t.Errorf("unexpected extracted files for %s: %v", name, result.Files)
+		}
+	}
+}
+
+func TestAutoUnwrap_Disabled(t *testing.T) {
+	// Without auto-unwrap, a stream compressed twice is read as a single
+	// compressed file holding the inner compressed stream.
+	var inner, outer bytes.Buffer
+	zw := gzip.NewWriter(&inner)
+	zw.Write([]byte("hello"))
+	zw.Close()
+	zw = gzip.NewWriter(&outer)
+	zw.Name = "inner.gz"
+	zw.Write(inner.Bytes())
+	zw.Close()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "x.gz"), outer.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+	a, err := New(dir)
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	result, err := a.List(context.Background(), ListOptions{Path: filepath.Join(dir, "x.gz")})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].Name != "inner.gz" {
+		t.Errorf("expected the single member inner.gz, got %v", result.Files)
+	}
+
+	a = newTestArchive(t)
+	result, err = a.List(context.Background(), ListOptions{Path: filepath.Join(a.Workdir, "nested.tar.gz.gz")})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(result.Files) != 1 {
+		t.Errorf("expected nested.tar.gz.gz to list a single member, got %v", result.Files)
+	}
+}
+
+func TestAutoUnwrap_Depth(t *testing.T) {
+	fixture, err := os.ReadFile("../testdata/test.tar.gz")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	dir := t.TempDir()
+	a, err := New(dir, WithAutoUnwrap(true))
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	// Up to three extra layers are removed from an archive that keeps the
+	// plain .tar.gz name.
+	for layers, wantErr := range map[int]bool{3: false, 4: true} {
+		data := fixture
+		for range layers {
+			var buf bytes.Buffer
+			zw := gzip.NewWriter(&buf)
+			zw.Write(data)
+			zw.Close()
+			data = buf.Bytes()
+		}
+		path := filepath.Join(dir, "deep.tar.gz")
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatalf("failed to write archive: %v", err)
+		}
+		_, err = a.List(context.Background(), ListOptions{Path: path})
+		if gotErr := errors.Is(err, errNestedCompression); gotErr != wantErr {
+			t.Errorf("with %d extra layers: expected nested compression error %v, got: %v", layers, wantErr, err)
+		}
+	}
+}
+
+func TestSniffCompression_Snappy(t *testing.T) {
+	data, err := os.ReadFile("../testdata/test.tar.sz")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	if d := sniffCompression(bufio.NewReader(bytes.NewReader(data))); d == nil {
+		t.Error("expected the Snappy stream identifier to be detected")
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// VerifyOptions are the options for checking the entries of an archive
+// against an expected list of paths.
+type VerifyOptions struct {
+	Path     string   `json:"path" jsonschema:"the path to the archive"`
+	Expected []string `json:"expected" jsonschema:"the paths the archive is expected to contain"`
+	// Exact also fails the verification if the archive contains entries
+	// that are not expected.
+	Exact bool `json:"exact,omitempty" jsonschema:"fail if the archive contains any entry that is not expected"`
+}
+
+// VerifyContentsArgs are the arguments for the verify_contents tool.
+type VerifyContentsArgs VerifyOptions
+
+// VerifyContentsResult holds the result of the verify_contents tool. The
+// names are sorted.
+type VerifyContentsResult struct {
+	// Passed reports whether no expected path is missing and, with
+	// VerifyOptions.Exact, no entry is unexpected.
+	Passed bool `json:"passed"`
+	// Missing are the expected paths the archive does not contain.
+	Missing []string `json:"missing"`
+	// Unexpected are the entries of the archive that are not expected.
+	Unexpected []string `json:"unexpected"`
+	// Matched is the number of expected paths the archive contains.
+	Matched int `json:"matched"`
+}
+
+// verifyKey returns the name under which an entry or expected path is
+// compared, ignoring a leading "./" and a trailing slash.
+func verifyKey(name string) string {
+	return strings.TrimSuffix(normalizeName(name), "/")
+}
+
+// Verify checks the entries of an archive against an expected list of
+// paths. Directories implied by the paths of other entries count as
+// present, and directories that are parents of expected paths are not
+// reported as unexpected, so that the result does not depend on whether
+// the archive stores directory entries.
+func (a *Archive) Verify(ctx context.Context, opts VerifyOptions) (VerifyContentsResult, error) {
+	files, err := a.listArchive(ctx, opts.Path)
+	if err != nil {
+		return VerifyContentsResult{}, err
+	}
+	files = append(files, impliedDirs(files, a.permissionFormat)...)
+	return verifyEntries(files, opts.Expected, opts.Exact), nil
+}
+
+// verifyEntries compares the entries of an archive with the expected paths.
+func verifyEntries(files []FileInfo, expected []string, exact bool) VerifyContentsResult {
+	want := make(map[string]bool, len(expected))
+	parents := make(map[string]bool)
+	for _, name := range expected {
+		key := verifyKey(name)
+		want[key] = true
+		for dir := path.Dir(key); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			parents[dir] = true
+		}
+	}
+
+	result := VerifyContentsResult{Missing: []string{}, Unexpected: []string{}}
+	present := make(map[string]bool, len(files))
+	for _, file := range files {
+		key := verifyKey(file.Name)
+		if present[key] {
+			continue
+		}
+		present[key] = true
+		switch {
+		case want[key]:
+			result.Matched++
+		case file.Type == typeDir && parents[key]:
+		default:
+			result.Unexpected = append(result.Unexpected, file.Name)
+		}
+	}
+	for name := range want {
+		if !present[name] {
+			result.Missing = append(result.Missing, name)
+		}
+	}
+	slices.Sort(result.Missing)
+	slices.Sort(result.Unexpected)
+	result.Passed = len(result.Missing) == 0 && (!exact || len(result.Unexpected) == 0)
+	return result
+}
+
+// VerifyContents checks that an archive contains the expected paths.
+func (a *Archive) VerifyContents(ctx context.Context, req *mcp.CallToolRequest, args VerifyContentsArgs) (*mcp.CallToolResult, VerifyContentsResult, error) {
+	return callTool(ctx, a, req, "verify_contents", args.Path, VerifyOptions(args), a.Verify)
+}
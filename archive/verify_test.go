@@ -0,0 +1,63 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	a := newTestArchive(t)
+	for _, tc := range []struct {
+		name       string
+		archive    string
+		expected   []string
+		exact      bool
+		passed     bool
+		missing    []string
+		unexpected []string
+		matched    int
+	}{
+		{"all present", "test.tar.gz", []string{"foo/", "foo/baar.txt", "./foo/bazz"}, true, true, nil, nil, 3},
+		{"parent dirs not unexpected", "test.zip", []string{"foo/baar.txt", "foo/bazz"}, true, true, nil, nil, 2},
+		{"missing", "test.zip", []string{"foo/baar.txt", "foo/missing"}, false, false, []string{"foo/missing"}, []string{"foo/bazz"}, 1},
+		{"unexpected", "test.zip", []string{"foo/baar.txt"}, false, true, nil, []string{"foo/bazz"}, 1},
+		{"unexpected exact", "test.zip", []string{"foo/baar.txt"}, true, false, nil, []string{"foo/bazz"}, 1},
+		{"implied dirs", "nodirs.zip", []string{"foo/sub", "foo/baar.txt", "foo/sub/bazz"}, true, true, nil, nil, 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := a.Verify(context.Background(), VerifyOptions{
+				Path:     filepath.Join(a.Workdir, tc.archive),
+				Expected: tc.expected,
+				Exact:    tc.exact,
+			})
+			if err != nil {
+				t.Fatalf("Verify failed: %v", err)
+			}
+			if result.Passed != tc.passed {
+				t.Errorf("expected passed %v, got %v", tc.passed, result.Passed)
+			}
+			if !slices.Equal(result.Missing, append([]string{}, tc.missing...)) {
+				t.Errorf("expected missing %v, got %v", tc.missing, result.Missing)
+			}
+			if !slices.Equal(result.Unexpected, append([]string{}, tc.unexpected...)) {
+				t.Errorf("expected unexpected %v, got %v", tc.unexpected, result.Unexpected)
+			}
+			if result.Matched != tc.matched {
+				t.Errorf("expected %d matched, got %d", tc.matched, result.Matched)
+			}
+		})
+	}
+}
+
+func TestVerify_UnsupportedFormat(t *testing.T) {
+	a := newTestArchive(t)
+	if _, err := a.Verify(context.Background(), VerifyOptions{Path: filepath.Join(a.Workdir, "Makefile")}); err == nil {
+		t.Error("expected an unsupported format to fail")
+	}
+}
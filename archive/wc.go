@@ -0,0 +1,150 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultMaxScanBytes is the default cap on the bytes decompressed by Count.
+const defaultMaxScanBytes = 1 << 30
+
+// WCOptions are the options for counting the lines, words and bytes of the
+// files in an archive.
+type WCOptions struct {
+	Path           string   `json:"path" jsonschema:"the path to the archive"`
+	Files          []string `json:"files,omitempty" jsonschema:"the files to count. If not set, all regular files passing the include and exclude patterns are counted"`
+	IncludePattern string   `json:"include,omitempty" jsonschema:"an optional regular expression, or glob with pattern_syntax glob, to include files"`
+	ExcludePattern string   `json:"exclude,omitempty" jsonschema:"an optional regular expression, or glob with pattern_syntax glob, to exclude files"`
+	PatternSyntax  string   `json:"pattern_syntax,omitempty" jsonschema:"the syntax of include and exclude: regexp or glob. If not set, it will default to regexp"`
+	MaxBytes       int64    `json:"max_bytes,omitempty" jsonschema:"the maximum number of bytes to scan across all files. If not set, it will default to 1 GiB"`
+}
+
+// ArchiveWCArgs are the arguments for the archive_wc tool.
+type ArchiveWCArgs WCOptions
+
+// WCCounts are the counts of wc.
+type WCCounts struct {
+	Lines int64 `json:"lines"`
+	Words int64 `json:"words"`
+	Bytes int64 `json:"bytes"`
+}
+
+// WCFile holds the counts of a single file.
+type WCFile struct {
+	Name string `json:"name"`
+	WCCounts
+}
+
+// ArchiveWCResult holds the result of the archive_wc tool.
+type ArchiveWCResult struct {
+	Files []WCFile `json:"files"`
+	Total WCCounts `json:"total"`
+}
+
+// wordCounter counts lines, words and bytes written to it like wc in the C
+// locale: words are separated by ASCII white space.
+type wordCounter struct {
+	WCCounts
+	inWord bool
+}
+
+func (c *wordCounter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		switch b {
+		case '\n':
+			c.Lines++
+			c.inWord = false
+		case ' ', '\t', '\v', '\f', '\r':
+			c.inWord = false
+		default:
+			if !c.inWord {
+				c.Words++
+				c.inWord = true
+			}
+		}
+	}
+	c.Bytes += int64(len(p))
+	return len(p), nil
+}
+
+// Count streams the selected regular files of an archive and returns their
+// line, word and byte counts. Since no content is returned, files are not
+// limited by the maximum file size, but the total bytes scanned are capped.
+func (a *Archive) Count(ctx context.Context, opts WCOptions) (ArchiveWCResult, error) {
+	filter, err := newFileFilter(ListOptions{IncludePattern: opts.IncludePattern, ExcludePattern: opts.ExcludePattern, PatternSyntax: opts.PatternSyntax, TypeFilter: typeFile})
+	if err != nil {
+		return ArchiveWCResult{}, err
+	}
+	if opts.MaxBytes < 0 {
+		return ArchiveWCResult{}, fmt.Errorf("invalid maximum bytes %d", opts.MaxBytes)
+	}
+	maxBytes := opts.MaxBytes
+	if maxBytes == 0 {
+		maxBytes = defaultMaxScanBytes
+	}
+	wanted := make(map[string]bool)
+	for _, f := range opts.Files {
+		wanted[normalizeName(f)] = true
+	}
+
+	result := ArchiveWCResult{Files: []WCFile{}}
+	err = a.walkArchive(ctx, opts.Path, func(call *archiveCall, e *entry) error {
+		if !filter.match(e.info) {
+			return nil
+		}
+		if len(wanted) > 0 && !wanted[normalizeName(e.info.Name)] {
+			return nil
+		}
+		if call.ignored(e.info) {
+			if len(wanted) == 0 {
+				return nil
+			}
+			return &rejectedError{
+				reason: reasonIgnored,
+				entry:  e.info.Name,
+				err:    fmt.Errorf("file %s is hidden by server policy", e.info.Name),
+			}
+		}
+
+		var c wordCounter
+		remaining := maxBytes - result.Total.Bytes
+		err := a.withinEntryTimeout(e, func() error {
+			rc, err := e.open()
+			if err != nil {
+				return err
+			}
+			defer rc.Close()
+			if _, err := io.Copy(&c, io.LimitReader(rc, remaining+1)); err != nil {
+				return fmt.Errorf("could not read file %s from archive: %w", e.info.Name, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if c.Bytes > remaining {
+			return fmt.Errorf("scanned more than the maximum of %d bytes", maxBytes)
+		}
+		result.Files = append(result.Files, WCFile{Name: e.info.Name, WCCounts: c.WCCounts})
+		result.Total.Lines += c.Lines
+		result.Total.Words += c.Words
+		result.Total.Bytes += c.Bytes
+		return nil
+	})
+	if err != nil {
+		return ArchiveWCResult{}, err
+	}
+	return result, nil
+}
+
+// ArchiveWC counts the lines, words and bytes of the files in an archive.
+func (a *Archive) ArchiveWC(ctx context.Context, req *mcp.CallToolRequest, args ArchiveWCArgs) (*mcp.CallToolResult, ArchiveWCResult, error) {
+	return callTool(ctx, a, req, "archive_wc", args.Path, WCOptions(args), a.Count)
+}
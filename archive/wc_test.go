@@ -0,0 +1,88 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWordCounter(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		want  WCCounts
+	}{
+		{"", WCCounts{}},
+		{"one", WCCounts{Lines: 0, Words: 1, Bytes: 3}},
+		{"das Pferd isst Gurkensalat\n", WCCounts{Lines: 1, Words: 4, Bytes: 27}},
+		{"  a\tb \r\n\nc", WCCounts{Lines: 2, Words: 3, Bytes: 10}},
+	} {
+		var c wordCounter
+		// Split the input to check that words spanning writes count once.
+		half := len(tc.input) / 2
+		c.Write([]byte(tc.input[:half]))
+		c.Write([]byte(tc.input[half:]))
+		if c.WCCounts != tc.want {
+			t.Errorf("counts of %q = %+v, want %+v", tc.input, c.WCCounts, tc.want)
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+	a := newTestArchive(t)
+	// Counting is not limited by the maximum file size.
+	a.maxSize = 1
+	for _, f := range formats {
+		t.Run(f.name, func(t *testing.T) {
+			result, err := a.Count(context.Background(), WCOptions{Path: filepath.Join(a.Workdir, "test."+f.name)})
+			if err != nil {
+				t.Fatalf("Count failed: %v", err)
+			}
+			if len(result.Files) != 2 {
+				t.Fatalf("expected 2 files, got %v", result.Files)
+			}
+			if want := (WCCounts{Lines: 2, Words: 5, Bytes: 32}); result.Total != want {
+				t.Errorf("expected total %+v, got %+v", want, result.Total)
+			}
+		})
+	}
+}
+
+func TestCount_Selection(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "test.tar.gz")
+	for _, tc := range []struct {
+		name string
+		opts WCOptions
+		want string
+	}{
+		{"files", WCOptions{Files: []string{"./foo/bazz", "foo/missing"}}, "foo/bazz"},
+		{"include", WCOptions{IncludePattern: `\.txt$`}, "foo/baar.txt"},
+		{"exclude", WCOptions{ExcludePattern: `\.txt$`}, "foo/bazz"},
+	} {
+		tc.opts.Path = path
+		result, err := a.Count(context.Background(), tc.opts)
+		if err != nil {
+			t.Fatalf("%s: Count failed: %v", tc.name, err)
+		}
+		if len(result.Files) != 1 || result.Files[0].Name != tc.want {
+			t.Errorf("%s: expected %s, got %v", tc.name, tc.want, result.Files)
+		}
+	}
+}
+
+func TestCount_MaxBytes(t *testing.T) {
+	a := newTestArchive(t)
+	path := filepath.Join(a.Workdir, "test.tar.gz")
+	if _, err := a.Count(context.Background(), WCOptions{Path: path, MaxBytes: 32}); err != nil {
+		t.Errorf("Count failed at the cap: %v", err)
+	}
+	_, err := a.Count(context.Background(), WCOptions{Path: path, MaxBytes: 31})
+	if err == nil || !strings.Contains(err.Error(), "more than the maximum of 31 bytes") {
+		t.Errorf("expected scan cap error, got: %v", err)
+	}
+}
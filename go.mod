@@ -4,8 +4,11 @@ go 1.23.0
 
 require (
 	github.com/cavaliergopher/cpio v1.0.1
+	github.com/klauspost/compress v1.18.0
 	github.com/modelcontextprotocol/go-sdk v0.8.0
 	github.com/ulikunitz/xz v0.5.15
+	golang.org/x/text v0.22.0
+	golang.org/x/time v0.9.0
 )
 
 require (
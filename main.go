@@ -4,48 +4,245 @@ import (
 	"context"
 	"flag"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"regexp"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/openSUSE/mcp-archive/archive"
 )
 
+// validToolPrefix matches the characters allowed in MCP tool names.
+var validToolPrefix = regexp.MustCompile(`^[A-Za-z0-9_.-]*$`)
+
 var (
-	httpAddr = flag.String("http", "", "if set, use streamable HTTP at this address, instead of stdin/stdout")
-	workdir  = flag.String("workdir", ".", "the working directory for the archive tools")
+	httpAddr           = flag.String("http", "", "if set, use streamable HTTP at this address, instead of stdin/stdout")
+	workdir            = flag.String("workdir", ".", "the working directory for the archive tools; several directories or glob patterns may be separated by the OS path list separator")
+	timeout            = flag.Duration("timeout", 0, "the maximum duration of a single tool call; 0 disables the limit")
+	entryTimeout       = flag.Duration("entry-timeout", 0, "the maximum duration of reading a single archive entry; 0 disables the limit")
+	decoderMaxMemory   = flag.Uint64("decoder-max-memory", 128<<20, "the maximum dictionary or window size in bytes the xz and zstd decompressors may allocate; 0 disables the limit")
+	decoderConcurrency = flag.Int("decoder-concurrency", 1, "the number of goroutines the zstd decompressor may use")
+	rateLimit          = flag.Float64("rate-limit", 0, "if set, the maximum number of tool calls per second and session, and of requests to /list.ndjson and /entry per second and client address, in HTTP mode")
+	rateBurst          = flag.Int("rate-burst", 10, "the number of tool calls or requests a session or client may burst above -rate-limit")
+	readOnly           = flag.Bool("read-only", true, "if set, tools that write to the filesystem are not registered")
+	maxEntries         = flag.Int("max-entries", 1_000_000, "the maximum number of entries scanned per archive; 0 disables the limit")
+	maxNameLength      = flag.Int("max-name-length", 4096, "the maximum length in bytes of an entry name; 0 disables the limit")
+	maxPathDepth       = flag.Int("max-path-depth", 256, "the maximum number of path segments of an entry name; 0 disables the limit")
+	maxConcurrent      = flag.Int("max-concurrent", 2*runtime.GOMAXPROCS(0), "the maximum number of archive operations running at the same time; further calls wait until their timeout; 0 disables the limit")
+	autoUnwrap         = flag.Bool("auto-unwrap", false, "if set, remove up to 3 extra compression layers from accidentally double compressed tar and cpio archives")
+	symlinkPolicy      = flag.String("symlink-policy", "report", "how symlink entries inside archives are handled: \"report\" lists them with their target, \"skip\" leaves them out and \"reject\" refuses archives containing any")
+	permissionFormat   = flag.String("permission-format", "symbolic", "the format of entry permissions, \"symbolic\" like -rw-r--r-- or \"octal\" like 0644")
+	filenameEncoding   = flag.String("filename-encoding", "", "the character set, such as IBM437 or Shift_JIS, of entry names that are not UTF-8; names of zip entries lacking the UTF-8 flag default to IBM437")
+	maxResponseBytes   = flag.Int64("max-response-bytes", 8<<20, "the maximum estimated size in bytes of an extraction result; larger results fail with a message telling how many files fit; 0 disables the limit")
+	maxNestedSize      = flag.Int64("max-nested-size", 64<<20, "the maximum total size in bytes of the nested archives copied to scratch files for a path like outer.zip!inner.tar.gz")
+	mmap               = flag.Bool("mmap", false, "if set, memory-map zip archives of at least 1 MiB for faster random access; archives must not be truncated while in use")
+	parallelThreshold  = flag.Int("parallel-threshold", 10000, "the number of entries from which zip central directories are decoded and listings filtered in parallel; smaller archives are processed sequentially, 0 always runs in parallel")
+	readBufferSize     = flag.Int("read-buffer-size", 0, "the size in bytes of the read buffers around tar and cpio archive files and their decompressors, e.g. 1048576 for large archives; 0 leaves reads unbuffered")
+	tolerant           = flag.Bool("tolerant", false, "if set, skip junk bytes such as a byte order mark found before the magic of an archive within its first KiB")
+	strictExtension    = flag.Bool("strict-extension", false, "if set, refuse archives whose content does not start with the magic of the format claimed by their extension")
+	tmpdir             = flag.String("tmpdir", "", "the directory for scratch files, which must exist and be writable; defaults to the system temp directory")
+	metrics            = flag.Bool("metrics", false, "if set, serve Prometheus metrics of tool calls, extracted bytes, decompression times and rejections at /metrics in HTTP mode")
+	readySelfTest      = flag.Bool("ready-self-test", true, "if set, /readyz lists a sample archive of each supported format in HTTP mode")
+	shutdownTimeout    = flag.Duration("shutdown-timeout", 10*time.Second, "the maximum duration to wait for in-flight requests on SIGINT or SIGTERM in HTTP mode before closing all connections")
+	toolPrefix         = flag.String("tool-prefix", "", "a prefix for the names of all registered tools, such as \"arc_\" for arc_list_archive_files, to avoid collisions with the tools of other MCP servers")
+	formats            = flag.String("formats", "", "comma-separated list of the enabled archive formats, such as zip,tar.gz; archives of other formats are rejected; all supported formats are enabled if empty")
+	deny               = flag.String("deny", "", "comma-separated list of path prefixes that are denied even inside the working directory")
 )
 
 func main() {
 	flag.Parse()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 	// Create a server with a single tool that says "Hi".
 	server := mcp.NewServer(&mcp.Implementation{Name: "greeter"}, nil)
 
-	archiver, err := archive.New(*workdir)
+	opts := []archive.Option{
+		archive.WithTimeout(*timeout),
+		archive.WithEntryTimeout(*entryTimeout),
+		archive.WithWritable(!*readOnly),
+		archive.WithDecoderLimits(*decoderMaxMemory, *decoderConcurrency),
+		archive.WithEntryLimits(*maxEntries, *maxNameLength),
+		archive.WithMaxPathDepth(*maxPathDepth),
+		archive.WithMaxConcurrent(*maxConcurrent),
+		archive.WithAutoUnwrap(*autoUnwrap),
+		archive.WithPermissionFormat(*permissionFormat),
+		archive.WithSymlinkPolicy(*symlinkPolicy),
+		archive.WithFilenameEncoding(*filenameEncoding),
+		archive.WithMaxResponseBytes(*maxResponseBytes),
+		archive.WithMaxNestedSize(*maxNestedSize),
+		archive.WithTempDir(*tmpdir),
+		archive.WithMmap(*mmap),
+		archive.WithReadBufferSize(*readBufferSize),
+		archive.WithParallelThreshold(*parallelThreshold),
+		archive.WithTolerant(*tolerant),
+		archive.WithStrictExtension(*strictExtension),
+	}
+	var registry *archive.Metrics
+	if *metrics && *httpAddr != "" {
+		registry = archive.NewMetrics()
+		opts = append(opts, archive.WithMetrics(registry))
+	}
+	if *formats != "" {
+		opts = append(opts, archive.WithFormats(strings.Split(*formats, ",")...))
+	}
+	if *deny != "" {
+		opts = append(opts, archive.WithDeny(strings.Split(*deny, ",")...))
+	}
+	if !validToolPrefix.MatchString(*toolPrefix) {
+		log.Fatalf("invalid tool prefix %q: only letters, digits, '_', '-' and '.' are allowed", *toolPrefix)
+	}
+	archiver, err := archive.New(*workdir, opts...)
 	if err != nil {
 		log.Fatalf("failed to create archive instance: %v", err)
 	}
 
 	// Add the tools from the hello package.
 	mcp.AddTool(server, &mcp.Tool{
-		Name:        "list_archive_files",
+		Name:        *toolPrefix + "list_archive_files",
 		Description: "list the files in an archive",
 	}, archiver.ListArchiveFiles)
 	mcp.AddTool(server, &mcp.Tool{
-		Name:        "extract_archive_files",
+		Name:        *toolPrefix + "extract_archive_files",
 		Description: "extract files from an archive",
 	}, archiver.ExtractArchiveFiles)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        *toolPrefix + "stat_archive_file",
+		Description: "show the size, permissions and type of a single file in an archive, or that it does not exist, without reading its content",
+	}, archiver.StatArchiveFile)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        *toolPrefix + "extract_matching",
+		Description: "extract all files of an archive whose content matches a regular expression",
+	}, archiver.ExtractMatchingFiles)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        *toolPrefix + "extract_grep",
+		Description: "return the lines matching a regular expression in the files of an archive, with optional context lines like grep -C, e.g. for log triage without extracting whole files",
+	}, archiver.ExtractGrep)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        *toolPrefix + "extract_concat",
+		Description: "return the content of several files of an archive concatenated in the requested order, e.g. to reassemble a file split into parts inside the archive",
+	}, archiver.ExtractConcat)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        *toolPrefix + "extract_by_hash",
+		Description: "return the files of an archive whose content has one of the given SHA256 hashes, for retrieving known blobs without knowing their names",
+	}, archiver.ExtractFilesByHash)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        *toolPrefix + "archive_wc",
+		Description: "count the lines, words and bytes of files in an archive like wc, without returning their content",
+	}, archiver.ArchiveWC)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        *toolPrefix + "largest_dirs",
+		Description: "list the directories of an archive holding the most data by the total size of the files below them, reading only the headers",
+	}, archiver.ListLargestDirs)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        *toolPrefix + "probe_archive",
+		Description: "cheaply check the format of an archive by its magic bytes, whether it is encrypted or solid and, where the format indexes its entries, their number, before using the heavier tools",
+	}, archiver.ProbeArchive)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        *toolPrefix + "archive_info",
+		Description: "show the format, the number of entries and metadata such as the zip comment or tar PAX global headers of an archive",
+	}, archiver.ArchiveInfo)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        *toolPrefix + "archive_manifest",
+		Description: "return the manifest of a jar, Python wheel or npm package, such as META-INF/MANIFEST.MF, *.dist-info/METADATA or package/package.json, without knowing its path",
+	}, archiver.ArchiveManifest)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        *toolPrefix + "list_changed",
+		Description: "list the files of an archive whose SHA256 differs from a baseline of hashes by name, and the files added or removed relative to it",
+	}, archiver.ListChanged)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        *toolPrefix + "verify_contents",
+		Description: "check that an archive contains an expected list of paths, reporting the missing and unexpected ones, e.g. to assert the contents of a build artifact",
+	}, archiver.VerifyContents)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        *toolPrefix + "list_archives",
+		Description: "list the files of all archives matching an absolute glob pattern",
+	}, archiver.ListArchives)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        *toolPrefix + "resolve_path",
+		Description: "check whether a path is accepted by the working directory confinement and show the resolved path or the reason for the rejection",
+	}, archiver.ResolvePath)
+	// Write-capable tools must only be registered if !*readOnly.
+	if !*readOnly {
+		mcp.AddTool(server, &mcp.Tool{
+			Name:        *toolPrefix + "extract_to_disk",
+			Description: "write the files and directories of an archive, with their permissions, to a directory in the working directory",
+		}, archiver.ExtractArchiveToDisk)
+	}
 
 	if *httpAddr != "" {
+		limit := func(h http.Handler) http.Handler { return h }
+		if *rateLimit > 0 {
+			limiter := archive.NewRateLimiter(*rateLimit, *rateBurst)
+			server.AddReceivingMiddleware(limiter.Middleware)
+			limit = limiter.HTTPMiddleware
+		}
 		handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
 			return server
 		}, nil)
-		log.Printf("MCP handler listening at %s", *httpAddr)
-		log.Fatal(http.ListenAndServe(*httpAddr, handler))
+		mux := http.NewServeMux()
+		mux.Handle("/list.ndjson", limit(archiver.ListStreamHandler()))
+		mux.Handle("/entry", limit(archiver.EntryHandler()))
+		if registry != nil {
+			mux.Handle("/metrics", registry.Handler())
+		}
+		mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("ok\n"))
+		})
+		mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+			if *readySelfTest {
+				if err := archiver.SelfTest(r.Context()); err != nil {
+					http.Error(w, err.Error(), http.StatusServiceUnavailable)
+					return
+				}
+			}
+			w.Write([]byte("ok\n"))
+		})
+		mux.Handle("/", handler)
+		ln, err := net.Listen("tcp", *httpAddr)
+		if err != nil {
+			log.Fatalf("failed to listen: %v", err)
+		}
+		log.Printf("MCP handler listening at %s", ln.Addr())
+		if err := serveHTTP(ctx, ln, mux, *shutdownTimeout); err != nil {
+			log.Fatal(err)
+		}
 	} else {
 		t := &mcp.LoggingTransport{Transport: &mcp.StdioTransport{}, Writer: os.Stderr}
-		if err := server.Run(context.Background(), t); err != nil {
+		if err := server.Run(ctx, t); err != nil {
 			log.Printf("Server failed: %v", err)
 		}
 	}
 }
+
+// serveHTTP serves handler on ln until ctx is done. It then stops accepting
+// connections and waits up to drain for in-flight requests, such as running
+// tool calls, to finish.
+func serveHTTP(ctx context.Context, ln net.Listener, handler http.Handler, drain time.Duration) error {
+	srv := &http.Server{Handler: handler}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	log.Printf("shutting down, waiting up to %s for in-flight requests", drain)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), drain)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		// Open event streams of idle sessions never finish on their own.
+		log.Printf("closing connections still open after %s: %v", drain, err)
+		srv.Close()
+	}
+	if err := <-errc; err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
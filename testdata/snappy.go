@@ -0,0 +1,23 @@
+//go:build ignore
+
+// snappy compresses stdin to stdout in the Snappy framing format, for which
+// no command line tool is commonly available.
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+
+	"github.com/klauspost/compress/snappy"
+)
+
+func main() {
+	w := snappy.NewBufferedWriter(os.Stdout)
+	if _, err := io.Copy(w, os.Stdin); err != nil {
+		log.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		log.Fatal(err)
+	}
+}